@@ -0,0 +1,29 @@
+// Package api хранит OpenAPI спецификацию HTTP API, встроенную в бинарник
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed docs.html
+var DocsHTML []byte
+
+// SpecJSON переводит спецификацию в JSON, в репозитории она хранится в yaml ради читаемости
+func SpecJSON() ([]byte, error) {
+	var spec map[string]any
+	if err := yaml.Unmarshal(specYAML, &spec); err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal openapi spec: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Тест SpecJSON - спецификация разбирается, а каждый $ref указывает на существующий компонент
+func TestSpecJSONRefsResolve(t *testing.T) {
+	// test
+	data, err := SpecJSON()
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("unmarshal - %v", err)
+	}
+	refs := regexp.MustCompile(`"\$ref":"#/([^"]+)"`).FindAllStringSubmatch(string(data), -1)
+	if len(refs) == 0 {
+		t.Fatalf("expected $ref in spec")
+	}
+	for _, ref := range refs {
+		var node any = spec
+		for _, part := range strings.Split(ref[1], "/") {
+			object, ok := node.(map[string]any)
+			if !ok {
+				node = nil
+				break
+			}
+			node = object[part]
+		}
+		if node == nil {
+			t.Errorf("broken $ref #/%v", ref[1])
+		}
+	}
+}
@@ -1,14 +1,15 @@
 package main
 
 import (
-	"chopper/internal/build"
+	"chopper/internal/cli"
+	"os"
 
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
-	if err := build.Run(); err != nil {
+	if err := cli.Run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
 		logrus.Error(err)
-		return
+		os.Exit(1)
 	}
 }
@@ -2,81 +2,158 @@ package build
 
 import (
 	"chopper/internal/config"
+	grpcdelivery "chopper/internal/delivery/grpc"
+	"chopper/internal/domain"
+	"chopper/internal/logger"
+	"chopper/internal/metrics"
 	"chopper/internal/middleware"
+	"chopper/internal/notifier"
 	"chopper/internal/repository"
+	"chopper/internal/scheduler"
 	"chopper/internal/security"
 	"chopper/internal/server"
+	"chopper/internal/tracing"
 	"chopper/internal/usecase"
+	"chopper/internal/validation"
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"golang.org/x/time/rate"
-
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sirupsen/logrus"
 )
 
-var migrationsFilePath string = "file:///app/migrations"
-
-func Run() error {
-	fmt.Println("step1")
-	// загрузка всех конфигов
-	serverConfig, jwtConfig, databaseConfig, rateLimiterConfig, err := config.ConfigsLoad()
+// Run запускает сервер; configPath - yaml или toml файл, поверх него env переменные
+func Run(configPath string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	log, err := logger.New(cfg.Logger)
 	if err != nil {
 		return err
 	}
+	log.WithFields(logrus.Fields{
+		"address":  cfg.Server.Address,
+		"mode":     cfg.Server.ServerMode,
+		"database": cfg.Database.String(),
+	}).Info("config loaded")
 
-	fmt.Println(serverConfig, databaseConfig)
-	fmt.Println("step2")
-	// подключение к бд
-	connStr := fmt.Sprintf("postgres://%v:%v@%v:%v/%v?sslmode=disable", databaseConfig.User, databaseConfig.Password, databaseConfig.Host, databaseConfig.Port, databaseConfig.Name)
+	// правила валидации запросов и политика паролей
+	requestValidator, err := validation.New(cfg.Security.PasswordPolicy)
+	if err != nil {
+		return fmt.Errorf("init validation: %w", err)
+	}
+	binding.Validator = requestValidator
+
+	// трейсинг
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
-	pool, err := pgxpool.New(ctx, connStr)
+	tracerProvider, err := tracing.New(ctx, cfg.Tracing)
 	if err != nil {
-		return err
+		return fmt.Errorf("init tracing: %w", err)
 	}
-	if err := pool.Ping(ctx); err != nil {
+	defer func() {
+		// досылаем оставшиеся спаны после остановки сервера
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer shutdownCancel()
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).Error("tracer provider shutdown failed")
+		}
+	}()
+
+	// подключение к бд
+	pool, err := ConnectDatabase(ctx, cfg.Database)
+	if err != nil {
 		return err
 	}
 	defer pool.Close()
+	log.Info("database connected")
 
-	fmt.Println("step3")
-	// миграции
-	m, err := migrate.New(migrationsFilePath, connStr)
-	fmt.Println("MIGRATE RESULT:", err)
+	// миграции встроены в бинарник; в режиме check сервер только сверяет версию схемы
+	schemaVersion, err := prepareSchema(cfg.Database)
 	if err != nil {
 		return err
 	}
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
-	}
+	log.WithFields(logrus.Fields{
+		"schema_version": schemaVersion.Current,
+		"mode":           cfg.Database.Migrations,
+	}).Info("database schema is up to date")
 
-	fmt.Println("step4")
 	// создание слоев
-	userRepo := repository.NewUserRepositoryRealization(pool)
-	jwtService := security.NewJwt(jwtConfig.Secret, jwtConfig.ExpirationTime, jwtConfig.Issuer, jwtConfig.Audience)
-	// сделать конфиг с кост
-	cost := 10
-	passwordHasher := security.NewPasswordHasher(cost)
+	appMetrics := metrics.New(pool)
+	jwtService := security.NewJwt(cfg.JWT.Secret, cfg.JWT.ExpirationTime, cfg.JWT.Issuer, cfg.JWT.Audience)
 	uuidGenerator := security.NewUUIDGenerator()
-	userService := usecase.NewUserService(userRepo, jwtService, passwordHasher, uuidGenerator)
-	authMiddleware := middleware.NewAuthMiddleware(jwtService)
-	dailyNotesRepo := repository.NewDailyNotesRepositoryRealization(pool)
-	dailyNotesService := usecase.NewDailyNotesService(dailyNotesRepo, uuidGenerator)
+	userService := NewUserService(cfg, pool, appMetrics)
+	apiTokenRepo := repository.NewApiTokenRepositoryRealization(pool)
+	apiTokenGenerator := security.NewApiTokenGenerator()
+	apiTokenService := usecase.NewApiTokenService(apiTokenRepo, apiTokenGenerator, uuidGenerator)
+	authenticator := usecase.NewAuthenticator(jwtService, apiTokenService, userService)
+	authMiddleware := middleware.NewAuthMiddleware(authenticator)
+	languageMiddleware := middleware.NewLanguageMiddleware(cfg.I18n.DefaultLanguage)
 	alertRepository := repository.NewAlertRepositoryRealization(pool)
-	alertService := usecase.NewAlertServcie(alertRepository)
-	rateLimiter := middleware.NewRateLimiter(rate.Every(rateLimiterConfig.Rate), rateLimiterConfig.Burst)
+	alertService := usecase.NewAlertServcie(alertRepository, cfg.Alert, appMetrics)
+	userEventRepository := repository.NewUserEventRepositoryRealization(pool)
+	userEventListener := repository.NewUserEventListenerRealization(pool)
+	transactor := repository.NewTransactorRealization(pool)
+	outboxRepository := repository.NewOutboxRepositoryRealization(pool)
+	userEventService := usecase.NewUserEventService(userEventRepository, userEventListener, alertService, transactor, outboxRepository, cfg.Events, log)
+	dailyNotesRepo := repository.NewDailyNotesRepositoryRealization(pool)
+	entryHistoryRepo := repository.NewEntryHistoryRepositoryRealization(pool)
+	dailyNotesService := usecase.NewDailyNotesService(dailyNotesRepo, uuidGenerator, appMetrics, transactor, outboxRepository, entryHistoryRepo)
+	var limiterStore middleware.LimiterStore = middleware.NewMemoryLimiterStore()
+	if cfg.RateLimiter.Store == domain.LimiterStorePostgres {
+		limiterStore = repository.NewRateLimitRepositoryRealization(pool)
+	}
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimiter.Policies, limiterStore, cfg.RateLimiter.IdleTimeout, appMetrics)
+	idempotency := middleware.NewIdempotency(repository.NewIdempotencyRepositoryRealization(pool), cfg.Idempotency.TTL)
+	requestLogger := middleware.NewRequestLogger(log)
+	corsMiddleware := middleware.NewCORSMiddleware(cfg.CORS)
+	reloader := NewReloader(configPath, cfg, log, rateLimiter, alertService, corsMiddleware)
+	healthRepository := repository.NewHealthRepositoryRealization(pool)
+	healthService := usecase.NewHealthService(healthRepository, schemaVersion.Expected, time.Second*2)
+
+	// gRPC для внутренних сервисов поверх тех же usecase
+	grpcServer := grpcdelivery.NewServer(authenticator, requestValidator, userService, dailyNotesService, alertService, rateLimiter, cfg.RateLimiter.Groups, cfg.I18n.DefaultLanguage, log)
+
+	// события других реплик через LISTEN/NOTIFY, до остановки сервера
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	defer stopEvents()
+	go userEventService.Run(eventsCtx)
+
+	// фоновые задачи; при нескольких репликах каждую выполняет одна из них
+	jobs := scheduler.New(repository.NewAdvisoryLockerRealization(pool), log)
+	// доменные события из outbox: подписчики регистрируются здесь, до запуска планировщика
+	outboxRelay := usecase.NewOutboxRelay(outboxRepository, cfg.Outbox)
+	outboxRelay.Subscribe("user_events", userEventService.HandleEntryEvent, domain.DomainEventEntryCreated, domain.DomainEventEntryChanged)
+	outboxRelay.Subscribe("user_events_alerts", userEventService.HandleAlertEvent, domain.DomainEventAlertRaised)
+	jobs.Add(scheduler.Job{Name: "outbox-relay", Interval: cfg.Outbox.PollInterval, Run: outboxRelay.RelayPending})
+	jobs.Add(scheduler.Job{Name: "outbox-cleanup", Interval: time.Hour, Run: outboxRelay.Cleanup})
+	jobs.Add(scheduler.Job{Name: "idempotency-cleanup", Interval: time.Hour, Run: idempotency.Cleanup})
+	if cfg.Reminders.Enabled {
+		reminderRepository := repository.NewReminderRepositoryRealization(pool)
+		reminderService := usecase.NewReminderService(reminderRepository, NewReminderNotifier(cfg), cfg.Reminders, appMetrics)
+		jobs.Add(scheduler.Job{Name: "reminders", Interval: cfg.Reminders.CheckInterval, Run: reminderService.SendDue})
+	}
+	jobs.Start()
 
-	fmt.Println("step5")
 	// запуск сервера
-	server := server.NewServer(serverConfig.Address, serverConfig.ReadTimeout, serverConfig.WriteTimeout, serverConfig.IdleTimeout, serverConfig.TimeToShutdown, serverConfig.ServerMode, userService, dailyNotesService, alertService, authMiddleware, rateLimiter)
-	if err := server.StartServer(); err != nil {
-		return err
+	server := server.NewServer(cfg.Server, userService, dailyNotesService, alertService, apiTokenService, userEventService, healthService, authMiddleware, languageMiddleware, rateLimiter, cfg.RateLimiter.Groups, idempotency, requestLogger, corsMiddleware, reloader, grpcServer, appMetrics, log)
+	serverErr := server.StartServer()
+
+	// задачи останавливаются после сервера: начатая доставка доводится до конца, но не дольше time_to_shutdown
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), cfg.Server.TimeToShutdown)
+	defer stopCancel()
+	if err := jobs.Stop(stopCtx); err != nil {
+		log.WithError(err).Error("scheduler stop failed")
+	}
+	return serverErr
+}
+
+// NewReminderNotifier - канал доставки напоминаний по reminders.notifier
+func NewReminderNotifier(cfg domain.Config) usecase.ReminderNotifier {
+	if cfg.Reminders.Notifier == domain.ReminderNotifierWebhook {
+		return notifier.NewWebhookNotifier(cfg.Reminders.WebhookURL, cfg.Reminders.WebhookTimeout, cfg.I18n.DefaultLanguage)
 	}
-	return nil
+	return notifier.NewLogNotifier(cfg.I18n.DefaultLanguage)
 }
@@ -0,0 +1,120 @@
+package build
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/repository"
+	"chopper/internal/security"
+	"chopper/internal/tracing"
+	"chopper/internal/usecase"
+	"chopper/migrations"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// сборка общих зависимостей для сервера и CLI команд
+
+func connString(config domain.DataBaseConfig) string {
+	return fmt.Sprintf("postgres://%v:%v@%v:%v/%v?sslmode=%v", config.User, config.Password, config.Host, config.Port, config.Name, config.SSLMode)
+}
+
+// ConnectDatabase создает пул и проверяет соединение
+func ConnectDatabase(ctx context.Context, config domain.DataBaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(connString(config))
+	if err != nil {
+		return nil, fmt.Errorf("parse database config: %w", err)
+	}
+	poolConfig.MaxConns = config.MaxConns
+	poolConfig.MinConns = config.MinConns
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return pool, nil
+}
+
+// NewMigrate - golang-migrate поверх миграций, встроенных в бинарник
+func NewMigrate(config domain.DataBaseConfig) (*migrate.Migrate, error) {
+	source, err := migrations.Source()
+	if err != nil {
+		return nil, fmt.Errorf("open embedded migrations: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", source, connString(config))
+	if err != nil {
+		return nil, fmt.Errorf("create migrate: %w", err)
+	}
+	return m, nil
+}
+
+// prepareSchema применяет миграции в режиме auto и проверяет, что схема совпадает с бинарником
+func prepareSchema(config domain.DataBaseConfig) (domain.SchemaVersion, error) {
+	m, err := NewMigrate(config)
+	if err != nil {
+		return domain.SchemaVersion{}, err
+	}
+	defer m.Close()
+	version, err := ReadSchemaVersion(m)
+	if err != nil {
+		return domain.SchemaVersion{}, err
+	}
+	// схему новее бинарника не трогаем и в режиме auto: откат - решение оператора
+	if config.Migrations == domain.MigrationsModeAuto && !version.Dirty && version.Current < version.Expected {
+		if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return domain.SchemaVersion{}, fmt.Errorf("migrate up: %w", err)
+		}
+		if version, err = ReadSchemaVersion(m); err != nil {
+			return domain.SchemaVersion{}, err
+		}
+	}
+	if err := CheckSchemaVersion(version); err != nil {
+		return domain.SchemaVersion{}, err
+	}
+	return version, nil
+}
+
+// ReadSchemaVersion - версия схемы в базе рядом с версией встроенных миграций, без примененных миграций Current = 0
+func ReadSchemaVersion(m *migrate.Migrate) (domain.SchemaVersion, error) {
+	expected, err := migrations.Latest()
+	if err != nil {
+		return domain.SchemaVersion{}, fmt.Errorf("read embedded migrations version: %w", err)
+	}
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return domain.SchemaVersion{}, fmt.Errorf("read schema version: %w", err)
+	}
+	return domain.SchemaVersion{Current: version, Expected: expected, Dirty: dirty}, nil
+}
+
+// CheckSchemaVersion не дает запуститься со схемой старше или новее бинарника, а также с dirty версией
+func CheckSchemaVersion(version domain.SchemaVersion) error {
+	switch {
+	case version.Dirty:
+		return fmt.Errorf("%w: version %d is dirty, fix it and run chopper migrate force", usecase.ErrSchemaVersionMismatch, version.Current)
+	case version.Current < version.Expected:
+		return fmt.Errorf("%w: schema %d is behind binary %d, run chopper migrate up", usecase.ErrSchemaVersionMismatch, version.Current, version.Expected)
+	case version.Current > version.Expected:
+		return fmt.Errorf("%w: schema %d is ahead of binary %d, deploy a newer binary", usecase.ErrSchemaVersionMismatch, version.Current, version.Expected)
+	}
+	return nil
+}
+
+// NewUserService - metrics может быть nil (CLI команды метрики не отдают).
+// События регистрации из CLI ложатся в outbox и рассылаются релеем работающего сервера
+func NewUserService(config domain.Config, pool *pgxpool.Pool, metrics usecase.MetricsRecorder) *usecase.UserService {
+	userRepo := repository.NewUserRepositoryRealization(pool)
+	jwtService := security.NewJwt(config.JWT.Secret, config.JWT.ExpirationTime, config.JWT.Issuer, config.JWT.Audience)
+	passwordHasher := security.NewPasswordHasher(config.Security.BcryptCost)
+	uuidGenerator := security.NewUUIDGenerator()
+	transactor := repository.NewTransactorRealization(pool)
+	outboxRepository := repository.NewOutboxRepositoryRealization(pool)
+	return usecase.NewUserService(userRepo, jwtService, passwordHasher, uuidGenerator, metrics, transactor, outboxRepository)
+}
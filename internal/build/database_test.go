@@ -0,0 +1,37 @@
+package build
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/usecase"
+	"errors"
+	"testing"
+)
+
+// Тест CheckSchemaVersion - сервер не стартует со схемой старше, новее бинарника или dirty
+func TestCheckSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version domain.SchemaVersion
+		ok      bool
+	}{
+		{"совпадает", domain.SchemaVersion{Current: 6, Expected: 6}, true},
+		{"схема отстает", domain.SchemaVersion{Current: 5, Expected: 6}, false},
+		{"миграций нет", domain.SchemaVersion{Current: 0, Expected: 6}, false},
+		{"схема новее бинарника", domain.SchemaVersion{Current: 7, Expected: 6}, false},
+		{"dirty", domain.SchemaVersion{Current: 6, Expected: 6, Dirty: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			err := CheckSchemaVersion(tt.version)
+
+			// assert
+			if tt.ok && err != nil {
+				t.Errorf("ошибки не ожидалось - %v", err)
+			}
+			if !tt.ok && !errors.Is(err, usecase.ErrSchemaVersionMismatch) {
+				t.Errorf("ожидалась ошибка - %v, получена - %v", usecase.ErrSchemaVersionMismatch, err)
+			}
+		})
+	}
+}
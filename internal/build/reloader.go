@@ -0,0 +1,66 @@
+package build
+
+import (
+	"chopper/internal/config"
+	"chopper/internal/domain"
+	"chopper/internal/middleware"
+	"chopper/internal/usecase"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reloader перечитывает конфиг по SIGHUP и подменяет безопасные настройки в работающих компонентах
+type Reloader struct {
+	mu             sync.Mutex
+	path           string
+	running        domain.Config
+	log            *logrus.Logger
+	rateLimiter    *middleware.RateLimiter
+	alertService   *usecase.AlertService
+	corsMiddleware *middleware.CORSMiddleware
+}
+
+func NewReloader(path string, running domain.Config, log *logrus.Logger, rateLimiter *middleware.RateLimiter, alertService *usecase.AlertService, corsMiddleware *middleware.CORSMiddleware) *Reloader {
+	return &Reloader{
+		path:           path,
+		running:        running,
+		log:            log,
+		rateLimiter:    rateLimiter,
+		alertService:   alertService,
+		corsMiddleware: corsMiddleware,
+	}
+}
+
+// Reload - невалидный конфиг не применяется совсем, работающий остается прежним
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next, err := config.Load(r.path)
+	if err != nil {
+		r.log.WithError(err).Error("config reload rejected, running config unchanged")
+		return err
+	}
+	level, err := logrus.ParseLevel(next.Logger.Level)
+	if err != nil {
+		// уже проверено в config.Load
+		return err
+	}
+	r.rateLimiter.SetPolicies(next.RateLimiter.Policies)
+	r.log.SetLevel(level)
+	r.alertService.SetRules(next.Alert)
+	r.corsMiddleware.SetConfig(next.CORS)
+	if restartRequired := config.RestartRequired(r.running, next); len(restartRequired) > 0 {
+		r.log.WithField("fields", restartRequired).Warn("config reloaded partially, changed fields require restart")
+	}
+	r.running = config.ApplyReloadable(r.running, next)
+	r.log.WithFields(logrus.Fields{
+		"log_level":       next.Logger.Level,
+		"rate_limits":     len(next.RateLimiter.Policies),
+		"cors_origins":    next.CORS.AllowedOrigins,
+		"alert_low_mood":  next.Alert.LowMood,
+		"alert_low_sleep": next.Alert.LowSleepHours,
+		"alert_high_load": next.Alert.HighLoad,
+	}).Info("config reloaded")
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"chopper/internal/build"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: chopper [-config path] <command> [arguments]
+
+commands:
+  serve                                        запустить сервер (по умолчанию)
+  migrate up [N] | down [N] | goto V | version | force V
+  user create -username U -email E [-admin]    пароль читается из stdin
+  user set-password -username U                пароль читается из stdin
+  user suspend -username U
+  config check                                 проверить конфиг и вывести все проблемы
+`
+
+// command - общие зависимости подкоманд: путь к конфигу и потоки ввода-вывода
+type command struct {
+	configPath string
+	stdin      io.Reader
+	stdout     io.Writer
+}
+
+// Run разбирает аргументы командной строки, без команды запускается serve (как раньше в Dockerfile)
+func Run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("chopper", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	flags.Usage = func() { fmt.Fprint(stdout, usage) }
+	cmd := &command{stdin: stdin, stdout: stdout}
+	flags.StringVar(&cmd.configPath, "config", os.Getenv("CONFIG_FILE"), "yaml или toml конфиг, по умолчанию $CONFIG_FILE")
+	if err := flags.Parse(args); err != nil {
+		return helpIsNotError(err)
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		return build.Run(cmd.configPath)
+	}
+	switch args[0] {
+	case "serve":
+		return build.Run(cmd.configPath)
+	case "migrate":
+		return cmd.migrate(args[1:])
+	case "user":
+		return cmd.user(args[1:])
+	case "config":
+		return cmd.config(args[1:])
+	case "help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%v", args[0], usage)
+	}
+}
+
+func helpIsNotError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
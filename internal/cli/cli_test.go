@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Тест config check - выводит все проблемы и возвращает ошибку
+func TestConfigCheckReportsProblems(t *testing.T) {
+	// preparing
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  mode: prod\n"), 0o600); err != nil {
+		t.Fatalf("write test file - %v", err)
+	}
+	var stdout bytes.Buffer
+
+	// test
+	err := Run([]string{"-config", path, "config", "check"}, strings.NewReader(""), &stdout)
+
+	// assert
+	if err == nil {
+		t.Fatalf("ожидалась ошибка")
+	}
+	for _, problem := range []string{"server.mode (SERVER_MODE)", "jwt.secret (JWT_SECRET): is required"} {
+		if !strings.Contains(stdout.String(), problem) {
+			t.Errorf("ожидалась проблема - %v, вывод - %v", problem, stdout.String())
+		}
+	}
+}
+
+// Тест - ошибки аргументов возвращаются до загрузки конфига и подключения к базе
+func TestRunArgumentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"неизвестная команда", []string{"deploy"}, "unknown command"},
+		{"migrate без действия", []string{"migrate"}, "usage"},
+		{"migrate goto без версии", []string{"migrate", "goto"}, "usage"},
+		{"migrate down с мусором", []string{"migrate", "down", "all"}, "wrong number"},
+		{"migrate up 0", []string{"migrate", "up", "0"}, "N must be at least 1"},
+		{"migrate down 0", []string{"migrate", "down", "0"}, "N must be at least 1"},
+		{"user create без email", []string{"user", "create", "-username", "dexter"}, "-email is required"},
+		{"user suspend без username", []string{"user", "suspend"}, "-username is required"},
+		{"пустой пароль", []string{"user", "set-password", "-username", "dexter"}, "password from stdin is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			err := Run(tt.args, strings.NewReader("\n"), &bytes.Buffer{})
+
+			// assert
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ожидалась ошибка с %q, получена - %v", tt.err, err)
+			}
+		})
+	}
+}
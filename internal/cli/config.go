@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"chopper/internal/config"
+	"errors"
+	"fmt"
+)
+
+func (c *command) config(args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return fmt.Errorf("usage: chopper config check")
+	}
+	if _, err := config.Load(c.configPath); err != nil {
+		var validationError *config.ValidationError
+		if errors.As(err, &validationError) {
+			for _, problem := range validationError.Problems {
+				fmt.Fprintln(c.stdout, problem)
+			}
+			return fmt.Errorf("config has %d problem(s)", len(validationError.Problems))
+		}
+		return err
+	}
+	fmt.Fprintln(c.stdout, "config is valid")
+	return nil
+}
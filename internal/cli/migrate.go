@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"chopper/internal/build"
+	"chopper/internal/config"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+func (c *command) migrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: chopper migrate up [N] | down [N] | goto V | version | force V")
+	}
+	// аргумент проверяем до подключения к базе
+	action, arg := args[0], -1
+	switch {
+	case len(args) == 2 && action != "version":
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("migrate %v: wrong number %q", action, args[1])
+		}
+		arg = n
+	case len(args) > 2:
+		return fmt.Errorf("migrate %v: too many arguments", action)
+	}
+	switch action {
+	case "up", "down":
+		// 0 шагов - скорее опечатка: up применил бы все миграции, down ничего бы не сделал
+		if arg == 0 {
+			return fmt.Errorf("migrate %v: N must be at least 1", action)
+		}
+	case "version":
+	case "goto", "force":
+		if arg < 0 {
+			return fmt.Errorf("usage: chopper migrate %v V", action)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", action)
+	}
+
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	m, err := build.NewMigrate(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch action {
+	case "up":
+		if arg > 0 {
+			err = m.Steps(arg)
+		} else {
+			err = m.Up()
+		}
+	case "down":
+		// откат всей схемы одной командой слишком опасен, по умолчанию один шаг
+		if arg < 0 {
+			arg = 1
+		}
+		err = m.Steps(-arg)
+	case "goto":
+		err = m.Migrate(uint(arg))
+	case "force":
+		err = m.Force(arg)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate %v: %w", action, err)
+	}
+	version, err := build.ReadSchemaVersion(m)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "version %d, dirty: %v, binary expects %d\n", version.Current, version.Dirty, version.Expected)
+	return nil
+}
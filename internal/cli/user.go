@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bufio"
+	"chopper/internal/build"
+	"chopper/internal/config"
+	"chopper/internal/domain"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func (c *command) user(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: chopper user create | set-password | suspend")
+	}
+	action := args[0]
+	flags := flag.NewFlagSet("user "+action, flag.ContinueOnError)
+	flags.SetOutput(c.stdout)
+	username := flags.String("username", "", "имя пользователя")
+	var email *string
+	var admin *bool
+	switch action {
+	case "create":
+		email = flags.String("email", "", "email пользователя")
+		admin = flags.Bool("admin", false, "создать администратора")
+	case "set-password", "suspend":
+	default:
+		return fmt.Errorf("unknown user command %q", action)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return helpIsNotError(err)
+	}
+	if *username == "" {
+		return fmt.Errorf("user %v: -username is required", action)
+	}
+	if email != nil && *email == "" {
+		return fmt.Errorf("user %v: -email is required", action)
+	}
+
+	// пароль не принимается аргументом, чтобы не оставлять его в истории shell и списке процессов
+	var password string
+	if action == "create" || action == "set-password" {
+		p, err := c.readPassword()
+		if err != nil {
+			return err
+		}
+		password = p
+	}
+
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	// те же правила, что у POST /api/v1/users/register, проверяются до подключения к бд
+	requestValidator, err := validation.New(cfg.Security.PasswordPolicy)
+	if err != nil {
+		return fmt.Errorf("init validation: %w", err)
+	}
+	var user domain.UserRegisterFromFront
+	switch action {
+	case "create":
+		user = domain.UserRegisterFromFront{Username: *username, Email: *email, Password: password}
+		err = requestValidator.ValidateStruct(user)
+	case "set-password":
+		err = requestValidator.ValidatePassword(password)
+	}
+	if err != nil {
+		return fmt.Errorf("user %v: %w", action, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	userService, pool, err := c.userService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	switch action {
+	case "create":
+		if *admin {
+			err = userService.CreateAdmin(ctx, user)
+		} else {
+			err = userService.CreateUser(ctx, user)
+		}
+	case "set-password":
+		err = userService.SetPassword(ctx, *username, password)
+	case "suspend":
+		err = userService.SuspendUser(ctx, *username)
+	}
+	if err != nil && errors.Is(err, usecase.ErrUserExists) {
+		return fmt.Errorf("user %q already exists", *username)
+	} else if err != nil && errors.Is(err, usecase.ErrUserNotExist) {
+		return fmt.Errorf("user %q not found", *username)
+	} else if err != nil {
+		return fmt.Errorf("user %v: %w", action, err)
+	}
+	fmt.Fprintf(c.stdout, "user %v: done for %q\n", action, *username)
+	return nil
+}
+
+func (c *command) userService(ctx context.Context, cfg domain.Config) (*usecase.UserService, *pgxpool.Pool, error) {
+	pool, err := build.ConnectDatabase(ctx, cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	return build.NewUserService(cfg, pool, nil), pool, nil
+}
+
+// readPassword читает первую строку stdin: echo "..." | chopper user set-password -username U
+func (c *command) readPassword() (string, error) {
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password from stdin: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password from stdin is empty")
+	}
+	return password, nil
+}
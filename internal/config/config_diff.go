@@ -0,0 +1,47 @@
+package config
+
+import (
+	"chopper/internal/domain"
+	"reflect"
+)
+
+// reloadable - настройки, которые подменяются на лету при SIGHUP
+var reloadable = map[string]bool{
+	"RateLimiter.Policies": true,
+	"Logger.Level":         true,
+	"Alert":                true,
+	"CORS":                 true,
+}
+
+// RestartRequired возвращает поля, которые отличаются в next, но применятся только после перезапуска.
+// Значения не возвращаются, чтобы в лог не попали секреты
+func RestartRequired(running, next domain.Config) []string {
+	changed := []string{}
+	runningValue, nextValue := reflect.ValueOf(running), reflect.ValueOf(next)
+	for i := 0; i < runningValue.NumField(); i++ {
+		section := runningValue.Type().Field(i).Name
+		if reloadable[section] {
+			continue
+		}
+		runningSection, nextSection := runningValue.Field(i), nextValue.Field(i)
+		for j := 0; j < runningSection.NumField(); j++ {
+			field := section + "." + runningSection.Type().Field(j).Name
+			if reloadable[field] {
+				continue
+			}
+			if !reflect.DeepEqual(runningSection.Field(j).Interface(), nextSection.Field(j).Interface()) {
+				changed = append(changed, field)
+			}
+		}
+	}
+	return changed
+}
+
+// ApplyReloadable переносит в running только то, что подменяется на лету; результат - фактически работающий конфиг
+func ApplyReloadable(running, next domain.Config) domain.Config {
+	running.RateLimiter.Policies = next.RateLimiter.Policies
+	running.Logger.Level = next.Logger.Level
+	running.Alert = next.Alert
+	running.CORS = next.CORS
+	return running
+}
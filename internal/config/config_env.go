@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envBinding связывает env переменную с полем сырого конфига; field - путь поля в файле
+type envBinding struct {
+	env    string
+	field  string
+	target any
+}
+
+func envBindings(c *fileConfig) []envBinding {
+	return []envBinding{
+		{"SERVER_ADDRESS", "server.address", &c.Server.Address},
+		{"SERVER_ADMINADDRESS", "server.admin_address", &c.Server.AdminAddress},
+		{"SERVER_READTIMEOUT", "server.read_timeout", &c.Server.ReadTimeout},
+		{"SERVER_WRITETIMEOUT", "server.write_timeout", &c.Server.WriteTimeout},
+		{"SERVER_IDLETIMEOUT", "server.idle_timeout", &c.Server.IdleTimeout},
+		{"SERVER_TIMETOSHUTDOWN", "server.time_to_shutdown", &c.Server.TimeToShutdown},
+		{"SERVER_DRAINDELAY", "server.drain_delay", &c.Server.DrainDelay},
+		{"SERVER_MODE", "server.mode", &c.Server.Mode},
+		{"SERVER_TLSCERTFILE", "server.tls_cert_file", &c.Server.TLSCertFile},
+		{"SERVER_TLSKEYFILE", "server.tls_key_file", &c.Server.TLSKeyFile},
+		{"SERVER_TLSCLIENTCAFILE", "server.tls_client_ca_file", &c.Server.TLSClientCAFile},
+		{"SERVER_UNIXSOCKET", "server.unix_socket", &c.Server.UnixSocket},
+		{"SERVER_HTTP2", "server.http2", &c.Server.HTTP2},
+		{"SERVER_LEGACYROUTES", "server.legacy_routes", &c.Server.LegacyRoutes},
+		{"SERVER_LEGACYDEPRECATEDAT", "server.legacy_deprecated_at", &c.Server.LegacyDeprecatedAt},
+		{"SERVER_LEGACYSUNSET", "server.legacy_sunset", &c.Server.LegacySunset},
+		{"SERVER_GRPCADDRESS", "server.grpc_address", &c.Server.GRPCAddress},
+
+		{"DB_USER", "database.user", &c.Database.User},
+		{"DB_PASSWORD", "database.password", &c.Database.Password},
+		{"DB_HOST", "database.host", &c.Database.Host},
+		{"DB_PORT", "database.port", &c.Database.Port},
+		{"DB_NAME", "database.name", &c.Database.Name},
+		{"DB_SSLMODE", "database.ssl_mode", &c.Database.SSLMode},
+		{"DB_MAXCONNS", "database.max_conns", &c.Database.MaxConns},
+		{"DB_MINCONNS", "database.min_conns", &c.Database.MinConns},
+		{"DB_MIGRATIONS", "database.migrations", &c.Database.Migrations},
+
+		{"JWT_SECRET", "jwt.secret", &c.JWT.Secret},
+		{"JWT_EXPIRATIONTIME", "jwt.expiration_time", &c.JWT.ExpirationTime},
+		{"JWT_ISSUER", "jwt.issuer", &c.JWT.Issuer},
+		{"JWT_AUDIENCE", "jwt.audience", &c.JWT.Audience},
+
+		{"LIMITER_RATE", "rate_limiter.rate", &c.RateLimiter.Rate},
+		{"LIMITER_BURST", "rate_limiter.burst", &c.RateLimiter.Burst},
+		{"LIMITER_IDLETIMEOUT", "rate_limiter.idle_timeout", &c.RateLimiter.IdleTimeout},
+		{"LIMITER_STORE", "rate_limiter.store", &c.RateLimiter.Store},
+		{"LIMITER_POLICIES", "rate_limiter.policies", &c.RateLimiter.Policies},
+		{"LIMITER_GROUPS", "rate_limiter.groups", &c.RateLimiter.Groups},
+
+		{"LOG_LEVEL", "log.level", &c.Log.Level},
+		{"LOG_FORMAT", "log.format", &c.Log.Format},
+
+		{"TRACING_EXPORTER", "tracing.exporter", &c.Tracing.Exporter},
+		{"OTEL_SERVICE_NAME", "tracing.service_name", &c.Tracing.ServiceName},
+		{"TRACING_SAMPLERATIO", "tracing.sample_ratio", &c.Tracing.SampleRatio},
+
+		{"SECURITY_BCRYPTCOST", "security.bcrypt_cost", &c.Security.BcryptCost},
+		{"SECURITY_PASSWORDMINLENGTH", "security.password_min_length", &c.Security.PasswordMinLength},
+		{"SECURITY_PASSWORDMAXLENGTH", "security.password_max_length", &c.Security.PasswordMaxLength},
+		{"SECURITY_PASSWORDREQUIREUPPER", "security.password_require_upper", &c.Security.PasswordRequireUpper},
+		{"SECURITY_PASSWORDREQUIRELOWER", "security.password_require_lower", &c.Security.PasswordRequireLower},
+		{"SECURITY_PASSWORDREQUIREDIGIT", "security.password_require_digit", &c.Security.PasswordRequireDigit},
+		{"SECURITY_PASSWORDREQUIRESYMBOL", "security.password_require_symbol", &c.Security.PasswordRequireSymbol},
+		{"SECURITY_PASSWORDBLOCKLISTFILE", "security.password_blocklist_file", &c.Security.PasswordBlocklistFile},
+
+		{"ALERT_LOWMOOD", "alert.low_mood", &c.Alert.LowMood},
+		{"ALERT_LOWSLEEPHOURS", "alert.low_sleep_hours", &c.Alert.LowSleepHours},
+		{"ALERT_HIGHLOAD", "alert.high_load", &c.Alert.HighLoad},
+
+		{"CORS_ALLOWEDORIGINS", "cors.allowed_origins", &c.CORS.AllowedOrigins},
+		{"CORS_ALLOWEDMETHODS", "cors.allowed_methods", &c.CORS.AllowedMethods},
+		{"CORS_ALLOWEDHEADERS", "cors.allowed_headers", &c.CORS.AllowedHeaders},
+		{"CORS_EXPOSEDHEADERS", "cors.exposed_headers", &c.CORS.ExposedHeaders},
+		{"CORS_ALLOWCREDENTIALS", "cors.allow_credentials", &c.CORS.AllowCredentials},
+		{"CORS_MAXAGE", "cors.max_age", &c.CORS.MaxAge},
+
+		{"I18N_DEFAULTLANGUAGE", "i18n.default_language", &c.I18n.DefaultLanguage},
+
+		{"EVENTS_HEARTBEATINTERVAL", "events.heartbeat_interval", &c.Events.HeartbeatInterval},
+		{"EVENTS_RETENTION", "events.retention", &c.Events.Retention},
+		{"EVENTS_MAXSTREAMS", "events.max_streams", &c.Events.MaxStreams},
+
+		{"REMINDERS_ENABLED", "reminders.enabled", &c.Reminders.Enabled},
+		{"REMINDERS_CHECKINTERVAL", "reminders.check_interval", &c.Reminders.CheckInterval},
+		{"REMINDERS_BATCHSIZE", "reminders.batch_size", &c.Reminders.BatchSize},
+		{"REMINDERS_MAXATTEMPTS", "reminders.max_attempts", &c.Reminders.MaxAttempts},
+		{"REMINDERS_RETRYDELAY", "reminders.retry_delay", &c.Reminders.RetryDelay},
+		{"REMINDERS_NOTIFIER", "reminders.notifier", &c.Reminders.Notifier},
+		{"REMINDERS_WEBHOOKURL", "reminders.webhook_url", &c.Reminders.WebhookURL},
+		{"REMINDERS_WEBHOOKTIMEOUT", "reminders.webhook_timeout", &c.Reminders.WebhookTimeout},
+
+		{"OUTBOX_POLLINTERVAL", "outbox.poll_interval", &c.Outbox.PollInterval},
+		{"OUTBOX_BATCHSIZE", "outbox.batch_size", &c.Outbox.BatchSize},
+		{"OUTBOX_MAXATTEMPTS", "outbox.max_attempts", &c.Outbox.MaxAttempts},
+		{"OUTBOX_RETENTION", "outbox.retention", &c.Outbox.Retention},
+		{"IDEMPOTENCY_TTL", "idempotency.ttl", &c.Idempotency.TTL},
+	}
+}
+
+// applyEnv переопределяет поля из env, ошибки разбора копятся в problems вместе с остальными
+func applyEnv(c *fileConfig, problems *ValidationError) {
+	for _, binding := range envBindings(c) {
+		value, ok, err := lookupEnv(binding.env)
+		if err != nil {
+			problems.add(binding.field, binding.env, err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		switch target := binding.target.(type) {
+		case *string:
+			*target = value
+		case *int:
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				problems.add(binding.field, binding.env, "must be an integer")
+				continue
+			}
+			*target = parsed
+		case *float64:
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				problems.add(binding.field, binding.env, "must be a number")
+				continue
+			}
+			*target = parsed
+		case *bool:
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				problems.add(binding.field, binding.env, "must be true or false")
+				continue
+			}
+			*target = parsed
+		case *[]string:
+			*target = splitList(value)
+		case *[]policySection:
+			policies, err := parseRateLimitPolicies(value)
+			if err != nil {
+				problems.add(binding.field, binding.env, err.Error())
+				continue
+			}
+			// политики из env дописываются после файловых и переопределяют одноименные
+			*target = append(*target, policies...)
+		case *map[string]string:
+			groups, err := parseRateLimitGroups(value)
+			if err != nil {
+				problems.add(binding.field, binding.env, err.Error())
+				continue
+			}
+			// назначения из env переопределяют файловые для тех же групп
+			if *target == nil {
+				*target = map[string]string{}
+			}
+			for group, policy := range groups {
+				(*target)[group] = policy
+			}
+		}
+	}
+}
+
+// lookupEnv читает NAME или содержимое файла из NAME_FILE (docker/k8s secrets), оба сразу - ошибка
+func lookupEnv(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	path, fileOk := os.LookupEnv(name + "_FILE")
+	if ok && fileOk {
+		return "", false, fmt.Errorf("both %v and %v_FILE are set", name, name)
+	}
+	if !fileOk {
+		return value, ok, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("read %v_FILE: %v", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
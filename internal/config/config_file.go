@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig - сырой конфиг до валидации: файл, затем env переменные.
+// Длительности хранятся строками, чтобы ошибки формата собирались валидацией вместе с остальными
+type fileConfig struct {
+	Server      serverSection      `yaml:"server" toml:"server"`
+	Database    databaseSection    `yaml:"database" toml:"database"`
+	JWT         jwtSection         `yaml:"jwt" toml:"jwt"`
+	RateLimiter rateLimiterSection `yaml:"rate_limiter" toml:"rate_limiter"`
+	Log         logSection         `yaml:"log" toml:"log"`
+	Tracing     tracingSection     `yaml:"tracing" toml:"tracing"`
+	Security    securitySection    `yaml:"security" toml:"security"`
+	Alert       alertSection       `yaml:"alert" toml:"alert"`
+	CORS        corsSection        `yaml:"cors" toml:"cors"`
+	I18n        i18nSection        `yaml:"i18n" toml:"i18n"`
+	Events      eventsSection      `yaml:"events" toml:"events"`
+	Reminders   remindersSection   `yaml:"reminders" toml:"reminders"`
+	Outbox      outboxSection      `yaml:"outbox" toml:"outbox"`
+	Idempotency idempotencySection `yaml:"idempotency" toml:"idempotency"`
+}
+
+type serverSection struct {
+	Address            string `yaml:"address" toml:"address"`
+	AdminAddress       string `yaml:"admin_address" toml:"admin_address"`
+	ReadTimeout        string `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout       string `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout        string `yaml:"idle_timeout" toml:"idle_timeout"`
+	TimeToShutdown     string `yaml:"time_to_shutdown" toml:"time_to_shutdown"`
+	DrainDelay         string `yaml:"drain_delay" toml:"drain_delay"`
+	Mode               string `yaml:"mode" toml:"mode"`
+	TLSCertFile        string `yaml:"tls_cert_file" toml:"tls_cert_file"`
+	TLSKeyFile         string `yaml:"tls_key_file" toml:"tls_key_file"`
+	TLSClientCAFile    string `yaml:"tls_client_ca_file" toml:"tls_client_ca_file"`
+	UnixSocket         string `yaml:"unix_socket" toml:"unix_socket"`
+	HTTP2              bool   `yaml:"http2" toml:"http2"`
+	LegacyRoutes       bool   `yaml:"legacy_routes" toml:"legacy_routes"`
+	LegacyDeprecatedAt string `yaml:"legacy_deprecated_at" toml:"legacy_deprecated_at"`
+	LegacySunset       string `yaml:"legacy_sunset" toml:"legacy_sunset"`
+	GRPCAddress        string `yaml:"grpc_address" toml:"grpc_address"`
+}
+
+type databaseSection struct {
+	User       string `yaml:"user" toml:"user"`
+	Password   string `yaml:"password" toml:"password"`
+	Host       string `yaml:"host" toml:"host"`
+	Port       string `yaml:"port" toml:"port"`
+	Name       string `yaml:"name" toml:"name"`
+	SSLMode    string `yaml:"ssl_mode" toml:"ssl_mode"`
+	MaxConns   int    `yaml:"max_conns" toml:"max_conns"`
+	MinConns   int    `yaml:"min_conns" toml:"min_conns"`
+	Migrations string `yaml:"migrations" toml:"migrations"`
+}
+
+type jwtSection struct {
+	Secret         string `yaml:"secret" toml:"secret"`
+	ExpirationTime string `yaml:"expiration_time" toml:"expiration_time"`
+	Issuer         string `yaml:"issuer" toml:"issuer"`
+	Audience       string `yaml:"audience" toml:"audience"`
+}
+
+type rateLimiterSection struct {
+	Rate        string            `yaml:"rate" toml:"rate"`
+	Burst       int               `yaml:"burst" toml:"burst"`
+	IdleTimeout string            `yaml:"idle_timeout" toml:"idle_timeout"`
+	Store       string            `yaml:"store" toml:"store"`
+	Policies    []policySection   `yaml:"policies" toml:"policies"`
+	Groups      map[string]string `yaml:"groups" toml:"groups"`
+}
+
+type policySection struct {
+	Name  string `yaml:"name" toml:"name"`
+	Rate  string `yaml:"rate" toml:"rate"`
+	Burst int    `yaml:"burst" toml:"burst"`
+	Key   string `yaml:"key" toml:"key"`
+}
+
+type logSection struct {
+	Level  string `yaml:"level" toml:"level"`
+	Format string `yaml:"format" toml:"format"`
+}
+
+type tracingSection struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+type securitySection struct {
+	BcryptCost            int    `yaml:"bcrypt_cost" toml:"bcrypt_cost"`
+	PasswordMinLength     int    `yaml:"password_min_length" toml:"password_min_length"`
+	PasswordMaxLength     int    `yaml:"password_max_length" toml:"password_max_length"`
+	PasswordRequireUpper  bool   `yaml:"password_require_upper" toml:"password_require_upper"`
+	PasswordRequireLower  bool   `yaml:"password_require_lower" toml:"password_require_lower"`
+	PasswordRequireDigit  bool   `yaml:"password_require_digit" toml:"password_require_digit"`
+	PasswordRequireSymbol bool   `yaml:"password_require_symbol" toml:"password_require_symbol"`
+	PasswordBlocklistFile string `yaml:"password_blocklist_file" toml:"password_blocklist_file"`
+}
+
+type alertSection struct {
+	LowMood       int     `yaml:"low_mood" toml:"low_mood"`
+	LowSleepHours float64 `yaml:"low_sleep_hours" toml:"low_sleep_hours"`
+	HighLoad      int     `yaml:"high_load" toml:"high_load"`
+}
+
+type corsSection struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" toml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods" toml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers" toml:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers" toml:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials" toml:"allow_credentials"`
+	MaxAge           string   `yaml:"max_age" toml:"max_age"`
+}
+
+type i18nSection struct {
+	DefaultLanguage string `yaml:"default_language" toml:"default_language"`
+}
+
+type eventsSection struct {
+	HeartbeatInterval string `yaml:"heartbeat_interval" toml:"heartbeat_interval"`
+	Retention         string `yaml:"retention" toml:"retention"`
+	MaxStreams        int    `yaml:"max_streams" toml:"max_streams"`
+}
+
+type remindersSection struct {
+	Enabled        bool   `yaml:"enabled" toml:"enabled"`
+	CheckInterval  string `yaml:"check_interval" toml:"check_interval"`
+	BatchSize      int    `yaml:"batch_size" toml:"batch_size"`
+	MaxAttempts    int    `yaml:"max_attempts" toml:"max_attempts"`
+	RetryDelay     string `yaml:"retry_delay" toml:"retry_delay"`
+	Notifier       string `yaml:"notifier" toml:"notifier"`
+	WebhookURL     string `yaml:"webhook_url" toml:"webhook_url"`
+	WebhookTimeout string `yaml:"webhook_timeout" toml:"webhook_timeout"`
+}
+
+type idempotencySection struct {
+	TTL string `yaml:"ttl" toml:"ttl"`
+}
+
+type outboxSection struct {
+	PollInterval string `yaml:"poll_interval" toml:"poll_interval"`
+	BatchSize    int    `yaml:"batch_size" toml:"batch_size"`
+	MaxAttempts  int    `yaml:"max_attempts" toml:"max_attempts"`
+	Retention    string `yaml:"retention" toml:"retention"`
+}
+
+// defaultFileConfig - значения по умолчанию для всего, кроме доступов к базе и секрета JWT
+func defaultFileConfig() fileConfig {
+	return fileConfig{
+		Server: serverSection{
+			Address:        ":8080",
+			AdminAddress:   ":9090",
+			ReadTimeout:    "5s",
+			WriteTimeout:   "10s",
+			IdleTimeout:    "60s",
+			TimeToShutdown: "10s",
+			DrainDelay:     "0s",
+			Mode:           "release",
+			HTTP2:          true,
+			// пути без /api/v1 объявлены устаревшими с выходом v1, полгода на переход клиентов
+			LegacyRoutes:       true,
+			LegacyDeprecatedAt: "2026-10-19",
+			LegacySunset:       "2027-04-19",
+		},
+		Database: databaseSection{
+			Host:       "localhost",
+			Port:       "5432",
+			SSLMode:    "disable",
+			MaxConns:   10,
+			MinConns:   0,
+			Migrations: "auto",
+		},
+		JWT: jwtSection{
+			ExpirationTime: "24h",
+			Issuer:         "chopper",
+			Audience:       "chopper-api",
+		},
+		RateLimiter: rateLimiterSection{
+			Rate:        "20s",
+			Burst:       5,
+			IdleTimeout: "10m",
+			Store:       "memory",
+		},
+		Log: logSection{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: tracingSection{
+			Exporter:    "none",
+			ServiceName: "chopper",
+			SampleRatio: 1,
+		},
+		// по NIST 800-63B: длина и список распространенных паролей, классы символов выключены
+		Security: securitySection{
+			BcryptCost:        10,
+			PasswordMinLength: 8,
+			PasswordMaxLength: 72,
+		},
+		Alert: alertSection{
+			LowMood:       5,
+			LowSleepHours: 7,
+			HighLoad:      5,
+		},
+		CORS: corsSection{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "X-Request-ID", "Last-Event-ID", "Idempotency-Key", "If-Match", "If-None-Match"},
+			ExposedHeaders: []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After", "Deprecation", "Sunset", "Link", "Idempotent-Replayed", "ETag"},
+			MaxAge:         "10m",
+		},
+		// исторически все тексты ответов были на русском
+		I18n: i18nSection{
+			DefaultLanguage: "ru",
+		},
+		// heartbeat короче типичного idle таймаута прокси (60s)
+		Events: eventsSection{
+			HeartbeatInterval: "15s",
+			Retention:         "24h",
+			MaxStreams:        5,
+		},
+		// время напоминания задается с точностью до минуты
+		Reminders: remindersSection{
+			Enabled:        true,
+			CheckInterval:  "1m",
+			BatchSize:      100,
+			MaxAttempts:    3,
+			RetryDelay:     "5m",
+			Notifier:       "log",
+			WebhookTimeout: "5s",
+		},
+		// poll_interval - задержка SSE событий и алертов после записи
+		Outbox: outboxSection{
+			PollInterval: "1s",
+			BatchSize:    100,
+			MaxAttempts:  10,
+			Retention:    "72h",
+		},
+		// сутки покрывают повторы мобильного клиента после долгой потери сети
+		Idempotency: idempotencySection{
+			TTL: "24h",
+		},
+	}
+}
+
+// readConfigFile накладывает файл поверх значений по умолчанию, формат определяется по расширению
+func readConfigFile(path string, config *fileConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse config file %v: %w", path, err)
+		}
+	case ".toml":
+		metadata, err := toml.Decode(string(data), config)
+		if err != nil {
+			return fmt.Errorf("parse config file %v: %w", path, err)
+		}
+		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse config file %v: unknown field %v", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	return nil
+}
@@ -2,109 +2,438 @@ package config
 
 import (
 	"chopper/internal/domain"
+	"chopper/internal/i18n"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultAuthPolicyRate  = time.Minute
+	defaultAuthPolicyBurst = 5
 )
 
-func ConfigsLoad() (domain.ServerConfig, domain.JWtConfig, domain.DataBaseConfig, domain.RateLimiterConfig, error) {
-	// загрузка конфига сервера
-	var serverConfig domain.ServerConfig
-	serverAddress := os.Getenv("SERVER_ADDRESS")
-	serverReadtimeout := os.Getenv("SERVER_READTIMEOUT")
-	serverWritetimeout := os.Getenv("SERVER_WRITETIMEOUT")
-	serverIdletimeout := os.Getenv("SERVER_IDLETIMEOUT")
-	serverTimeToShutdown := os.Getenv("SERVER_TIMETOSHUTDOWN")
-	serverMode := os.Getenv("SERVER_MODE")
-	if serverAddress == "" || serverReadtimeout == "" || serverWritetimeout == "" || serverIdletimeout == "" || serverTimeToShutdown == "" || serverMode == "" {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, fmt.Errorf("lack of environment variables")
-	}
-	readTimeout, err := time.ParseDuration(serverReadtimeout)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	writeTimeout, err := time.ParseDuration(serverWritetimeout)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	idleTimeout, err := time.ParseDuration(serverIdletimeout)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	timeToShutdown, err := time.ParseDuration(serverTimeToShutdown)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	serverConfig.Address = serverAddress
-	serverConfig.ReadTimeout = readTimeout
-	serverConfig.WriteTimeout = writeTimeout
-	serverConfig.IdleTimeout = idleTimeout
-	serverConfig.TimeToShutdown = timeToShutdown
-	var sm domain.ServerMode
-	switch serverMode {
-	case "release":
-		sm = domain.ReleaseMode
-	case "debug":
-		sm = domain.DebugMode
-	case "test":
-		sm = domain.TestMode
+// defaultRateLimitGroups - встроенные назначения политик; ключи - все группы, которые знают роуты
+var defaultRateLimitGroups = map[string]string{
+	domain.RateLimitGroupUsersPublic:  domain.RateLimitPolicyAuth,
+	domain.RateLimitGroupUsers:        domain.RateLimitPolicyUser,
+	domain.RateLimitGroupUsersSession: domain.RateLimitPolicyUser,
+	domain.RateLimitGroupNotesRead:    domain.RateLimitPolicyUser,
+	domain.RateLimitGroupNotes:        domain.RateLimitPolicyUser,
+	domain.RateLimitGroupAlert:        domain.RateLimitPolicyUser,
+	domain.RateLimitGroupTokens:       domain.RateLimitPolicyUser,
+	domain.RateLimitGroupEvents:       domain.RateLimitPolicyUser,
+	domain.RateLimitGroupGRPCPublic:   domain.RateLimitPolicyAuth,
+	domain.RateLimitGroupGRPC:         domain.RateLimitPolicyUser,
+}
+
+// Load собирает конфиг: значения по умолчанию, затем файл path (если задан), затем env переменные и NAME_FILE.
+// Возвращает *ValidationError со всеми проблемами сразу
+func Load(path string) (domain.Config, error) {
+	raw := defaultFileConfig()
+	if path != "" {
+		if err := readConfigFile(path, &raw); err != nil {
+			return domain.Config{}, err
+		}
+	}
+	problems := &ValidationError{}
+	applyEnv(&raw, problems)
+	config := raw.validate(problems)
+	if len(problems.Problems) > 0 {
+		return domain.Config{}, problems
+	}
+	return config, nil
+}
+
+// validate переводит сырой конфиг в domain.Config, каждая проблема записывается с именем поля и env переменной
+func (c *fileConfig) validate(problems *ValidationError) domain.Config {
+	envs := map[string]string{}
+	for _, binding := range envBindings(c) {
+		envs[binding.field] = binding.env
+	}
+	add := func(field, message string) {
+		problems.add(field, envs[field], message)
+	}
+	duration := func(field, value string, allowZero bool) time.Duration {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			add(field, fmt.Sprintf("invalid duration %q", value))
+			return 0
+		}
+		if parsed < 0 || (parsed == 0 && !allowZero) {
+			add(field, "must be positive")
+		}
+		return parsed
+	}
+	date := func(field, value string) time.Time {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			add(field, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", value))
+		}
+		return parsed
+	}
+	required := func(field, value string) {
+		if strings.TrimSpace(value) == "" {
+			add(field, "is required")
+		}
+	}
+	var config domain.Config
+
+	// сервер
+	required("server.address", c.Server.Address)
+	required("server.admin_address", c.Server.AdminAddress)
+	config.Server = domain.ServerConfig{
+		Address:        c.Server.Address,
+		AdminAddress:   c.Server.AdminAddress,
+		ReadTimeout:    duration("server.read_timeout", c.Server.ReadTimeout, false),
+		WriteTimeout:   duration("server.write_timeout", c.Server.WriteTimeout, false),
+		IdleTimeout:    duration("server.idle_timeout", c.Server.IdleTimeout, false),
+		TimeToShutdown: duration("server.time_to_shutdown", c.Server.TimeToShutdown, false),
+		DrainDelay:     duration("server.drain_delay", c.Server.DrainDelay, true),
+		ServerMode:     domain.ServerMode(c.Server.Mode),
+		TLS: domain.TLSConfig{
+			CertFile:     c.Server.TLSCertFile,
+			KeyFile:      c.Server.TLSKeyFile,
+			ClientCAFile: c.Server.TLSClientCAFile,
+		},
+		UnixSocket: c.Server.UnixSocket,
+		HTTP2:      c.Server.HTTP2,
+		LegacyAPI: domain.LegacyAPIConfig{
+			Enabled:      c.Server.LegacyRoutes,
+			DeprecatedAt: date("server.legacy_deprecated_at", c.Server.LegacyDeprecatedAt),
+			Sunset:       date("server.legacy_sunset", c.Server.LegacySunset),
+		},
+		GRPCAddress: c.Server.GRPCAddress,
+	}
+	if legacy := config.Server.LegacyAPI; !legacy.DeprecatedAt.IsZero() && !legacy.Sunset.IsZero() && !legacy.Sunset.After(legacy.DeprecatedAt) {
+		add("server.legacy_sunset", "must be after legacy_deprecated_at")
+	}
+	switch config.Server.ServerMode {
+	case domain.ReleaseMode, domain.DebugMode, domain.TestMode:
+	default:
+		add("server.mode", "must be one of release, debug, test")
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		add("server.tls_cert_file", "tls_cert_file and tls_key_file must be set together")
+	}
+	if c.Server.TLSClientCAFile != "" && c.Server.TLSCertFile == "" {
+		add("server.tls_client_ca_file", "requires tls_cert_file and tls_key_file")
+	}
+	for field, path := range map[string]string{
+		"server.tls_cert_file":      c.Server.TLSCertFile,
+		"server.tls_key_file":       c.Server.TLSKeyFile,
+		"server.tls_client_ca_file": c.Server.TLSClientCAFile,
+	} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			add(field, fmt.Sprintf("file is not readable: %v", err))
+		}
+	}
+
+	// база данных
+	required("database.user", c.Database.User)
+	required("database.password", c.Database.Password)
+	required("database.host", c.Database.Host)
+	required("database.port", c.Database.Port)
+	required("database.name", c.Database.Name)
+	if !slices.Contains([]string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}, c.Database.SSLMode) {
+		add("database.ssl_mode", "must be one of disable, allow, prefer, require, verify-ca, verify-full")
+	}
+	if c.Database.MaxConns < 1 {
+		add("database.max_conns", "must be at least 1")
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		add("database.min_conns", "must be between 0 and max_conns")
+	}
+	config.Database = domain.DataBaseConfig{
+		User:       c.Database.User,
+		Password:   url.QueryEscape(c.Database.Password),
+		Host:       c.Database.Host,
+		Port:       c.Database.Port,
+		Name:       c.Database.Name,
+		SSLMode:    c.Database.SSLMode,
+		MaxConns:   int32(c.Database.MaxConns),
+		MinConns:   int32(c.Database.MinConns),
+		Migrations: domain.MigrationsMode(c.Database.Migrations),
+	}
+	switch config.Database.Migrations {
+	case domain.MigrationsModeAuto, domain.MigrationsModeCheck:
+	default:
+		add("database.migrations", "must be one of auto, check")
+	}
+
+	// jwt
+	required("jwt.secret", c.JWT.Secret)
+	required("jwt.issuer", c.JWT.Issuer)
+	required("jwt.audience", c.JWT.Audience)
+	config.JWT = domain.JWtConfig{
+		Secret:         []byte(c.JWT.Secret),
+		ExpirationTime: duration("jwt.expiration_time", c.JWT.ExpirationTime, false),
+		Issuer:         c.JWT.Issuer,
+		Audience:       c.JWT.Audience,
+	}
+
+	// рейт лимитер: встроенные политики по rate/burst, для логина и регистрации строже
+	limiterRate := duration("rate_limiter.rate", c.RateLimiter.Rate, false)
+	if c.RateLimiter.Burst < 1 {
+		add("rate_limiter.burst", "must be at least 1")
+	}
+	policies := map[string]domain.RateLimitPolicy{
+		domain.RateLimitPolicyUser: {Name: domain.RateLimitPolicyUser, Rate: limiterRate, Burst: c.RateLimiter.Burst, Key: domain.RateLimitKeyUser},
+		domain.RateLimitPolicyAuth: {Name: domain.RateLimitPolicyAuth, Rate: defaultAuthPolicyRate, Burst: defaultAuthPolicyBurst, Key: domain.RateLimitKeyIP},
+	}
+	for i, policy := range c.RateLimiter.Policies {
+		field := fmt.Sprintf("rate_limiter.policies[%d]", i)
+		if policy.Name == "" {
+			add(field, "name is required")
+			continue
+		}
+		rate, err := time.ParseDuration(policy.Rate)
+		if err != nil || rate <= 0 {
+			add(field, fmt.Sprintf("wrong rate in policy %q", policy.Name))
+		}
+		if policy.Burst < 1 {
+			add(field, fmt.Sprintf("wrong burst in policy %q", policy.Name))
+		}
+		key := domain.RateLimitKey(policy.Key)
+		if !key.IsValid() {
+			add(field, fmt.Sprintf("wrong key in policy %q, must be one of ip, user, user_ip", policy.Name))
+		}
+		policies[policy.Name] = domain.RateLimitPolicy{Name: policy.Name, Rate: rate, Burst: policy.Burst, Key: key}
+	}
+	// группы роутов: встроенные назначения, поверх них - из конфига; неизвестная группа или политика - ошибка
+	groups := make(map[string]string, len(defaultRateLimitGroups))
+	for group, policy := range defaultRateLimitGroups {
+		groups[group] = policy
+	}
+	for _, group := range slices.Sorted(maps.Keys(c.RateLimiter.Groups)) {
+		policy := c.RateLimiter.Groups[group]
+		if _, ok := defaultRateLimitGroups[group]; !ok {
+			add("rate_limiter.groups", fmt.Sprintf("unknown group %q", group))
+			continue
+		}
+		if _, ok := policies[policy]; !ok {
+			add("rate_limiter.groups", fmt.Sprintf("unknown policy %q for group %q", policy, group))
+			continue
+		}
+		groups[group] = policy
+	}
+	config.RateLimiter = domain.RateLimiterConfig{
+		Policies:    policies,
+		Groups:      groups,
+		IdleTimeout: duration("rate_limiter.idle_timeout", c.RateLimiter.IdleTimeout, false),
+		Store:       domain.LimiterStore(c.RateLimiter.Store),
+	}
+	switch config.RateLimiter.Store {
+	case domain.LimiterStoreMemory, domain.LimiterStorePostgres:
+	default:
+		add("rate_limiter.store", "must be one of memory, postgres")
+	}
+
+	// логгер
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		add("log.level", "must be one of debug, info, warn, error")
+	}
+	config.Logger = domain.LoggerConfig{
+		Level:  c.Log.Level,
+		Format: domain.LogFormat(c.Log.Format),
+	}
+	switch config.Logger.Format {
+	case domain.LogFormatJSON, domain.LogFormatText:
 	default:
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, fmt.Errorf("wrong server mode field")
-	}
-	serverConfig.ServerMode = sm
-
-	// загрузка конфига базы данных
-	var databaseConfig domain.DataBaseConfig
-	databaseUser := os.Getenv("DB_USER")
-	databasePassword := os.Getenv("DB_PASSWORD")
-	databaseHost := os.Getenv("DB_HOST")
-	databasePort := os.Getenv("DB_PORT")
-	databaseName := os.Getenv("DB_NAME")
-	if databaseUser == "" || databasePassword == "" || databaseHost == "" || databasePort == "" || databaseName == "" {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, fmt.Errorf("lack of environment variables")
-	}
-	databaseConfig.User = databaseUser
-	databaseConfig.Password = url.QueryEscape(databasePassword)
-	databaseConfig.Host = databaseHost
-	databaseConfig.Port = databasePort
-	databaseConfig.Name = databaseName
-
-	// загрузка конфига jwt сервиса
-	var jwtConfig domain.JWtConfig
-	jwtSecret := os.Getenv("JWT_SECRET")
-	jwtExpirationTime := os.Getenv("JWT_EXPIRATIONTIME")
-	jwtIssuer := os.Getenv("JWT_ISSUER")
-	jwtAudience := os.Getenv("JWT_AUDIENCE")
-	if jwtSecret == "" || jwtExpirationTime == "" || jwtIssuer == "" || jwtAudience == "" {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, fmt.Errorf("lack of environment variables")
-	}
-	jwtConfig.Secret = []byte(jwtSecret)
-	jwtValidatedExpirationTime, err := time.ParseDuration(jwtExpirationTime)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	jwtConfig.ExpirationTime = jwtValidatedExpirationTime
-	jwtConfig.Issuer = jwtIssuer
-	jwtConfig.Audience = jwtAudience
-
-	// загрузка конфига рейт лимитера
-	limiterRate := os.Getenv("LIMITER_RATE")
-	limiterBurst := os.Getenv("LIMITER_BURST")
-	if limiterRate == "" || limiterBurst == "" {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, fmt.Errorf("lack of environment variables")
-	}
-	parsedLimiterRate, err := time.ParseDuration(limiterRate)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	parsedLimiterBurst, err := strconv.Atoi(limiterBurst)
-	if err != nil {
-		return domain.ServerConfig{}, domain.JWtConfig{}, domain.DataBaseConfig{}, domain.RateLimiterConfig{}, err
-	}
-	var rateLimiterConfig domain.RateLimiterConfig
-	rateLimiterConfig.Rate = parsedLimiterRate
-	rateLimiterConfig.Burst = parsedLimiterBurst
-	return serverConfig, jwtConfig, databaseConfig, rateLimiterConfig, nil
+		add("log.format", "must be one of json, text")
+	}
+
+	// трейсинг
+	config.Tracing = domain.TracingConfig{
+		Exporter:    domain.TraceExporter(c.Tracing.Exporter),
+		ServiceName: c.Tracing.ServiceName,
+		SampleRatio: c.Tracing.SampleRatio,
+	}
+	switch config.Tracing.Exporter {
+	case domain.TraceExporterNone, domain.TraceExporterStdout, domain.TraceExporterOTLP:
+	default:
+		add("tracing.exporter", "must be one of none, stdout, otlp")
+	}
+	required("tracing.service_name", c.Tracing.ServiceName)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio", "must be between 0 and 1")
+	}
+
+	// безопасность
+	if c.Security.BcryptCost < bcrypt.MinCost || c.Security.BcryptCost > bcrypt.MaxCost {
+		add("security.bcrypt_cost", fmt.Sprintf("must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+	if c.Security.PasswordMinLength < 1 {
+		add("security.password_min_length", "must be at least 1")
+	}
+	if c.Security.PasswordMaxLength < c.Security.PasswordMinLength || c.Security.PasswordMaxLength > 72 {
+		add("security.password_max_length", "must be between password_min_length and 72 (bcrypt limit)")
+	}
+	if c.Security.PasswordBlocklistFile != "" {
+		if _, err := os.Stat(c.Security.PasswordBlocklistFile); err != nil {
+			add("security.password_blocklist_file", fmt.Sprintf("file is not readable: %v", err))
+		}
+	}
+	config.Security = domain.SecurityConfig{
+		BcryptCost: c.Security.BcryptCost,
+		PasswordPolicy: domain.PasswordPolicy{
+			MinLength:     c.Security.PasswordMinLength,
+			MaxLength:     c.Security.PasswordMaxLength,
+			RequireUpper:  c.Security.PasswordRequireUpper,
+			RequireLower:  c.Security.PasswordRequireLower,
+			RequireDigit:  c.Security.PasswordRequireDigit,
+			RequireSymbol: c.Security.PasswordRequireSymbol,
+			BlocklistFile: c.Security.PasswordBlocklistFile,
+		},
+	}
+
+	// пороги алертов в тех же диапазонах, что и записи
+	if c.Alert.LowMood < int(domain.MoodMin) || c.Alert.LowMood > int(domain.MoodMax) {
+		add("alert.low_mood", fmt.Sprintf("must be between %v and %v", domain.MoodMin, domain.MoodMax))
+	}
+	if !domain.ValidSleepHours(c.Alert.LowSleepHours) {
+		add("alert.low_sleep_hours", fmt.Sprintf("must be between %v and %v", domain.SleepHoursMin, domain.SleepHoursMax))
+	}
+	if c.Alert.HighLoad < int(domain.LoadMin) || c.Alert.HighLoad > int(domain.LoadMax) {
+		add("alert.high_load", fmt.Sprintf("must be between %v and %v", domain.LoadMin, domain.LoadMax))
+	}
+	config.Alert = domain.AlertConfig{
+		LowMood:       int16(c.Alert.LowMood),
+		LowSleepHours: c.Alert.LowSleepHours,
+		HighLoad:      int16(c.Alert.HighLoad),
+	}
+
+	// cors
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				add("cors.allowed_origins", "\"*\" can not be used with allow_credentials")
+			}
+			continue
+		}
+		if parsed, err := url.Parse(origin); err != nil || parsed.Scheme == "" || parsed.Host == "" || parsed.Path != "" {
+			add("cors.allowed_origins", fmt.Sprintf("invalid origin %q, expected scheme://host[:port]", origin))
+		}
+	}
+	config.CORS = domain.CORSConfig{
+		AllowedOrigins:   c.CORS.AllowedOrigins,
+		AllowedMethods:   c.CORS.AllowedMethods,
+		AllowedHeaders:   c.CORS.AllowedHeaders,
+		ExposedHeaders:   c.CORS.ExposedHeaders,
+		AllowCredentials: c.CORS.AllowCredentials,
+		MaxAge:           duration("cors.max_age", c.CORS.MaxAge, true),
+	}
+
+	// язык по умолчанию должен быть в каталоге
+	if err := i18n.Check(); err != nil {
+		add("i18n.default_language", fmt.Sprintf("message catalog is broken: %v", err))
+	} else if !i18n.Supported(c.I18n.DefaultLanguage) {
+		add("i18n.default_language", fmt.Sprintf("must be one of %v", strings.Join(i18n.Languages(), ", ")))
+	}
+	config.I18n = domain.I18nConfig{
+		DefaultLanguage: c.I18n.DefaultLanguage,
+	}
+
+	// события
+	if c.Events.MaxStreams < 1 {
+		add("events.max_streams", "must be at least 1")
+	}
+	config.Events = domain.EventsConfig{
+		HeartbeatInterval: duration("events.heartbeat_interval", c.Events.HeartbeatInterval, false),
+		Retention:         duration("events.retention", c.Events.Retention, false),
+		MaxStreams:        c.Events.MaxStreams,
+	}
+
+	// напоминания
+	if c.Reminders.BatchSize < 1 {
+		add("reminders.batch_size", "must be at least 1")
+	}
+	if c.Reminders.MaxAttempts < 1 {
+		add("reminders.max_attempts", "must be at least 1")
+	}
+	switch domain.ReminderNotifier(c.Reminders.Notifier) {
+	case domain.ReminderNotifierLog:
+	case domain.ReminderNotifierWebhook:
+		if parsed, err := url.Parse(c.Reminders.WebhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add("reminders.webhook_url", "must be an http(s) url when notifier is webhook")
+		}
+	default:
+		add("reminders.notifier", "must be one of log, webhook")
+	}
+	config.Reminders = domain.RemindersConfig{
+		Enabled:        c.Reminders.Enabled,
+		CheckInterval:  duration("reminders.check_interval", c.Reminders.CheckInterval, false),
+		BatchSize:      c.Reminders.BatchSize,
+		MaxAttempts:    c.Reminders.MaxAttempts,
+		RetryDelay:     duration("reminders.retry_delay", c.Reminders.RetryDelay, false),
+		Notifier:       domain.ReminderNotifier(c.Reminders.Notifier),
+		WebhookURL:     c.Reminders.WebhookURL,
+		WebhookTimeout: duration("reminders.webhook_timeout", c.Reminders.WebhookTimeout, false),
+	}
+
+	// outbox
+	if c.Outbox.BatchSize < 1 {
+		add("outbox.batch_size", "must be at least 1")
+	}
+	if c.Outbox.MaxAttempts < 1 {
+		add("outbox.max_attempts", "must be at least 1")
+	}
+	config.Outbox = domain.OutboxConfig{
+		PollInterval: duration("outbox.poll_interval", c.Outbox.PollInterval, false),
+		BatchSize:    c.Outbox.BatchSize,
+		MaxAttempts:  c.Outbox.MaxAttempts,
+		Retention:    duration("outbox.retention", c.Outbox.Retention, false),
+	}
+
+	// idempotency
+	config.Idempotency = domain.IdempotencyConfig{
+		TTL: duration("idempotency.ttl", c.Idempotency.TTL, false),
+	}
+	return config
+}
+
+// parseRateLimitGroups разбирает строку вида "notes:export,events:user", имена проверяет validate
+func parseRateLimitGroups(raw string) (map[string]string, error) {
+	groups := map[string]string{}
+	for _, item := range splitList(raw) {
+		group, policy, ok := strings.Cut(item, ":")
+		if !ok || group == "" || policy == "" {
+			return nil, fmt.Errorf("wrong limiter group %q, expected group:policy", item)
+		}
+		groups[group] = policy
+	}
+	return groups, nil
+}
+
+// parseRateLimitPolicies разбирает строку вида "auth:1m:5:ip,user:10s:20:user", значения проверяет validate
+func parseRateLimitPolicies(raw string) ([]policySection, error) {
+	policies := []policySection{}
+	for _, item := range splitList(raw) {
+		parts := strings.Split(item, ":")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("wrong limiter policy %q, expected name:rate:burst:key", item)
+		}
+		burst, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("wrong burst in limiter policy %q", parts[0])
+		}
+		policies = append(policies, policySection{
+			Name:  parts[0],
+			Rate:  parts[1],
+			Burst: burst,
+			Key:   parts[3],
+		})
+	}
+	return policies, nil
 }
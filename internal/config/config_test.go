@@ -0,0 +1,206 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write test file - %v", err)
+	}
+	return path
+}
+
+// Тест Load - все проблемы возвращаются разом, с именем поля и env переменной
+func TestLoadReportsAllProblems(t *testing.T) {
+	// preparing
+	t.Setenv("SERVER_READTIMEOUT", "five seconds")
+	t.Setenv("SERVER_MODE", "prod")
+	t.Setenv("SECURITY_BCRYPTCOST", "100")
+	t.Setenv("SERVER_LEGACYSUNSET", "2026-01-01")
+	t.Setenv("REMINDERS_NOTIFIER", "webhook")
+
+	// test
+	_, err := Load("")
+
+	// assert
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected validation error, got - %v", err)
+	}
+	expected := []string{
+		"server.read_timeout (SERVER_READTIMEOUT)",
+		"server.mode (SERVER_MODE)",
+		"database.user (DB_USER): is required",
+		"database.password (DB_PASSWORD): is required",
+		"database.name (DB_NAME): is required",
+		"jwt.secret (JWT_SECRET): is required",
+		"security.bcrypt_cost (SECURITY_BCRYPTCOST)",
+		"server.legacy_sunset (SERVER_LEGACYSUNSET): must be after legacy_deprecated_at",
+		"reminders.webhook_url (REMINDERS_WEBHOOKURL)",
+	}
+	for _, problem := range expected {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected problem was - %v", problem)
+		}
+	}
+	if len(validationError.Problems) != len(expected) {
+		t.Errorf("expected %v problems, got - %v", len(expected), validationError.Problems)
+	}
+}
+
+// Тест Load - значения по умолчанию, файл yaml, env поверх файла и секрет из NAME_FILE
+func TestLoadYamlWithEnvOverrides(t *testing.T) {
+	// preparing
+	path := writeTestFile(t, "config.yaml", `
+server:
+  address: ":8081"
+database:
+  user: chopper
+  name: chopper_database
+  max_conns: 20
+jwt:
+  secret: from-file
+rate_limiter:
+  policies:
+    - name: export
+      rate: 1m
+      burst: 2
+      key: user
+  groups:
+    notes: export
+`)
+	t.Setenv("DB_HOST", "postgres")
+	t.Setenv("DB_PASSWORD_FILE", writeTestFile(t, "db_password", "s3cr#t\n"))
+	t.Setenv("JWT_SECRET", "from-env")
+
+	// test
+	config, err := Load(path)
+
+	// assert
+	if err != nil {
+		t.Fatalf("error was not expected - %v", err)
+	}
+	if config.Server.Address != ":8081" || config.Server.ReadTimeout != 5*time.Second {
+		t.Errorf("expected address from file and default read timeout")
+	}
+	if config.Database.Host != "postgres" || config.Database.MaxConns != 20 {
+		t.Errorf("expected host from env and max conns from file")
+	}
+	if config.Database.Password != "s3cr%23t" {
+		t.Errorf("expected escaped password from file, got - %v", config.Database.Password)
+	}
+	if string(config.JWT.Secret) != "from-env" {
+		t.Errorf("env must override file")
+	}
+	if config.Security.BcryptCost != 10 {
+		t.Errorf("expected default bcrypt cost was - %v", 10)
+	}
+	if policy, ok := config.RateLimiter.Policies["export"]; !ok || policy.Rate != time.Minute {
+		t.Errorf("expected policy from file")
+	}
+	if config.RateLimiter.Groups["notes"] != "export" || config.RateLimiter.Groups["users_public"] != "auth" {
+		t.Errorf("expected group from file on top of built-in groups, got - %v", config.RateLimiter.Groups)
+	}
+}
+
+// Тест Load - группе можно назначить только известную политику, неизвестная группа - ошибка
+func TestLoadRateLimitGroupsErrors(t *testing.T) {
+	// preparing
+	t.Setenv("DB_USER", "chopper")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_NAME", "chopper_database")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("LIMITER_GROUPS", "reports:user,notes:export")
+
+	// test
+	_, err := Load("")
+
+	// assert
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected validation error, got - %v", err)
+	}
+	for _, problem := range []string{`unknown group "reports"`, `unknown policy "export" for group "notes"`} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected problem was - %v", problem)
+		}
+	}
+}
+
+// Тест Load - toml файл и неизвестное поле в нем
+func TestLoadToml(t *testing.T) {
+	// preparing
+	valid := writeTestFile(t, "config.toml", `
+[database]
+user = "chopper"
+password = "secret"
+name = "chopper_database"
+
+[jwt]
+secret = "secret"
+
+[alert]
+low_sleep_hours = 6.5
+`)
+	unknown := writeTestFile(t, "unknown.toml", `
+[database]
+usr = "chopper"
+`)
+
+	// test
+	config, err := Load(valid)
+	_, unknownErr := Load(unknown)
+
+	// assert
+	if err != nil {
+		t.Fatalf("error was not expected - %v", err)
+	}
+	if config.Alert.LowSleepHours != 6.5 {
+		t.Errorf("expected low sleep hours was - %v", 6.5)
+	}
+	if unknownErr == nil || !strings.Contains(unknownErr.Error(), "database.usr") {
+		t.Errorf("expected unknown field error, got - %v", unknownErr)
+	}
+}
+
+// Тест RestartRequired - подменяемые на лету настройки не считаются, остальные перечисляются
+func TestRestartRequired(t *testing.T) {
+	// preparing
+	t.Setenv("DB_USER", "chopper")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_NAME", "chopper_database")
+	t.Setenv("JWT_SECRET", "secret")
+	running, err := Load("")
+	if err != nil {
+		t.Fatalf("error was not expected - %v", err)
+	}
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("ALERT_LOWMOOD", "3")
+	t.Setenv("CORS_ALLOWEDORIGINS", "https://app.example.com")
+	t.Setenv("LIMITER_POLICIES", "export:1m:2:user")
+	t.Setenv("SERVER_ADDRESS", ":8081")
+	t.Setenv("JWT_SECRET", "rotated")
+	next, err := Load("")
+	if err != nil {
+		t.Fatalf("error was not expected - %v", err)
+	}
+
+	// test
+	changed := RestartRequired(running, next)
+	applied := ApplyReloadable(running, next)
+
+	// assert
+	if strings.Join(changed, ",") != "Server.Address,JWT.Secret" {
+		t.Errorf("expected changed fields were - %v, got - %v", "Server.Address,JWT.Secret", changed)
+	}
+	if applied.Logger.Level != "debug" || applied.Alert.LowMood != 3 || applied.Server.Address != running.Server.Address {
+		t.Errorf("only reloadable settings must be applied")
+	}
+}
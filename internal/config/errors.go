@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError собирает все проблемы конфига, чтобы их можно было исправить за один заход
+type ValidationError struct {
+	Problems []string
+}
+
+func (v *ValidationError) add(field, env, message string) {
+	if env != "" {
+		field = fmt.Sprintf("%v (%v)", field, env)
+	}
+	v.Problems = append(v.Problems, field+": "+message)
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config, %d problem(s):\n  - %v", len(v.Problems), strings.Join(v.Problems, "\n  - "))
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"chopper/internal/delivery/grpc/chopperv1"
+	"chopper/internal/i18n"
+	"chopper/internal/usecase"
+	"context"
+)
+
+type alertServer struct {
+	chopperv1.UnimplementedAlertServiceServer
+	alertService *usecase.AlertService
+}
+
+func newAlertServer(alertService *usecase.AlertService) *alertServer {
+	return &alertServer{
+		alertService: alertService,
+	}
+}
+
+func (a *alertServer) GetLastSevenDays(ctx context.Context, req *chopperv1.GetLastSevenDaysRequest) (*chopperv1.GetLastSevenDaysResponse, error) {
+	identity, err := currentIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	alertMessage, err := a.alertService.GetLastSevenDays(ctx, identity.UserId)
+	if err != nil {
+		return nil, statusError(ctx, err, "GetLastSevenDays")
+	}
+	return &chopperv1.GetLastSevenDaysResponse{
+		Key:   string(alertMessage),
+		Alert: i18n.T(ctx, string(alertMessage)),
+	}, nil
+}
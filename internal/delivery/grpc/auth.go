@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"chopper/internal/delivery/grpc/chopperv1"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/usecase"
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// methodAccess - кто может вызвать метод; без записи в methodAccesses метод закрыт для всех
+type methodAccess struct {
+	public bool
+	scope  domain.Scope
+}
+
+// methodAccesses - то же разделение, что у групп роутов в HTTP v1
+var methodAccesses = map[string]methodAccess{
+	chopperv1.UserService_Register_FullMethodName: {public: true},
+	chopperv1.UserService_Login_FullMethodName:    {public: true},
+	chopperv1.UserService_WhoAmI_FullMethodName:   {scope: domain.ScopeUsersRead},
+
+	chopperv1.DailyNoteService_CreateNote_FullMethodName:       {scope: domain.ScopeNotesWrite},
+	chopperv1.DailyNoteService_ChangeMood_FullMethodName:       {scope: domain.ScopeNotesWrite},
+	chopperv1.DailyNoteService_ChangeSleepHours_FullMethodName: {scope: domain.ScopeNotesWrite},
+	chopperv1.DailyNoteService_ChangeLoad_FullMethodName:       {scope: domain.ScopeNotesWrite},
+
+	chopperv1.AlertService_GetLastSevenDays_FullMethodName: {scope: domain.ScopeAlertsRead},
+}
+
+type identityKey struct{}
+
+// authInterceptor - аналог AuthMiddleware: metadata authorization: Bearer <JWT или персональный токен>
+type authInterceptor struct {
+	authenticator *usecase.Authenticator
+}
+
+func newAuthInterceptor(authenticator *usecase.Authenticator) *authInterceptor {
+	return &authInterceptor{
+		authenticator: authenticator,
+	}
+}
+
+func (a *authInterceptor) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	access, ok := methodAccesses[info.FullMethod]
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, info.FullMethod)
+	}
+	if access.public {
+		return handler(ctx, req)
+	}
+	header := firstMetadata(ctx, "authorization")
+	if header == "" {
+		return nil, unauthenticated(ctx, i18n.Msg("detail.no_token"))
+	}
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, unauthenticated(ctx, i18n.Msg("detail.bearer_format"))
+	}
+	identity, err := a.authenticator.Authenticate(ctx, strings.TrimPrefix(header, "Bearer "))
+	if err != nil && errors.Is(err, usecase.ErrUnauthenticated) {
+		logger.FromContext(ctx).WithError(err).Info("token rejected")
+		return nil, unauthenticated(ctx, i18n.Msg("detail.invalid_token"))
+	} else if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("authentication failed")
+		return nil, status.Error(codes.Internal, i18n.Msg("problem.internal-error").In(i18n.Language(ctx)))
+	}
+	// сохраненная настройка важнее accept-language
+	if identity.Language != "" {
+		ctx = i18n.WithLanguage(ctx, identity.Language)
+	}
+	if !identity.Allows(access.scope) {
+		lang := i18n.Language(ctx)
+		return nil, status.Error(codes.PermissionDenied, i18n.Msg("detail.scope_required", "scope", access.scope).In(lang))
+	}
+	return handler(context.WithValue(ctx, identityKey{}, identity), req)
+}
+
+func unauthenticated(ctx context.Context, detail i18n.Message) error {
+	return status.Error(codes.Unauthenticated, detail.In(i18n.Language(ctx)))
+}
+
+// currentIdentity достает пользователя, которого положил authInterceptor
+func currentIdentity(ctx context.Context) (domain.Identity, error) {
+	identity, ok := ctx.Value(identityKey{}).(domain.Identity)
+	if !ok {
+		return domain.Identity{}, unauthenticated(ctx, i18n.Msg("detail.no_user"))
+	}
+	return identity, nil
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: chopper/v1/alerts.proto
+
+package chopperv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetLastSevenDaysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLastSevenDaysRequest) Reset() {
+	*x = GetLastSevenDaysRequest{}
+	mi := &file_chopper_v1_alerts_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLastSevenDaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLastSevenDaysRequest) ProtoMessage() {}
+
+func (x *GetLastSevenDaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_alerts_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLastSevenDaysRequest.ProtoReflect.Descriptor instead.
+func (*GetLastSevenDaysRequest) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_alerts_proto_rawDescGZIP(), []int{0}
+}
+
+// alert - текст на языке запроса, key - ключ сообщения для клиентов со своими переводами
+type GetLastSevenDaysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Alert         string                 `protobuf:"bytes,2,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLastSevenDaysResponse) Reset() {
+	*x = GetLastSevenDaysResponse{}
+	mi := &file_chopper_v1_alerts_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLastSevenDaysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLastSevenDaysResponse) ProtoMessage() {}
+
+func (x *GetLastSevenDaysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_alerts_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLastSevenDaysResponse.ProtoReflect.Descriptor instead.
+func (*GetLastSevenDaysResponse) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_alerts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetLastSevenDaysResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetLastSevenDaysResponse) GetAlert() string {
+	if x != nil {
+		return x.Alert
+	}
+	return ""
+}
+
+var File_chopper_v1_alerts_proto protoreflect.FileDescriptor
+
+const file_chopper_v1_alerts_proto_rawDesc = "" +
+	"\n" +
+	"\x17chopper/v1/alerts.proto\x12\n" +
+	"chopper.v1\"\x19\n" +
+	"\x17GetLastSevenDaysRequest\"B\n" +
+	"\x18GetLastSevenDaysResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05alert\x18\x02 \x01(\tR\x05alert2m\n" +
+	"\fAlertService\x12]\n" +
+	"\x10GetLastSevenDays\x12#.chopper.v1.GetLastSevenDaysRequest\x1a$.chopper.v1.GetLastSevenDaysResponseB4Z2chopper/internal/delivery/grpc/chopperv1;chopperv1b\x06proto3"
+
+var (
+	file_chopper_v1_alerts_proto_rawDescOnce sync.Once
+	file_chopper_v1_alerts_proto_rawDescData []byte
+)
+
+func file_chopper_v1_alerts_proto_rawDescGZIP() []byte {
+	file_chopper_v1_alerts_proto_rawDescOnce.Do(func() {
+		file_chopper_v1_alerts_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_chopper_v1_alerts_proto_rawDesc), len(file_chopper_v1_alerts_proto_rawDesc)))
+	})
+	return file_chopper_v1_alerts_proto_rawDescData
+}
+
+var file_chopper_v1_alerts_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_chopper_v1_alerts_proto_goTypes = []any{
+	(*GetLastSevenDaysRequest)(nil),  // 0: chopper.v1.GetLastSevenDaysRequest
+	(*GetLastSevenDaysResponse)(nil), // 1: chopper.v1.GetLastSevenDaysResponse
+}
+var file_chopper_v1_alerts_proto_depIdxs = []int32{
+	0, // 0: chopper.v1.AlertService.GetLastSevenDays:input_type -> chopper.v1.GetLastSevenDaysRequest
+	1, // 1: chopper.v1.AlertService.GetLastSevenDays:output_type -> chopper.v1.GetLastSevenDaysResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_chopper_v1_alerts_proto_init() }
+func file_chopper_v1_alerts_proto_init() {
+	if File_chopper_v1_alerts_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chopper_v1_alerts_proto_rawDesc), len(file_chopper_v1_alerts_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_chopper_v1_alerts_proto_goTypes,
+		DependencyIndexes: file_chopper_v1_alerts_proto_depIdxs,
+		MessageInfos:      file_chopper_v1_alerts_proto_msgTypes,
+	}.Build()
+	File_chopper_v1_alerts_proto = out.File
+	file_chopper_v1_alerts_proto_goTypes = nil
+	file_chopper_v1_alerts_proto_depIdxs = nil
+}
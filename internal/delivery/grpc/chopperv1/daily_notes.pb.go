@@ -0,0 +1,605 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: chopper/v1/daily_notes.proto
+
+package chopperv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mood          int32                  `protobuf:"varint,1,opt,name=mood,proto3" json:"mood,omitempty"`
+	SleepHours    float64                `protobuf:"fixed64,2,opt,name=sleep_hours,json=sleepHours,proto3" json:"sleep_hours,omitempty"`
+	Load          int32                  `protobuf:"varint,3,opt,name=load,proto3" json:"load,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteRequest) Reset() {
+	*x = CreateNoteRequest{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteRequest) ProtoMessage() {}
+
+func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateNoteRequest) GetMood() int32 {
+	if x != nil {
+		return x.Mood
+	}
+	return 0
+}
+
+func (x *CreateNoteRequest) GetSleepHours() float64 {
+	if x != nil {
+		return x.SleepHours
+	}
+	return 0
+}
+
+func (x *CreateNoteRequest) GetLoad() int32 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+// version - версия созданной записи для expected_version
+type CreateNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteResponse) Reset() {
+	*x = CreateNoteResponse{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteResponse) ProtoMessage() {}
+
+func (x *CreateNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteResponse.ProtoReflect.Descriptor instead.
+func (*CreateNoteResponse) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateNoteResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ChangeMoodRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// date - день записи, время суток не учитывается
+	Date *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Mood int32                  `protobuf:"varint,2,opt,name=mood,proto3" json:"mood,omitempty"`
+	// expected_version - версия записи, которую клиент видел последней, обязательна
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeMoodRequest) Reset() {
+	*x = ChangeMoodRequest{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeMoodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeMoodRequest) ProtoMessage() {}
+
+func (x *ChangeMoodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeMoodRequest.ProtoReflect.Descriptor instead.
+func (*ChangeMoodRequest) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChangeMoodRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ChangeMoodRequest) GetMood() int32 {
+	if x != nil {
+		return x.Mood
+	}
+	return 0
+}
+
+func (x *ChangeMoodRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// answer - текст на языке запроса, key - ключ сообщения для клиентов со своими переводами, version - новая версия записи
+type ChangeMoodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Answer        string                 `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeMoodResponse) Reset() {
+	*x = ChangeMoodResponse{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeMoodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeMoodResponse) ProtoMessage() {}
+
+func (x *ChangeMoodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeMoodResponse.ProtoReflect.Descriptor instead.
+func (*ChangeMoodResponse) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChangeMoodResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ChangeMoodResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ChangeMoodResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ChangeSleepHoursRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Date            *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	SleepHours      float64                `protobuf:"fixed64,2,opt,name=sleep_hours,json=sleepHours,proto3" json:"sleep_hours,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeSleepHoursRequest) Reset() {
+	*x = ChangeSleepHoursRequest{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeSleepHoursRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeSleepHoursRequest) ProtoMessage() {}
+
+func (x *ChangeSleepHoursRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeSleepHoursRequest.ProtoReflect.Descriptor instead.
+func (*ChangeSleepHoursRequest) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChangeSleepHoursRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ChangeSleepHoursRequest) GetSleepHours() float64 {
+	if x != nil {
+		return x.SleepHours
+	}
+	return 0
+}
+
+func (x *ChangeSleepHoursRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ChangeSleepHoursResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Answer        string                 `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeSleepHoursResponse) Reset() {
+	*x = ChangeSleepHoursResponse{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeSleepHoursResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeSleepHoursResponse) ProtoMessage() {}
+
+func (x *ChangeSleepHoursResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeSleepHoursResponse.ProtoReflect.Descriptor instead.
+func (*ChangeSleepHoursResponse) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChangeSleepHoursResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ChangeSleepHoursResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ChangeSleepHoursResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ChangeLoadRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Date            *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Load            int32                  `protobuf:"varint,2,opt,name=load,proto3" json:"load,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeLoadRequest) Reset() {
+	*x = ChangeLoadRequest{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeLoadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeLoadRequest) ProtoMessage() {}
+
+func (x *ChangeLoadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeLoadRequest.ProtoReflect.Descriptor instead.
+func (*ChangeLoadRequest) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChangeLoadRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ChangeLoadRequest) GetLoad() int32 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+func (x *ChangeLoadRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ChangeLoadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Answer        string                 `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeLoadResponse) Reset() {
+	*x = ChangeLoadResponse{}
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeLoadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeLoadResponse) ProtoMessage() {}
+
+func (x *ChangeLoadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chopper_v1_daily_notes_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeLoadResponse.ProtoReflect.Descriptor instead.
+func (*ChangeLoadResponse) Descriptor() ([]byte, []int) {
+	return file_chopper_v1_daily_notes_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ChangeLoadResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ChangeLoadResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ChangeLoadResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_chopper_v1_daily_notes_proto protoreflect.FileDescriptor
+
+const file_chopper_v1_daily_notes_proto_rawDesc = "" +
+	"\n" +
+	"\x1cchopper/v1/daily_notes.proto\x12\n" +
+	"chopper.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\\\n" +
+	"\x11CreateNoteRequest\x12\x12\n" +
+	"\x04mood\x18\x01 \x01(\x05R\x04mood\x12\x1f\n" +
+	"\vsleep_hours\x18\x02 \x01(\x01R\n" +
+	"sleepHours\x12\x12\n" +
+	"\x04load\x18\x03 \x01(\x05R\x04load\".\n" +
+	"\x12CreateNoteResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\"\x82\x01\n" +
+	"\x11ChangeMoodRequest\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x12\n" +
+	"\x04mood\x18\x02 \x01(\x05R\x04mood\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"X\n" +
+	"\x12ChangeMoodResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"\x95\x01\n" +
+	"\x17ChangeSleepHoursRequest\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x1f\n" +
+	"\vsleep_hours\x18\x02 \x01(\x01R\n" +
+	"sleepHours\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"^\n" +
+	"\x18ChangeSleepHoursResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"\x82\x01\n" +
+	"\x11ChangeLoadRequest\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x12\n" +
+	"\x04load\x18\x02 \x01(\x05R\x04load\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"X\n" +
+	"\x12ChangeLoadResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion2\xd8\x02\n" +
+	"\x10DailyNoteService\x12K\n" +
+	"\n" +
+	"CreateNote\x12\x1d.chopper.v1.CreateNoteRequest\x1a\x1e.chopper.v1.CreateNoteResponse\x12K\n" +
+	"\n" +
+	"ChangeMood\x12\x1d.chopper.v1.ChangeMoodRequest\x1a\x1e.chopper.v1.ChangeMoodResponse\x12]\n" +
+	"\x10ChangeSleepHours\x12#.chopper.v1.ChangeSleepHoursRequest\x1a$.chopper.v1.ChangeSleepHoursResponse\x12K\n" +
+	"\n" +
+	"ChangeLoad\x12\x1d.chopper.v1.ChangeLoadRequest\x1a\x1e.chopper.v1.ChangeLoadResponseB4Z2chopper/internal/delivery/grpc/chopperv1;chopperv1b\x06proto3"
+
+var (
+	file_chopper_v1_daily_notes_proto_rawDescOnce sync.Once
+	file_chopper_v1_daily_notes_proto_rawDescData []byte
+)
+
+func file_chopper_v1_daily_notes_proto_rawDescGZIP() []byte {
+	file_chopper_v1_daily_notes_proto_rawDescOnce.Do(func() {
+		file_chopper_v1_daily_notes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_chopper_v1_daily_notes_proto_rawDesc), len(file_chopper_v1_daily_notes_proto_rawDesc)))
+	})
+	return file_chopper_v1_daily_notes_proto_rawDescData
+}
+
+var file_chopper_v1_daily_notes_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_chopper_v1_daily_notes_proto_goTypes = []any{
+	(*CreateNoteRequest)(nil),        // 0: chopper.v1.CreateNoteRequest
+	(*CreateNoteResponse)(nil),       // 1: chopper.v1.CreateNoteResponse
+	(*ChangeMoodRequest)(nil),        // 2: chopper.v1.ChangeMoodRequest
+	(*ChangeMoodResponse)(nil),       // 3: chopper.v1.ChangeMoodResponse
+	(*ChangeSleepHoursRequest)(nil),  // 4: chopper.v1.ChangeSleepHoursRequest
+	(*ChangeSleepHoursResponse)(nil), // 5: chopper.v1.ChangeSleepHoursResponse
+	(*ChangeLoadRequest)(nil),        // 6: chopper.v1.ChangeLoadRequest
+	(*ChangeLoadResponse)(nil),       // 7: chopper.v1.ChangeLoadResponse
+	(*timestamppb.Timestamp)(nil),    // 8: google.protobuf.Timestamp
+}
+var file_chopper_v1_daily_notes_proto_depIdxs = []int32{
+	8, // 0: chopper.v1.ChangeMoodRequest.date:type_name -> google.protobuf.Timestamp
+	8, // 1: chopper.v1.ChangeSleepHoursRequest.date:type_name -> google.protobuf.Timestamp
+	8, // 2: chopper.v1.ChangeLoadRequest.date:type_name -> google.protobuf.Timestamp
+	0, // 3: chopper.v1.DailyNoteService.CreateNote:input_type -> chopper.v1.CreateNoteRequest
+	2, // 4: chopper.v1.DailyNoteService.ChangeMood:input_type -> chopper.v1.ChangeMoodRequest
+	4, // 5: chopper.v1.DailyNoteService.ChangeSleepHours:input_type -> chopper.v1.ChangeSleepHoursRequest
+	6, // 6: chopper.v1.DailyNoteService.ChangeLoad:input_type -> chopper.v1.ChangeLoadRequest
+	1, // 7: chopper.v1.DailyNoteService.CreateNote:output_type -> chopper.v1.CreateNoteResponse
+	3, // 8: chopper.v1.DailyNoteService.ChangeMood:output_type -> chopper.v1.ChangeMoodResponse
+	5, // 9: chopper.v1.DailyNoteService.ChangeSleepHours:output_type -> chopper.v1.ChangeSleepHoursResponse
+	7, // 10: chopper.v1.DailyNoteService.ChangeLoad:output_type -> chopper.v1.ChangeLoadResponse
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_chopper_v1_daily_notes_proto_init() }
+func file_chopper_v1_daily_notes_proto_init() {
+	if File_chopper_v1_daily_notes_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chopper_v1_daily_notes_proto_rawDesc), len(file_chopper_v1_daily_notes_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_chopper_v1_daily_notes_proto_goTypes,
+		DependencyIndexes: file_chopper_v1_daily_notes_proto_depIdxs,
+		MessageInfos:      file_chopper_v1_daily_notes_proto_msgTypes,
+	}.Build()
+	File_chopper_v1_daily_notes_proto = out.File
+	file_chopper_v1_daily_notes_proto_goTypes = nil
+	file_chopper_v1_daily_notes_proto_depIdxs = nil
+}
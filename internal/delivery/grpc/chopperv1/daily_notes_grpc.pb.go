@@ -0,0 +1,245 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: chopper/v1/daily_notes.proto
+
+package chopperv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DailyNoteService_CreateNote_FullMethodName       = "/chopper.v1.DailyNoteService/CreateNote"
+	DailyNoteService_ChangeMood_FullMethodName       = "/chopper.v1.DailyNoteService/ChangeMood"
+	DailyNoteService_ChangeSleepHours_FullMethodName = "/chopper.v1.DailyNoteService/ChangeSleepHours"
+	DailyNoteService_ChangeLoad_FullMethodName       = "/chopper.v1.DailyNoteService/ChangeLoad"
+)
+
+// DailyNoteServiceClient is the client API for DailyNoteService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DailyNoteService - ежедневные записи, как /api/v1/notes; для персональных токенов нужен scope notes:write
+type DailyNoteServiceClient interface {
+	// CreateNote создает запись за сегодня
+	CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*CreateNoteResponse, error)
+	// Change* меняют запись, только если ее версия равна expected_version, как If-Match в HTTP;
+	// иначе FAILED_PRECONDITION, и клиент перечитывает запись
+	ChangeMood(ctx context.Context, in *ChangeMoodRequest, opts ...grpc.CallOption) (*ChangeMoodResponse, error)
+	ChangeSleepHours(ctx context.Context, in *ChangeSleepHoursRequest, opts ...grpc.CallOption) (*ChangeSleepHoursResponse, error)
+	ChangeLoad(ctx context.Context, in *ChangeLoadRequest, opts ...grpc.CallOption) (*ChangeLoadResponse, error)
+}
+
+type dailyNoteServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDailyNoteServiceClient(cc grpc.ClientConnInterface) DailyNoteServiceClient {
+	return &dailyNoteServiceClient{cc}
+}
+
+func (c *dailyNoteServiceClient) CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*CreateNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateNoteResponse)
+	err := c.cc.Invoke(ctx, DailyNoteService_CreateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dailyNoteServiceClient) ChangeMood(ctx context.Context, in *ChangeMoodRequest, opts ...grpc.CallOption) (*ChangeMoodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeMoodResponse)
+	err := c.cc.Invoke(ctx, DailyNoteService_ChangeMood_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dailyNoteServiceClient) ChangeSleepHours(ctx context.Context, in *ChangeSleepHoursRequest, opts ...grpc.CallOption) (*ChangeSleepHoursResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeSleepHoursResponse)
+	err := c.cc.Invoke(ctx, DailyNoteService_ChangeSleepHours_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dailyNoteServiceClient) ChangeLoad(ctx context.Context, in *ChangeLoadRequest, opts ...grpc.CallOption) (*ChangeLoadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeLoadResponse)
+	err := c.cc.Invoke(ctx, DailyNoteService_ChangeLoad_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DailyNoteServiceServer is the server API for DailyNoteService service.
+// All implementations must embed UnimplementedDailyNoteServiceServer
+// for forward compatibility.
+//
+// DailyNoteService - ежедневные записи, как /api/v1/notes; для персональных токенов нужен scope notes:write
+type DailyNoteServiceServer interface {
+	// CreateNote создает запись за сегодня
+	CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error)
+	// Change* меняют запись, только если ее версия равна expected_version, как If-Match в HTTP;
+	// иначе FAILED_PRECONDITION, и клиент перечитывает запись
+	ChangeMood(context.Context, *ChangeMoodRequest) (*ChangeMoodResponse, error)
+	ChangeSleepHours(context.Context, *ChangeSleepHoursRequest) (*ChangeSleepHoursResponse, error)
+	ChangeLoad(context.Context, *ChangeLoadRequest) (*ChangeLoadResponse, error)
+	mustEmbedUnimplementedDailyNoteServiceServer()
+}
+
+// UnimplementedDailyNoteServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDailyNoteServiceServer struct{}
+
+func (UnimplementedDailyNoteServiceServer) CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNote not implemented")
+}
+func (UnimplementedDailyNoteServiceServer) ChangeMood(context.Context, *ChangeMoodRequest) (*ChangeMoodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeMood not implemented")
+}
+func (UnimplementedDailyNoteServiceServer) ChangeSleepHours(context.Context, *ChangeSleepHoursRequest) (*ChangeSleepHoursResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeSleepHours not implemented")
+}
+func (UnimplementedDailyNoteServiceServer) ChangeLoad(context.Context, *ChangeLoadRequest) (*ChangeLoadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeLoad not implemented")
+}
+func (UnimplementedDailyNoteServiceServer) mustEmbedUnimplementedDailyNoteServiceServer() {}
+func (UnimplementedDailyNoteServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeDailyNoteServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DailyNoteServiceServer will
+// result in compilation errors.
+type UnsafeDailyNoteServiceServer interface {
+	mustEmbedUnimplementedDailyNoteServiceServer()
+}
+
+func RegisterDailyNoteServiceServer(s grpc.ServiceRegistrar, srv DailyNoteServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDailyNoteServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DailyNoteService_ServiceDesc, srv)
+}
+
+func _DailyNoteService_CreateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DailyNoteServiceServer).CreateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DailyNoteService_CreateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DailyNoteServiceServer).CreateNote(ctx, req.(*CreateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DailyNoteService_ChangeMood_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeMoodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DailyNoteServiceServer).ChangeMood(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DailyNoteService_ChangeMood_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DailyNoteServiceServer).ChangeMood(ctx, req.(*ChangeMoodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DailyNoteService_ChangeSleepHours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeSleepHoursRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DailyNoteServiceServer).ChangeSleepHours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DailyNoteService_ChangeSleepHours_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DailyNoteServiceServer).ChangeSleepHours(ctx, req.(*ChangeSleepHoursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DailyNoteService_ChangeLoad_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeLoadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DailyNoteServiceServer).ChangeLoad(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DailyNoteService_ChangeLoad_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DailyNoteServiceServer).ChangeLoad(ctx, req.(*ChangeLoadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DailyNoteService_ServiceDesc is the grpc.ServiceDesc for DailyNoteService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DailyNoteService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chopper.v1.DailyNoteService",
+	HandlerType: (*DailyNoteServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateNote",
+			Handler:    _DailyNoteService_CreateNote_Handler,
+		},
+		{
+			MethodName: "ChangeMood",
+			Handler:    _DailyNoteService_ChangeMood_Handler,
+		},
+		{
+			MethodName: "ChangeSleepHours",
+			Handler:    _DailyNoteService_ChangeSleepHours_Handler,
+		},
+		{
+			MethodName: "ChangeLoad",
+			Handler:    _DailyNoteService_ChangeLoad_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "chopper/v1/daily_notes.proto",
+}
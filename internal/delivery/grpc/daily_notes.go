@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"chopper/internal/delivery/grpc/chopperv1"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/problem"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"context"
+	"math"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type dailyNoteServer struct {
+	chopperv1.UnimplementedDailyNoteServiceServer
+	dailyNotesService *usecase.DailyNotesService
+	validator         *validation.Validator
+}
+
+func newDailyNoteServer(dailyNotesService *usecase.DailyNotesService, validator *validation.Validator) *dailyNoteServer {
+	return &dailyNoteServer{
+		dailyNotesService: dailyNotesService,
+		validator:         validator,
+	}
+}
+
+func (d *dailyNoteServer) CreateNote(ctx context.Context, req *chopperv1.CreateNoteRequest) (*chopperv1.CreateNoteResponse, error) {
+	dailyNoteFromFront := domain.DailyNoteFromFront{
+		Mood:       toInt16(req.GetMood()),
+		SleepHours: req.GetSleepHours(),
+		Load:       toInt16(req.GetLoad()),
+	}
+	if err := d.validator.ValidateStruct(dailyNoteFromFront); err != nil {
+		return nil, invalidRequest(ctx, err)
+	}
+	identity, err := currentIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.dailyNotesService.CreateNote(withClientInfo(ctx), identity.UserId, dailyNoteFromFront); err != nil {
+		return nil, statusError(ctx, err, "CreateNote")
+	}
+	return &chopperv1.CreateNoteResponse{Version: domain.EntryFirstVersion}, nil
+}
+
+func (d *dailyNoteServer) ChangeMood(ctx context.Context, req *chopperv1.ChangeMoodRequest) (*chopperv1.ChangeMoodResponse, error) {
+	changeMoodFromFront := domain.ChangeMoodFromFront{
+		Date: toTime(req.GetDate()),
+		Mood: toInt16(req.GetMood()),
+	}
+	if err := d.validator.ValidateStruct(changeMoodFromFront); err != nil {
+		return nil, invalidRequest(ctx, err)
+	}
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+	identity, err := currentIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changeMessage, newVersion, err := d.dailyNotesService.ChangeMood(withClientInfo(ctx), identity.UserId, changeMoodFromFront.Date, changeMoodFromFront.Mood, version)
+	if err != nil {
+		return nil, statusError(ctx, err, "ChangeMood")
+	}
+	return &chopperv1.ChangeMoodResponse{
+		Key:     string(changeMessage),
+		Answer:  i18n.T(ctx, string(changeMessage)),
+		Version: int64(newVersion),
+	}, nil
+}
+
+func (d *dailyNoteServer) ChangeSleepHours(ctx context.Context, req *chopperv1.ChangeSleepHoursRequest) (*chopperv1.ChangeSleepHoursResponse, error) {
+	changeSleepHoursFromFront := domain.ChangeSleepHoursFromFront{
+		Date:       toTime(req.GetDate()),
+		SleepHours: req.GetSleepHours(),
+	}
+	if err := d.validator.ValidateStruct(changeSleepHoursFromFront); err != nil {
+		return nil, invalidRequest(ctx, err)
+	}
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+	identity, err := currentIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changeMessage, newVersion, err := d.dailyNotesService.ChangeSleepHours(withClientInfo(ctx), identity.UserId, changeSleepHoursFromFront.Date, changeSleepHoursFromFront.SleepHours, version)
+	if err != nil {
+		return nil, statusError(ctx, err, "ChangeSleepHours")
+	}
+	return &chopperv1.ChangeSleepHoursResponse{
+		Key:     string(changeMessage),
+		Answer:  i18n.T(ctx, string(changeMessage)),
+		Version: int64(newVersion),
+	}, nil
+}
+
+func (d *dailyNoteServer) ChangeLoad(ctx context.Context, req *chopperv1.ChangeLoadRequest) (*chopperv1.ChangeLoadResponse, error) {
+	changeLoadFromFront := domain.ChangeLoadFromFront{
+		Date: toTime(req.GetDate()),
+		Load: toInt16(req.GetLoad()),
+	}
+	if err := d.validator.ValidateStruct(changeLoadFromFront); err != nil {
+		return nil, invalidRequest(ctx, err)
+	}
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+	identity, err := currentIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changeMessage, newVersion, err := d.dailyNotesService.ChangeLoad(withClientInfo(ctx), identity.UserId, changeLoadFromFront.Date, changeLoadFromFront.Load, version)
+	if err != nil {
+		return nil, statusError(ctx, err, "ChangeLoad")
+	}
+	return &chopperv1.ChangeLoadResponse{
+		Key:     string(changeMessage),
+		Answer:  i18n.T(ctx, string(changeMessage)),
+		Version: int64(newVersion),
+	}, nil
+}
+
+// expectedVersion - как If-Match в HTTP, только без "*": изменение без проверки версии по gRPC недоступно
+func expectedVersion(ctx context.Context, version int64) (int, error) {
+	if version < domain.EntryFirstVersion {
+		return 0, problemError(ctx, problem.PreconditionRequired, i18n.Msg("detail.expected_version_required"), nil)
+	}
+	return int(version), nil
+}
+
+// toInt16 - в proto нет int16; значения за пределами не должны превратиться в допустимые при переполнении
+func toInt16(v int32) int16 {
+	return int16(max(min(v, math.MaxInt16), math.MinInt16))
+}
+
+// toTime - незаданная дата остается нулевой и не проходит required
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// withClientInfo - адрес клиента и его user-agent из metadata для истории изменений
+func withClientInfo(ctx context.Context) context.Context {
+	return usecase.WithClientInfo(ctx, domain.ClientInfo{IP: peerIP(ctx), UserAgent: firstMetadata(ctx, "user-agent")})
+}
+
+// peerIP - адрес клиента без порта, "" если транспорт его не знает
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/problem"
+	"chopper/internal/repository"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain - домен в ErrorInfo, reason в нем - код problem из HTTP API
+const errorDomain = "chopper"
+
+// errorMapping - как ошибка usecase/repository выглядит для gRPC клиента; коды те же, что в problem+json
+type errorMapping struct {
+	err     error
+	problem problem.Type
+	field   string
+	detail  i18n.Message
+}
+
+var errorMappings = []errorMapping{
+	// users
+	{err: usecase.ErrUserExists, problem: problem.UserExists, detail: i18n.Msg("detail.user_exists")},
+	{err: usecase.ErrUserNotExist, problem: problem.InvalidCredentials},
+	{err: usecase.ErrWrongPassword, problem: problem.InvalidCredentials},
+	{err: usecase.ErrUserSuspended, problem: problem.UserSuspended},
+
+	// notes
+	{err: usecase.ErrWrongMoodValue, problem: problem.ValidationFailed, field: "mood", detail: validation.RangeMessage(domain.MoodMin, domain.MoodMax)},
+	{err: usecase.ErrWrongSleepHourValue, problem: problem.ValidationFailed, field: "sleep_hours", detail: validation.RangeMessage(domain.SleepHoursMin, domain.SleepHoursMax)},
+	{err: usecase.ErrWrongLoadValue, problem: problem.ValidationFailed, field: "load", detail: validation.RangeMessage(domain.LoadMin, domain.LoadMax)},
+	{err: usecase.ErrNoteAlreadyExists, problem: problem.NoteExists},
+	{err: usecase.ErrNoteNotExists, problem: problem.NoteNotFound, detail: i18n.Msg("detail.note_not_found")},
+	{err: usecase.ErrNoteVersionMismatch, problem: problem.PreconditionFailed, detail: i18n.Msg("detail.note_version_mismatch")},
+
+	// health
+	{err: usecase.ErrDatabaseUnavailable, problem: problem.ServiceUnavailable, detail: i18n.Msg("detail.database_unavailable")},
+
+	// repository
+	{err: repository.ErrNoRow, problem: problem.NotFound},
+}
+
+// statusCodes - HTTP статус problem -> код gRPC
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusUnauthorized:         codes.Unauthenticated,
+	http.StatusForbidden:            codes.PermissionDenied,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusConflict:             codes.AlreadyExists,
+	http.StatusPreconditionFailed:   codes.FailedPrecondition,
+	http.StatusPreconditionRequired: codes.FailedPrecondition,
+	http.StatusTooManyRequests:      codes.ResourceExhausted,
+	http.StatusServiceUnavailable:   codes.Unavailable,
+	http.StatusInternalServerError:  codes.Internal,
+}
+
+// statusError - аналог respondError: известная ошибка получает код и текст на языке запроса,
+// неизвестная логируется и отдается как codes.Internal без деталей
+func statusError(ctx context.Context, err error, operation string) error {
+	for _, mapping := range errorMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+		if mapping.field != "" {
+			return fieldsError(ctx, mapping.problem, validation.FieldError{Field: mapping.field, Problems: []i18n.Message{mapping.detail}})
+		}
+		return problemError(ctx, mapping.problem, mapping.detail, nil)
+	}
+	logger.FromContext(ctx).WithError(err).Error(operation + " failed")
+	return problemError(ctx, problem.InternalError, i18n.Message{}, nil)
+}
+
+// invalidRequest - запрос не прошел те же binding правила, что и JSON тело в HTTP
+func invalidRequest(ctx context.Context, err error) error {
+	var validationErr *validation.Error
+	if errors.As(err, &validationErr) {
+		return fieldsError(ctx, problem.ValidationFailed, validationErr.Fields...)
+	}
+	return problemError(ctx, problem.InvalidBody, i18n.Msg("detail.invalid_body", "error", err.Error()), nil)
+}
+
+// fieldsError - как abortFieldErrors: одно поле описывается в тексте, несколько - количеством;
+// все поля перечислены в BadRequest, чтобы клиент не разбирал текст
+func fieldsError(ctx context.Context, problemType problem.Type, fields ...validation.FieldError) error {
+	lang := i18n.Language(ctx)
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message(lang),
+		})
+	}
+	detail := i18n.Msg("detail.validation_failed", "count", len(fields))
+	if len(fields) == 1 {
+		detail = i18n.Msg("detail.field", "field", fields[0].Field, "message", fields[0].Message(lang))
+	}
+	return problemError(ctx, problemType, detail, badRequest)
+}
+
+// problemError - текст "заголовок: detail" и ErrorInfo с кодом problem
+func problemError(ctx context.Context, problemType problem.Type, detail i18n.Message, badRequest *errdetails.BadRequest) error {
+	lang := i18n.Language(ctx)
+	code, ok := statusCodes[problemType.Status]
+	if !ok {
+		code = codes.Unknown
+	}
+	message := problemType.Title(lang)
+	if !detail.IsZero() {
+		message += ": " + detail.In(lang)
+	}
+	st := status.New(code, message)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: problemType.Code, Domain: errorDomain}}
+	if badRequest != nil {
+		details = append(details, badRequest)
+	}
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"context"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDPattern - тот же формат, что у X-Request-ID в HTTP
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// logInterceptor пишет строку на каждый вызов и кладет логгер с request_id в ctx, как RequestLogger
+func logInterceptor(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		requestID := firstMetadata(ctx, "x-request-id")
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+		entry := log.WithFields(logrus.Fields{
+			"request_id": requestID,
+			"method":     info.FullMethod,
+		})
+		resp, err := handler(logger.WithContext(ctx, entry), req)
+		code := status.Code(err)
+		fields := logrus.Fields{
+			"code":       code.String(),
+			"latency_ms": time.Since(start).Milliseconds(),
+		}
+		switch code {
+		case codes.Internal, codes.Unknown, codes.DataLoss:
+			entry.WithFields(fields).Error("grpc request completed")
+		case codes.OK:
+			entry.WithFields(fields).Info("grpc request completed")
+		default:
+			entry.WithFields(fields).Warn("grpc request completed")
+		}
+		return resp, err
+	}
+}
+
+// recoveryInterceptor превращает панику обработчика в codes.Internal без деталей для клиента
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx).WithFields(logrus.Fields{
+				"panic": r,
+				"stack": string(debug.Stack()),
+			}).Error("panic recovered")
+			err = status.Error(codes.Internal, i18n.Msg("problem.internal-error").In(i18n.Language(ctx)))
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// languageInterceptor выбирает язык по metadata accept-language; сохраненную настройку позже ставит авторизация
+func languageInterceptor(defaultLanguage string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		lang := i18n.Negotiate(firstMetadata(ctx, "accept-language"), defaultLanguage)
+		return handler(i18n.WithLanguage(ctx, lang), req)
+	}
+}
+
+// firstMetadata - первое значение ключа из входящей metadata, ключи в gRPC всегда в нижнем регистре
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/middleware"
+	"chopper/internal/problem"
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// rateLimitInterceptor - тот же RateLimiter, что у HTTP: публичные методы по группе grpc_public,
+// остальные по группе grpc. Стоит после авторизации, чтобы ключом мог быть пользователь
+type rateLimitInterceptor struct {
+	public    middleware.LimitFunc
+	protected middleware.LimitFunc
+}
+
+// newRateLimitInterceptor паникует, если группе не назначена известная политика, как и роуты HTTP
+func newRateLimitInterceptor(rateLimiter *middleware.RateLimiter, groups map[string]string) *rateLimitInterceptor {
+	return &rateLimitInterceptor{
+		public:    rateLimiter.Limit(groups[domain.RateLimitGroupGRPCPublic]),
+		protected: rateLimiter.Limit(groups[domain.RateLimitGroupGRPC]),
+	}
+}
+
+func (r *rateLimitInterceptor) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	limit := r.protected
+	if methodAccesses[info.FullMethod].public {
+		limit = r.public
+	}
+	var userId uuid.UUID
+	if identity, ok := ctx.Value(identityKey{}).(domain.Identity); ok {
+		userId = identity.UserId
+	}
+	result, err := limit(ctx, peerIP(ctx), userId)
+	if err != nil {
+		// хранилище недоступно - пропускаем вызов, как и HTTP
+		logger.FromContext(ctx).WithError(err).Error("rate limiter failed")
+		return handler(ctx, req)
+	}
+	if !result.Allowed {
+		retryAfter := middleware.CeilSeconds(result.RetryAfter)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+		return nil, problemError(ctx, problem.TooManyRequests, i18n.Msg("detail.retry_in", "seconds", retryAfter), nil)
+	}
+	return handler(ctx, req)
+}
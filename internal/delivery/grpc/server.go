@@ -0,0 +1,34 @@
+// Package grpc - gRPC API для внутренних сервисов поверх тех же usecase, что и HTTP.
+// Код в chopperv1 генерируется из proto командой make proto
+package grpc
+
+import (
+	"chopper/internal/delivery/grpc/chopperv1"
+	"chopper/internal/middleware"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+//go:generate buf generate
+
+// NewServer собирает gRPC сервер; порядок интерцепторов тот же, что у middleware в HTTP:
+// recovery и лог снаружи, язык до авторизации, чтобы отказ в доступе пришел на языке клиента,
+// рейт лимитер после авторизации, чтобы считать вызовы по пользователю
+func NewServer(authenticator *usecase.Authenticator, validator *validation.Validator, userService *usecase.UserService, dailyNotesService *usecase.DailyNotesService, alertService *usecase.AlertService, rateLimiter *middleware.RateLimiter, rateLimitGroups map[string]string, defaultLanguage string, log *logrus.Logger) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			logInterceptor(log),
+			recoveryInterceptor,
+			languageInterceptor(defaultLanguage),
+			newAuthInterceptor(authenticator).intercept,
+			newRateLimitInterceptor(rateLimiter, rateLimitGroups).intercept,
+		),
+	)
+	chopperv1.RegisterUserServiceServer(server, newUserServer(userService, validator))
+	chopperv1.RegisterDailyNoteServiceServer(server, newDailyNoteServer(dailyNotesService, validator))
+	chopperv1.RegisterAlertServiceServer(server, newAlertServer(alertService))
+	return server
+}
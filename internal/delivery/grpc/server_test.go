@@ -0,0 +1,286 @@
+package grpc
+
+import (
+	"chopper/internal/delivery/grpc/chopperv1"
+	"chopper/internal/domain"
+	"chopper/internal/middleware"
+	"chopper/internal/problem"
+	"chopper/internal/repository"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testApiToken = domain.ApiTokenPrefix + "alerts"
+
+// Моки
+// Мок репозитория токенов - единственный токен testApiToken со scope alerts:read
+type MockApiTokenRepository struct {
+	usecase.ApiTokenRepository
+}
+
+func (m *MockApiTokenRepository) GetApiTokenByHash(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error) {
+	now := time.Now()
+	apiToken := domain.ApiToken{ExpiresAt: now.Add(time.Hour), LastUsedAt: &now, Scopes: []domain.Scope{domain.ScopeAlertsRead}}
+	return apiToken, domain.UserClaims{Id: uuid.New(), Username: "dexter", Language: "en"}, nil
+}
+
+// Мок генератора токенов - хэш совпадает с токеном
+type MockApiTokenGenerator struct {
+	usecase.ApiTokenGenerator
+}
+
+func (m *MockApiTokenGenerator) Hash(token string) string {
+	return token
+}
+
+// Мок репозитория алертов - записей нет
+type MockAlertRepository struct {
+}
+
+func (m *MockAlertRepository) GetLastSevenDays(ctx context.Context, userId uuid.UUID) ([]domain.Day, error) {
+	return nil, nil
+}
+
+// Мок репозитория записей - запись одна, с версией version
+type MockDailyNotesRepository struct {
+	usecase.DailyNotesRepository
+	version int
+}
+
+func (m *MockDailyNotesRepository) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16, version int) (domain.EntryChange, error) {
+	if version != m.version {
+		return domain.EntryChange{}, repository.ErrDailyEntryVersionMismatch
+	}
+	return domain.EntryChange{Field: domain.EntryFieldMood, Version: m.version + 1}, nil
+}
+
+// newTestClient поднимает сервер на bufconn; сервисы без репозиториев, кроме токенов и алертов,
+// у политик рейт лимитера по burst вызовов на клиента
+func newTestClient(t *testing.T, burst int) *grpc.ClientConn {
+	t.Helper()
+	validator, err := validation.New(domain.PasswordPolicy{MinLength: 8, MaxLength: 72})
+	if err != nil {
+		t.Fatalf("validation.New - %v", err)
+	}
+	apiTokenService := usecase.NewApiTokenService(&MockApiTokenRepository{}, &MockApiTokenGenerator{}, nil)
+	authenticator := usecase.NewAuthenticator(nil, apiTokenService, nil)
+	alertService := usecase.NewAlertServcie(&MockAlertRepository{}, domain.AlertConfig{}, nil)
+	policies := map[string]domain.RateLimitPolicy{
+		domain.RateLimitPolicyAuth: {Name: domain.RateLimitPolicyAuth, Rate: time.Minute, Burst: burst, Key: domain.RateLimitKeyIP},
+		domain.RateLimitPolicyUser: {Name: domain.RateLimitPolicyUser, Rate: time.Minute, Burst: burst, Key: domain.RateLimitKeyUser},
+	}
+	rateLimiter := middleware.NewRateLimiter(policies, middleware.NewMemoryLimiterStore(), time.Minute, nil)
+	t.Cleanup(rateLimiter.Stop)
+	groups := map[string]string{
+		domain.RateLimitGroupGRPCPublic: domain.RateLimitPolicyAuth,
+		domain.RateLimitGroupGRPC:       domain.RateLimitPolicyUser,
+	}
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	server := NewServer(authenticator, validator, usecase.NewUserService(nil, nil, nil, nil, nil, nil, nil), usecase.NewDailyNotesService(nil, nil, nil, nil, nil, nil), alertService, rateLimiter, groups, "ru", log)
+
+	ln := bufconn.Listen(1 << 20)
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient - %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// Тест Register - ошибки полей приходят в BadRequest, текст на языке из accept-language
+func TestRegisterValidation(t *testing.T) {
+	// preparing
+	client := chopperv1.NewUserServiceClient(newTestClient(t, 100))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "en")
+
+	// test
+	_, err := client.Register(ctx, &chopperv1.RegisterRequest{Username: "x", Email: "not-email", Password: "Dexter-Morgan-1"})
+
+	// assert
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("ожидался код %v, получен - %v", codes.InvalidArgument, st.Code())
+	}
+	fields := map[string]bool{}
+	reason := ""
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, violation := range d.GetFieldViolations() {
+				fields[violation.GetField()] = true
+			}
+		case *errdetails.ErrorInfo:
+			reason = d.GetReason()
+		}
+	}
+	if !fields["username"] || !fields["email"] || fields["password"] {
+		t.Errorf("ожидались ошибки username и email, получены - %v", fields)
+	}
+	if reason != problem.ValidationFailed.Code {
+		t.Errorf("ожидался reason %v, получен - %v", problem.ValidationFailed.Code, reason)
+	}
+	if title := problem.ValidationFailed.Title("en"); st.Message()[:len(title)] != title {
+		t.Errorf("ожидался текст на английском, получен - %v", st.Message())
+	}
+}
+
+// Тест авторизации - без токена, scope персонального токена и язык из настройки владельца
+func TestAuthInterceptor(t *testing.T) {
+	conn := newTestClient(t, 100)
+	withToken := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testApiToken)
+	tests := []struct {
+		name     string
+		call     func() error
+		expected codes.Code
+	}{
+		{"без токена", func() error {
+			_, err := chopperv1.NewAlertServiceClient(conn).GetLastSevenDays(context.Background(), &chopperv1.GetLastSevenDaysRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"не Bearer", func() error {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", testApiToken)
+			_, err := chopperv1.NewAlertServiceClient(conn).GetLastSevenDays(ctx, &chopperv1.GetLastSevenDaysRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"нет scope", func() error {
+			_, err := chopperv1.NewDailyNoteServiceClient(conn).CreateNote(withToken, &chopperv1.CreateNoteRequest{Mood: 5, SleepHours: 8, Load: 5})
+			return err
+		}, codes.PermissionDenied},
+		{"scope есть", func() error {
+			resp, err := chopperv1.NewAlertServiceClient(conn).GetLastSevenDays(withToken, &chopperv1.GetLastSevenDaysRequest{})
+			if err == nil && (resp.GetKey() != string(domain.MessageAlertOk) || resp.GetAlert() != "All good") {
+				t.Errorf("ожидался %v на английском, получен - %v %v", domain.MessageAlertOk, resp.GetKey(), resp.GetAlert())
+			}
+			return err
+		}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			err := tt.call()
+
+			// assert
+			if code := status.Code(err); code != tt.expected {
+				t.Errorf("ожидался код %v, получен - %v (%v)", tt.expected, code, err)
+			}
+		})
+	}
+}
+
+// Тест рейт лимитера - публичные методы считаются по IP, остальные по пользователю в отдельной корзине
+func TestRateLimitInterceptor(t *testing.T) {
+	// preparing
+	conn := newTestClient(t, 1)
+	users := chopperv1.NewUserServiceClient(conn)
+	request := &chopperv1.RegisterRequest{Username: "x", Email: "not-email", Password: "Dexter-Morgan-1"}
+	if _, err := users.Register(context.Background(), request); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("первый вызов - ожидался код %v, получен - %v", codes.InvalidArgument, status.Code(err))
+	}
+
+	// test
+	var header metadata.MD
+	_, err := users.Register(context.Background(), request, grpc.Header(&header))
+	withToken := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testApiToken)
+	_, alertErr := chopperv1.NewAlertServiceClient(conn).GetLastSevenDays(withToken, &chopperv1.GetLastSevenDaysRequest{})
+
+	// assert
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("ожидался код %v, получен - %v", codes.ResourceExhausted, st.Code())
+	}
+	reason := ""
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			reason = info.GetReason()
+		}
+	}
+	if reason != problem.TooManyRequests.Code {
+		t.Errorf("ожидался reason %v, получен - %v", problem.TooManyRequests.Code, reason)
+	}
+	if retryAfter := header.Get("retry-after"); len(retryAfter) != 1 || retryAfter[0] != "60" {
+		t.Errorf("ожидался retry-after 60, получен - %v", retryAfter)
+	}
+	if alertErr != nil {
+		t.Errorf("вызов пользователя не должен упираться в корзину IP, получено - %v", alertErr)
+	}
+}
+
+// Тест ChangeMood - expected_version обязательна, чужая версия - FAILED_PRECONDITION, в ответе новая версия
+func TestChangeMoodExpectedVersion(t *testing.T) {
+	// preparing
+	validator, err := validation.New(domain.PasswordPolicy{MinLength: 8, MaxLength: 72})
+	if err != nil {
+		t.Fatalf("validation.New - %v", err)
+	}
+	server := newDailyNoteServer(usecase.NewDailyNotesService(&MockDailyNotesRepository{version: 3}, nil, nil, nil, nil, nil), validator)
+	ctx := context.WithValue(context.Background(), identityKey{}, domain.Identity{UserId: uuid.New()})
+	tests := []struct {
+		name            string
+		expectedVersion int64
+		code            codes.Code
+		reason          string
+		version         int64
+	}{
+		{"без версии", 0, codes.FailedPrecondition, problem.PreconditionRequired.Code, 0},
+		{"чужая версия", 2, codes.FailedPrecondition, problem.PreconditionFailed.Code, 0},
+		{"версия совпала", 3, codes.OK, "", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			resp, err := server.ChangeMood(ctx, &chopperv1.ChangeMoodRequest{Date: timestamppb.Now(), Mood: 5, ExpectedVersion: tt.expectedVersion})
+
+			// assert
+			st := status.Convert(err)
+			if st.Code() != tt.code {
+				t.Fatalf("ожидался код %v, получен - %v (%v)", tt.code, st.Code(), err)
+			}
+			reason := ""
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.GetReason()
+				}
+			}
+			if reason != tt.reason {
+				t.Errorf("ожидался reason %v, получен - %v", tt.reason, reason)
+			}
+			if resp.GetVersion() != tt.version {
+				t.Errorf("ожидалась версия %v, получена - %v", tt.version, resp.GetVersion())
+			}
+		})
+	}
+}
+
+// Тест - у каждого метода каждого сервиса задан доступ, иначе он недоступен никому
+func TestMethodAccessesCoverServices(t *testing.T) {
+	for _, service := range []grpc.ServiceDesc{chopperv1.UserService_ServiceDesc, chopperv1.DailyNoteService_ServiceDesc, chopperv1.AlertService_ServiceDesc} {
+		for _, method := range service.Methods {
+			fullMethod := "/" + service.ServiceName + "/" + method.MethodName
+			if _, ok := methodAccesses[fullMethod]; !ok {
+				t.Errorf("нет записи в methodAccesses для %v", fullMethod)
+			}
+		}
+	}
+}
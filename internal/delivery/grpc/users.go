@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"chopper/internal/delivery/grpc/chopperv1"
+	"chopper/internal/domain"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"context"
+)
+
+type userServer struct {
+	chopperv1.UnimplementedUserServiceServer
+	userService *usecase.UserService
+	validator   *validation.Validator
+}
+
+func newUserServer(userService *usecase.UserService, validator *validation.Validator) *userServer {
+	return &userServer{
+		userService: userService,
+		validator:   validator,
+	}
+}
+
+func (u *userServer) Register(ctx context.Context, req *chopperv1.RegisterRequest) (*chopperv1.RegisterResponse, error) {
+	userRegisterFromFront := domain.UserRegisterFromFront{
+		Username: req.GetUsername(),
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	}
+	if err := u.validator.ValidateStruct(userRegisterFromFront); err != nil {
+		return nil, invalidRequest(ctx, err)
+	}
+	if err := u.userService.CreateUser(ctx, userRegisterFromFront); err != nil {
+		return nil, statusError(ctx, err, "Register")
+	}
+	return &chopperv1.RegisterResponse{}, nil
+}
+
+func (u *userServer) Login(ctx context.Context, req *chopperv1.LoginRequest) (*chopperv1.LoginResponse, error) {
+	userLoginFromFront := domain.UserLoginFromFront{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	}
+	if err := u.validator.ValidateStruct(userLoginFromFront); err != nil {
+		return nil, invalidRequest(ctx, err)
+	}
+	token, err := u.userService.CheckUserInDatabase(ctx, userLoginFromFront)
+	if err != nil {
+		return nil, statusError(ctx, err, "Login")
+	}
+	return &chopperv1.LoginResponse{Token: token}, nil
+}
+
+func (u *userServer) WhoAmI(ctx context.Context, req *chopperv1.WhoAmIRequest) (*chopperv1.WhoAmIResponse, error) {
+	identity, err := currentIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userService.GetIdUsernameRole(ctx, identity.UserId, identity.Username)
+	if err != nil {
+		return nil, statusError(ctx, err, "WhoAmI")
+	}
+	return &chopperv1.WhoAmIResponse{
+		Id:       user.Id.String(),
+		Username: user.Username,
+		Role:     string(user.Role),
+	}, nil
+}
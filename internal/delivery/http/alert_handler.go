@@ -1,11 +1,11 @@
 package http
 
 import (
+	"chopper/internal/i18n"
 	"chopper/internal/usecase"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type AlertHandler struct {
@@ -22,30 +22,18 @@ func (a *AlertHandler) RegisterRoutes(r gin.IRouter) {
 	r.GET("/get", a.GetLastSevenDaysAlert)
 }
 
+// GetLastSevenDaysAlert - текст анализа в поле alert на языке запроса, поле error в успешном ответе путало клиентов
 func (a *AlertHandler) GetLastSevenDaysAlert(c *gin.Context) {
-	ctx := c.Request.Context()
-	uid, ok := c.Get("user_id")
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	userId, ok := uid.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
-		return
-	}
-	allertMessage, err := a.alertService.GetLastSevenDays(ctx, userId)
+	alertMessage, err := a.alertService.GetLastSevenDays(c.Request.Context(), userId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+		respondError(c, err, "GetLastSevenDaysAlert")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"error": allertMessage,
+		"alert": i18n.T(c.Request.Context(), string(alertMessage)),
 	})
 }
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/problem"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApiTokenHandler struct {
+	apiTokenService *usecase.ApiTokenService
+}
+
+func NewApiTokenHandler(apiTokenService *usecase.ApiTokenService) *ApiTokenHandler {
+	return &ApiTokenHandler{
+		apiTokenService: apiTokenService,
+	}
+}
+
+func (a *ApiTokenHandler) RegisterRoutes(protected gin.IRouter) {
+	protected.POST("/new", a.CreateToken)
+	protected.GET("/get", a.GetTokens)
+	protected.DELETE("/:id", a.DeleteToken)
+}
+
+func (a *ApiTokenHandler) CreateToken(c *gin.Context) {
+	var apiTokenFromFront domain.ApiTokenFromFront
+	if err := c.ShouldBindJSON(&apiTokenFromFront); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	userId, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	apiToken, err := a.apiTokenService.CreateToken(c.Request.Context(), userId, apiTokenFromFront)
+	if err != nil {
+		respondError(c, err, "CreateToken")
+		return
+	}
+	// секрет показывается один раз: не кэшируется и не сохраняется для повтора по Idempotency-Key
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, apiToken)
+}
+
+func (a *ApiTokenHandler) GetTokens(c *gin.Context) {
+	userId, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	apiTokens, err := a.apiTokenService.GetTokens(c.Request.Context(), userId)
+	if err != nil {
+		respondError(c, err, "GetTokens")
+		return
+	}
+	c.JSON(http.StatusOK, apiTokens)
+}
+
+func (a *ApiTokenHandler) DeleteToken(c *gin.Context) {
+	tokenId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		abortFieldErrors(c, problem.ValidationFailed, validation.FieldError{Field: "id", Problems: []i18n.Message{i18n.Msg("validation.uuid")}})
+		return
+	}
+	userId, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if err := a.apiTokenService.DeleteToken(c.Request.Context(), userId, tokenId); err != nil {
+		respondError(c, err, "DeleteToken")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
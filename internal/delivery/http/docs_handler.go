@@ -0,0 +1,41 @@
+package http
+
+import (
+	"chopper/api"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocsHandler struct {
+	once sync.Once
+	spec []byte
+	err  error
+}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+func (d *DocsHandler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/openapi.json", d.OpenAPI)
+	r.GET("/docs", d.Docs)
+}
+
+// OpenAPI отдает спецификацию, yaml переводится в JSON один раз при первом запросе
+func (d *DocsHandler) OpenAPI(c *gin.Context) {
+	d.once.Do(func() {
+		d.spec, d.err = api.SpecJSON()
+	})
+	if d.err != nil {
+		respondError(c, d.err, "OpenAPI")
+		return
+	}
+	c.Data(http.StatusOK, "application/json", d.spec)
+}
+
+// Docs - страница Redoc поверх /openapi.json
+func (d *DocsHandler) Docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", api.DocsHTML)
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/problem"
+	"chopper/internal/repository"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// errorMapping - как ошибка usecase/repository выглядит для клиента; field задается для ошибок валидации.
+// detail - ключ i18n, текст на языке запроса подставляется при ответе
+type errorMapping struct {
+	err     error
+	problem problem.Type
+	field   string
+	detail  i18n.Message
+}
+
+// errorMappings - единственное место, где ошибки слоев ниже превращаются в HTTP ответы
+var errorMappings = []errorMapping{
+	// users
+	{err: usecase.ErrUserExists, problem: problem.UserExists, detail: i18n.Msg("detail.user_exists")},
+	{err: usecase.ErrUserNotExist, problem: problem.InvalidCredentials},
+	{err: usecase.ErrWrongPassword, problem: problem.InvalidCredentials},
+	{err: usecase.ErrUserSuspended, problem: problem.UserSuspended},
+	{err: usecase.ErrWrongLanguage, problem: problem.ValidationFailed, field: "language", detail: i18n.Msg("validation.language", "languages", strings.Join(i18n.Languages(), ", "))},
+	{err: usecase.ErrWrongTimeZone, problem: problem.ValidationFailed, field: "time_zone", detail: i18n.Msg("validation.time_zone")},
+	{err: usecase.ErrWrongReminderTime, problem: problem.ValidationFailed, field: "time", detail: i18n.Msg("validation.reminder_time")},
+
+	// notes
+	{err: usecase.ErrWrongMoodValue, problem: problem.ValidationFailed, field: "mood", detail: validation.RangeMessage(domain.MoodMin, domain.MoodMax)},
+	{err: usecase.ErrWrongSleepHourValue, problem: problem.ValidationFailed, field: "sleep_hours", detail: validation.RangeMessage(domain.SleepHoursMin, domain.SleepHoursMax)},
+	{err: usecase.ErrWrongLoadValue, problem: problem.ValidationFailed, field: "load", detail: validation.RangeMessage(domain.LoadMin, domain.LoadMax)},
+	{err: usecase.ErrNoteAlreadyExists, problem: problem.NoteExists, detail: i18n.Msg("detail.note_exists")},
+	{err: usecase.ErrNoteNotExists, problem: problem.NoteNotFound, detail: i18n.Msg("detail.note_not_found")},
+	{err: usecase.ErrNoteVersionNotExists, problem: problem.NoteVersionNotFound, detail: i18n.Msg("detail.note_version_not_found")},
+	{err: usecase.ErrNoteVersionMismatch, problem: problem.PreconditionFailed, detail: i18n.Msg("detail.note_version_mismatch")},
+
+	// api tokens
+	{err: usecase.ErrWrongApiTokenName, problem: problem.ValidationFailed, field: "name", detail: i18n.Msg("validation.api_token_name", "max", domain.ApiTokenNameMaxLength)},
+	{err: usecase.ErrWrongApiTokenScope, problem: problem.ValidationFailed, field: "scopes", detail: i18n.Msg("validation.scopes", "scopes", validation.ScopesList())},
+	{err: usecase.ErrWrongApiTokenExpiration, problem: problem.ValidationFailed, field: "expires_in_days", detail: validation.RangeMessage(1, domain.ApiTokenMaxDays)},
+	{err: usecase.ErrApiTokenExists, problem: problem.ApiTokenExists, detail: i18n.Msg("detail.api_token_exists")},
+	{err: usecase.ErrApiTokenNotExists, problem: problem.ApiTokenNotFound},
+
+	// events
+	{err: usecase.ErrTooManyStreams, problem: problem.TooManyRequests, detail: i18n.Msg("detail.too_many_streams")},
+
+	// health
+	{err: usecase.ErrShuttingDown, problem: problem.ServiceUnavailable, detail: i18n.Msg("detail.shutting_down")},
+	{err: usecase.ErrDatabaseUnavailable, problem: problem.ServiceUnavailable, detail: i18n.Msg("detail.database_unavailable")},
+	{err: usecase.ErrSchemaVersionMismatch, problem: problem.ServiceUnavailable, detail: i18n.Msg("detail.schema_mismatch")},
+
+	// repository
+	{err: repository.ErrNoRow, problem: problem.NotFound},
+}
+
+// respondError отвечает problem+json по errorMappings; неизвестная ошибка логируется и отдается как 500 без деталей
+func respondError(c *gin.Context, err error, operation string) {
+	for _, mapping := range errorMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+		if mapping.field != "" {
+			abortFieldErrors(c, mapping.problem, validation.FieldError{Field: mapping.field, Problems: []i18n.Message{mapping.detail}})
+			return
+		}
+		problem.Abort(c, mapping.problem, mapping.detail)
+		return
+	}
+	logger.FromContext(c.Request.Context()).WithError(err).Error(operation + " failed")
+	problem.Abort(c, problem.InternalError, i18n.Message{})
+}
+
+// respondInvalidBody - тело не разобралось как JSON нужной формы или не прошло binding теги;
+// во втором случае клиент получает ошибки всех полей сразу
+func respondInvalidBody(c *gin.Context, err error) {
+	var validationErr *validation.Error
+	if errors.As(err, &validationErr) {
+		abortFieldErrors(c, problem.ValidationFailed, validationErr.Fields...)
+		return
+	}
+	problem.Abort(c, problem.InvalidBody, i18n.Msg("detail.invalid_body", "error", err.Error()))
+}
+
+// abortFieldErrors - одно поле описывается прямо в detail, несколько - количеством
+func abortFieldErrors(c *gin.Context, problemType problem.Type, fields ...validation.FieldError) {
+	lang := i18n.Language(c.Request.Context())
+	fieldErrors := make([]problem.FieldError, 0, len(fields))
+	for _, field := range fields {
+		fieldErrors = append(fieldErrors, problem.FieldError{Field: field.Field, Message: field.Message(lang)})
+	}
+	detail := i18n.Msg("detail.validation_failed", "count", len(fieldErrors))
+	if len(fieldErrors) == 1 {
+		detail = i18n.Msg("detail.field", "field", fieldErrors[0].Field, "message", fieldErrors[0].Message)
+	}
+	problem.Abort(c, problemType, detail, fieldErrors...)
+}
+
+// currentUser достает пользователя, которого положил AuthMiddleware; без него отвечает 401
+func currentUser(c *gin.Context) (uuid.UUID, string, bool) {
+	id, ok := c.Get("user_id")
+	if !ok {
+		problem.Abort(c, problem.Unauthorized, i18n.Msg("detail.no_user"))
+		return uuid.UUID{}, "", false
+	}
+	userId, ok := id.(uuid.UUID)
+	if !ok {
+		problem.Abort(c, problem.Unauthorized, i18n.Msg("detail.no_user"))
+		return uuid.UUID{}, "", false
+	}
+	username, _ := c.Get("username")
+	userUsername, _ := username.(string)
+	return userId, userUsername, true
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"chopper/internal/i18n"
+	"chopper/internal/problem"
+	"chopper/internal/repository"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func respond(err error) (*httptest.ResponseRecorder, problem.Problem) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	respondError(c, err, "Test")
+	var p problem.Problem
+	_ = json.Unmarshal(w.Body.Bytes(), &p)
+	return w, p
+}
+
+// Тест respondError - ошибки usecase и repository, в том числе обернутые, получают свой type и статус
+func TestRespondErrorMapping(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		typ    string
+		field  string
+	}{
+		{usecase.ErrUserExists, http.StatusConflict, "/problems/user-already-exists", ""},
+		{usecase.ErrWrongPassword, http.StatusUnauthorized, "/problems/invalid-credentials", ""},
+		{usecase.ErrUserSuspended, http.StatusForbidden, "/problems/user-suspended", ""},
+		{usecase.ErrNoteAlreadyExists, http.StatusConflict, "/problems/note-already-exists", ""},
+		{usecase.ErrNoteNotExists, http.StatusNotFound, "/problems/note-not-found", ""},
+		{usecase.ErrNoteVersionNotExists, http.StatusNotFound, "/problems/note-version-not-found", ""},
+		{usecase.ErrNoteVersionMismatch, http.StatusPreconditionFailed, "/problems/precondition-failed", ""},
+		{usecase.ErrWrongSleepHourValue, http.StatusBadRequest, "/problems/validation-failed", "sleep_hours"},
+		{fmt.Errorf("create token: %w", usecase.ErrWrongApiTokenScope), http.StatusBadRequest, "/problems/validation-failed", "scopes"},
+		{fmt.Errorf("%w: dial tcp", usecase.ErrDatabaseUnavailable), http.StatusServiceUnavailable, "/problems/service-unavailable", ""},
+		{repository.ErrNoRow, http.StatusNotFound, "/problems/not-found", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			// test
+			w, p := respond(tt.err)
+
+			// assert
+			if w.Code != tt.status || p.Status != tt.status {
+				t.Errorf("expected status %v, got - %v (body %v)", tt.status, w.Code, p.Status)
+			}
+			if p.Type != tt.typ {
+				t.Errorf("expected type %v, got - %v", tt.typ, p.Type)
+			}
+			if tt.field != "" && (len(p.Errors) != 1 || p.Errors[0].Field != tt.field) {
+				t.Errorf("expected field error for %v, got - %+v", tt.field, p.Errors)
+			}
+		})
+	}
+}
+
+// Тест respondError - неизвестная ошибка не раскрывается клиенту
+func TestRespondErrorUnknown(t *testing.T) {
+	// test
+	w, p := respond(errors.New("pq: password authentication failed for user postgres"))
+
+	// assert
+	if w.Code != http.StatusInternalServerError || p.Type != "/problems/internal-error" {
+		t.Errorf("expected internal-error 500, got - %v %v", w.Code, p.Type)
+	}
+	if p.Detail != "" {
+		t.Errorf("detail was not expected, got - %v", p.Detail)
+	}
+}
+
+// Тест respondInvalidBody - ошибки binding тегов отдаются все сразу, ошибка разбора JSON - как invalid-body
+func TestRespondInvalidBody(t *testing.T) {
+	// preparing
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err    error
+		typ    string
+		fields int
+	}{
+		{&validation.Error{Fields: []validation.FieldError{{Field: "username", Problems: []i18n.Message{i18n.Msg("validation.required")}}, {Field: "email", Problems: []i18n.Message{i18n.Msg("validation.required")}}}}, "/problems/validation-failed", 2},
+		{errors.New("unexpected EOF"), "/problems/invalid-request-body", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+			// test
+			respondInvalidBody(c, tt.err)
+
+			// assert
+			var p problem.Problem
+			_ = json.Unmarshal(w.Body.Bytes(), &p)
+			if w.Code != http.StatusBadRequest || p.Type != tt.typ {
+				t.Errorf("expected %v 400, got - %v %v", tt.typ, p.Type, w.Code)
+			}
+			if len(p.Errors) != tt.fields {
+				t.Errorf("expected %v field errors, got - %+v", tt.fields, p.Errors)
+			}
+		})
+	}
+}
+
+// Тест respondError - detail и ошибки полей на языке запроса
+func TestRespondErrorLocalized(t *testing.T) {
+	// preparing
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/notes/change/mood", nil)
+	c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), "ru"))
+
+	// test
+	respondError(c, usecase.ErrWrongMoodValue, "Test")
+
+	// assert
+	var p problem.Problem
+	_ = json.Unmarshal(w.Body.Bytes(), &p)
+	if p.Title != "Ошибка валидации" || p.Detail != "mood: должно быть от 0 до 10" {
+		t.Errorf("expected russian title and detail, got - %+v", p)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Message != "должно быть от 0 до 10" {
+		t.Errorf("expected russian field error, got - %+v", p.Errors)
+	}
+}
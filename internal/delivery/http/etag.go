@@ -0,0 +1,45 @@
+package http
+
+import (
+	"chopper/internal/i18n"
+	"chopper/internal/problem"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// entryETag - сильный ETag записи по ее версии
+func entryETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// requireIfMatch достает ожидаемую версию из If-Match: один сильный ETag или "*" (любая версия, 0).
+// Без заголовка - 428; ETag, который не может быть версией записи, ни с чем не совпадает - 412
+func requireIfMatch(c *gin.Context) (int, bool) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		problem.Abort(c, problem.PreconditionRequired, i18n.Msg("detail.if_match_required"))
+		return 0, false
+	}
+	if ifMatch == "*" {
+		return 0, true
+	}
+	version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(ifMatch, `"`), `"`))
+	if err != nil || version < 1 || entryETag(version) != ifMatch {
+		problem.Abort(c, problem.PreconditionFailed, i18n.Msg("detail.note_version_mismatch"))
+		return 0, false
+	}
+	return version, true
+}
+
+// ifNoneMatch - совпадает ли etag с одним из If-None-Match; сравнение слабое, W/ не учитывается
+func ifNoneMatch(c *gin.Context, etag string) bool {
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
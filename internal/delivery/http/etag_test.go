@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func etagContext(header, value string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	if value != "" {
+		c.Request.Header.Set(header, value)
+	}
+	return c, w
+}
+
+// Тест requireIfMatch - версия из сильного ETag, "*" - без проверки, без заголовка 428, чужой формат 412
+func TestRequireIfMatch(t *testing.T) {
+	tests := []struct {
+		ifMatch string
+		version int
+		ok      bool
+		status  int
+	}{
+		{`"3"`, 3, true, http.StatusOK},
+		{"*", 0, true, http.StatusOK},
+		{"", 0, false, http.StatusPreconditionRequired},
+		{`W/"3"`, 0, false, http.StatusPreconditionFailed},
+		{`"3", "4"`, 0, false, http.StatusPreconditionFailed},
+		{`"0"`, 0, false, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ifMatch, func(t *testing.T) {
+			// preparing
+			c, w := etagContext("If-Match", tt.ifMatch)
+
+			// test
+			version, ok := requireIfMatch(c)
+
+			// assert
+			if version != tt.version || ok != tt.ok {
+				t.Errorf("expected %v %v, got - %v %v", tt.version, tt.ok, version, ok)
+			}
+			if w.Code != tt.status {
+				t.Errorf("expected status %v, got - %v", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+// Тест ifNoneMatch - слабое сравнение по списку ETag
+func TestIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		{`"3"`, true},
+		{`W/"3"`, true},
+		{`"1", "3"`, true},
+		{"*", true},
+		{`"2"`, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		// preparing
+		c, _ := etagContext("If-None-Match", tt.ifNoneMatch)
+
+		// test
+		result := ifNoneMatch(c, entryETag(3))
+
+		// assert
+		if result != tt.expected {
+			t.Errorf("%q: expected %v, got - %v", tt.ifNoneMatch, tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/problem"
+	"chopper/internal/usecase"
+	"chopper/internal/validation"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventRetry - через сколько браузерный EventSource переподключается после обрыва
+const eventRetry = 3 * time.Second
+
+// eventScopes - scope, без которого персональный токен не видит событие
+var eventScopes = map[domain.UserEventType]domain.Scope{
+	domain.UserEventEntryCreated: domain.ScopeNotesRead,
+	domain.UserEventEntryChanged: domain.ScopeNotesRead,
+	domain.UserEventAlert:        domain.ScopeAlertsRead,
+}
+
+type EventHandler struct {
+	userEventService *usecase.UserEventService
+}
+
+func NewEventHandler(userEventService *usecase.UserEventService) *EventHandler {
+	return &EventHandler{
+		userEventService: userEventService,
+	}
+}
+
+func (e *EventHandler) RegisterRoutes(protected gin.IRouter) {
+	protected.GET("", e.Stream)
+}
+
+// eventData - payload события; у алерта еще текст на языке запроса
+type eventData struct {
+	domain.UserEventPayload
+	Message string `json:"message,omitempty"`
+}
+
+// Stream - text/event-stream с изменениями записей и алертами; после обрыва клиент
+// присылает Last-Event-ID и получает пропущенные события из базы
+func (e *EventHandler) Stream(c *gin.Context) {
+	userId, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	allowed := allowedEvents(c)
+	if len(allowed) == 0 {
+		problem.Abort(c, problem.InsufficientScope, i18n.Msg("detail.scope_required", "scope", domain.ScopeNotesRead))
+		return
+	}
+	var lastEventId int64
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			abortFieldErrors(c, problem.ValidationFailed, validation.FieldError{Field: "Last-Event-ID", Problems: []i18n.Message{i18n.Msg("validation.invalid")}})
+			return
+		}
+		lastEventId = id
+	}
+	subscription, err := e.userEventService.Subscribe(c.Request.Context(), userId, lastEventId)
+	if err != nil {
+		respondError(c, err, "Stream")
+		return
+	}
+	defer e.userEventService.Unsubscribe(subscription)
+
+	// поток живет дольше server.write_timeout, дедлайн снимается только для него
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventRetry.Milliseconds())
+	c.Writer.Flush()
+
+	lastSent := lastEventId
+	send := func(event domain.UserEvent) {
+		// событие могло прийти и из базы, и из LISTEN
+		if event.Id <= lastSent {
+			return
+		}
+		lastSent = event.Id
+		if !allowed[event.Type] {
+			return
+		}
+		writeEvent(c, event)
+	}
+	for _, event := range subscription.Replay {
+		send(event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(e.userEventService.HeartbeatInterval())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// сервис закрыл поток, клиент переподключится с Last-Event-ID
+				return
+			}
+			send(event)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			io.WriteString(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+func writeEvent(c *gin.Context, event domain.UserEvent) {
+	data := eventData{UserEventPayload: event.Payload}
+	if event.Type == domain.UserEventAlert {
+		data.Message = i18n.T(c.Request.Context(), string(event.Payload.Key))
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, body)
+}
+
+// allowedEvents - JWT сессии видят все события, персональный токен - только по своим scope
+func allowedEvents(c *gin.Context) map[domain.UserEventType]bool {
+	allowed := map[domain.UserEventType]bool{}
+	s, ok := c.Get("scopes")
+	scopes, _ := s.([]domain.Scope)
+	for eventType, scope := range eventScopes {
+		if !ok || slices.Contains(scopes, scope) {
+			allowed[eventType] = true
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/usecase"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Мок репозитория событий - отдает заранее заданные события
+type MockUserEventRepository struct {
+	usecase.UserEventRepository
+	events []domain.UserEvent
+}
+
+func (m *MockUserEventRepository) GetUserEventsAfter(ctx context.Context, userId uuid.UUID, afterId int64, limit int) ([]domain.UserEvent, error) {
+	events := []domain.UserEvent{}
+	for _, event := range m.events {
+		if event.Id > afterId {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// stream вызывает Stream с уже отмененным контекстом: хендлер отдает догон и сразу завершается
+func stream(lastEventId string, scopes []domain.Scope, events []domain.UserEvent) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	service := usecase.NewUserEventService(&MockUserEventRepository{events: events}, nil, nil, nil, nil, domain.EventsConfig{HeartbeatInterval: time.Minute, MaxStreams: 1}, logrus.New())
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/events", nil).WithContext(ctx)
+	if lastEventId != "" {
+		c.Request.Header.Set("Last-Event-ID", lastEventId)
+	}
+	c.Set("user_id", uuid.New())
+	if scopes != nil {
+		c.Set("scopes", scopes)
+	}
+	NewEventHandler(service).Stream(c)
+	return w
+}
+
+// Тест Stream - догон после Last-Event-ID, события без нужного scope не отдаются
+func TestStreamReplay(t *testing.T) {
+	// preparing
+	events := []domain.UserEvent{
+		{Id: 1, Type: domain.UserEventEntryCreated, Payload: domain.UserEventPayload{Date: "2025-01-01"}},
+		{Id: 2, Type: domain.UserEventEntryChanged, Payload: domain.UserEventPayload{Date: "2025-01-02"}},
+		{Id: 3, Type: domain.UserEventAlert, Payload: domain.UserEventPayload{Key: domain.MessageAlertOk}},
+	}
+
+	// test
+	w := stream("1", []domain.Scope{domain.ScopeAlertsRead}, events)
+
+	// assert
+	body := w.Body.String()
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("ожидался поток 200 text/event-stream, получено - %v %v", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(body, "retry: 3000\n\n") {
+		t.Errorf("ожидался retry в начале потока, получено - %q", body)
+	}
+	if !strings.Contains(body, "id: 3\nevent: alert\ndata: {\"key\":\"alert.ok\",\"message\":\"All good\"}\n\n") {
+		t.Errorf("ожидался алерт 3, получено - %q", body)
+	}
+	if strings.Contains(body, "id: 1\n") || strings.Contains(body, "id: 2\n") {
+		t.Errorf("события записей без notes:read и до Last-Event-ID не ожидались, получено - %q", body)
+	}
+}
+
+// Тест Stream - невалидный Last-Event-ID и токен без подходящих scope
+func TestStreamRejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventId string
+		scopes      []domain.Scope
+		status      int
+	}{
+		{"невалидный Last-Event-ID", "abc", nil, http.StatusBadRequest},
+		{"нет scope", "", []domain.Scope{domain.ScopeUsersRead}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			w := stream(tt.lastEventId, tt.scopes, nil)
+
+			// assert
+			if w.Code != tt.status {
+				t.Errorf("ожидался статус %v, получен - %v", tt.status, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"chopper/internal/logger"
+	"chopper/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService *usecase.HealthService
+}
+
+func NewHealthHandler(healthService *usecase.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+func (h *HealthHandler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/healthz", h.Healthz)
+	r.GET("/readyz", h.Readyz)
+	r.GET("/version", h.Version)
+}
+
+// Healthz - процесс жив и отвечает, зависимости не проверяются
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx := c.Request.Context()
+	if err := h.healthService.Ready(ctx); err != nil {
+		// наружу только причина, детали ошибки базы остаются в логе
+		logger.FromContext(ctx).WithError(err).Warn("readiness check failed")
+		respondError(c, err, "Readyz")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// Version - версия схемы базы и версия встроенных миграций бинарника
+func (h *HealthHandler) Version(c *gin.Context) {
+	ctx := c.Request.Context()
+	version, err := h.healthService.SchemaVersion(ctx)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("read schema version failed")
+		respondError(c, err, "Version")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"schema": version,
+	})
+}
@@ -2,12 +2,12 @@ package http
 
 import (
 	"chopper/internal/domain"
+	"chopper/internal/i18n"
 	"chopper/internal/usecase"
-	"errors"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type NoteHandler struct {
@@ -20,205 +20,186 @@ func NewNoteHandler(dailyNotesService *usecase.DailyNotesService) *NoteHandler {
 	}
 }
 
-func (n *NoteHandler) RegisterRoutes(protected gin.IRouter) {
+func (n *NoteHandler) RegisterRoutes(read gin.IRouter, protected gin.IRouter) {
+	read.GET("/get", n.GetNote)
+	read.GET("/history", n.GetNoteHistory)
 	protected.POST("/new", n.CreateNote)
 	protected.POST("/change/mood", n.ChangeMood)
 	protected.POST("/change/sleep_hours", n.ChangeSleepHours)
 	protected.POST("/change/load", n.ChangeLoad)
+	protected.POST("/revert", n.RevertNote)
 }
 
 func (n *NoteHandler) CreateNote(c *gin.Context) {
 	var dailyNoteFromFront domain.DailyNoteFromFront
 	if err := c.ShouldBindJSON(&dailyNoteFromFront); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request body",
-		})
+		respondInvalidBody(c, err)
 		return
 	}
-	ctx := c.Request.Context()
-	uId, ok := c.Get("user_id")
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad token",
-		})
 		return
 	}
-	userId, ok := uId.(uuid.UUID)
+	if err := n.dailyNotesService.CreateNote(clientContext(c), userId, dailyNoteFromFront); err != nil {
+		respondError(c, err, "CreateNote")
+		return
+	}
+	c.Header("ETag", entryETag(domain.EntryFirstVersion))
+	c.Status(http.StatusCreated)
+}
+
+// GetNote отдает запись с ETag; If-None-Match с тем же ETag - 304 без тела
+func (n *NoteHandler) GetNote(c *gin.Context) {
+	var noteDateFromFront domain.NoteDateFromFront
+	if err := c.ShouldBindQuery(&noteDateFromFront); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad token",
-		})
 		return
 	}
-	err := n.dailyNotesService.CreateNote(ctx, userId, dailyNoteFromFront)
+	entry, err := n.dailyNotesService.GetNote(c.Request.Context(), userId, noteDateFromFront.Date)
 	if err != nil {
-		if errors.Is(err, usecase.ErrNoteAlreadyExists) {
-			c.JSON(http.StatusNotFound, gin.H{})
-			return
-		}
-		if errors.Is(err, usecase.ErrWrongMoodValue) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "wrong mood value",
-			})
-			return
-		}
-		if errors.Is(err, usecase.ErrWrongSleepHourValue) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "wrong sleep hours value",
-			})
-			return
-		}
-		if errors.Is(err, usecase.ErrWrongLoadValue) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "wrong load value",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+		respondError(c, err, "GetNote")
 		return
 	}
-	c.Status(http.StatusCreated)
+	etag := entryETag(entry.Version)
+	c.Header("ETag", etag)
+	// кэш клиента каждый раз сверяется с сервером, после изменения записи старый ответ не используется
+	c.Header("Cache-Control", "private, no-cache")
+	if ifNoneMatch(c, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetNoteHistory отдает изменения записи по возрастанию версии
+func (n *NoteHandler) GetNoteHistory(c *gin.Context) {
+	var noteDateFromFront domain.NoteDateFromFront
+	if err := c.ShouldBindQuery(&noteDateFromFront); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	userId, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	history, err := n.dailyNotesService.GetNoteHistory(c.Request.Context(), userId, noteDateFromFront.Date)
+	if err != nil {
+		respondError(c, err, "GetNoteHistory")
+		return
+	}
+	c.Header("Cache-Control", "private, no-cache")
+	c.JSON(http.StatusOK, history)
 }
 
 func (n *NoteHandler) ChangeMood(c *gin.Context) {
 	var changeMoodFromFront domain.ChangeMoodFromFront
 	if err := c.ShouldBindJSON(&changeMoodFromFront); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "bad request body",
-		})
+		respondInvalidBody(c, err)
 		return
 	}
-	ctx := c.Request.Context()
-	uid, ok := c.Get("user_id")
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	userId, ok := uid.(uuid.UUID)
+	version, ok := requireIfMatch(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	changeMessage, err := n.dailyNotesService.ChangeMood(ctx, userId, changeMoodFromFront.Date, changeMoodFromFront.Mood)
+	changeMessage, newVersion, err := n.dailyNotesService.ChangeMood(clientContext(c), userId, changeMoodFromFront.Date, changeMoodFromFront.Mood, version)
 	if err != nil {
-		if errors.Is(err, usecase.ErrNoteNotExists) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "note not exists",
-			})
-			return
-		}
-		if errors.Is(err, usecase.ErrWrongMoodValue) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "wrong mood value",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+		respondError(c, err, "ChangeMood")
 		return
 	}
+	c.Header("ETag", entryETag(newVersion))
 	c.JSON(http.StatusOK, gin.H{
-		"answer": changeMessage,
+		"answer": i18n.T(c.Request.Context(), string(changeMessage)),
 	})
 }
 
 func (n *NoteHandler) ChangeSleepHours(c *gin.Context) {
 	var changeSleepHoursFromFront domain.ChangeSleepHoursFromFront
 	if err := c.ShouldBindJSON(&changeSleepHoursFromFront); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "bad request body",
-		})
+		respondInvalidBody(c, err)
 		return
 	}
-	ctx := c.Request.Context()
-	uid, ok := c.Get("user_id")
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	userId, ok := uid.(uuid.UUID)
+	version, ok := requireIfMatch(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	changeMessage, err := n.dailyNotesService.ChangeSleepHours(ctx, userId, changeSleepHoursFromFront.Date, changeSleepHoursFromFront.SleepHours)
+	changeMessage, newVersion, err := n.dailyNotesService.ChangeSleepHours(clientContext(c), userId, changeSleepHoursFromFront.Date, changeSleepHoursFromFront.SleepHours, version)
 	if err != nil {
-		if errors.Is(err, usecase.ErrNoteNotExists) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "note not exists",
-			})
-			return
-		}
-		if errors.Is(err, usecase.ErrWrongSleepHourValue) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "wrong sleep hours value",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+		respondError(c, err, "ChangeSleepHours")
 		return
 	}
+	c.Header("ETag", entryETag(newVersion))
 	c.JSON(http.StatusOK, gin.H{
-		"answer": changeMessage,
+		"answer": i18n.T(c.Request.Context(), string(changeMessage)),
 	})
 }
 
 func (n *NoteHandler) ChangeLoad(c *gin.Context) {
 	var changeLoadFromFront domain.ChangeLoadFromFront
 	if err := c.ShouldBindJSON(&changeLoadFromFront); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "bad request body",
-		})
+		respondInvalidBody(c, err)
 		return
 	}
-	ctx := c.Request.Context()
-	uid, ok := c.Get("user_id")
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	userId, ok := uid.(uuid.UUID)
+	version, ok := requireIfMatch(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad credentials",
-		})
 		return
 	}
-	changeMessage, err := n.dailyNotesService.ChangeLoad(ctx, userId, changeLoadFromFront.Date, changeLoadFromFront.Load)
+	changeMessage, newVersion, err := n.dailyNotesService.ChangeLoad(clientContext(c), userId, changeLoadFromFront.Date, changeLoadFromFront.Load, version)
 	if err != nil {
-		if errors.Is(err, usecase.ErrNoteNotExists) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "note not exists",
-			})
-			return
-		}
-		if errors.Is(err, usecase.ErrWrongLoadValue) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "wrong load value",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+		respondError(c, err, "ChangeLoad")
 		return
 	}
+	c.Header("ETag", entryETag(newVersion))
 	c.JSON(http.StatusOK, gin.H{
-		"answer": changeMessage,
+		"answer": i18n.T(c.Request.Context(), string(changeMessage)),
+	})
+}
+
+// RevertNote возвращает значения версии из тела запроса; If-Match - текущая версия, как и при изменении
+func (n *NoteHandler) RevertNote(c *gin.Context) {
+	var noteRevertFromFront domain.NoteRevertFromFront
+	if err := c.ShouldBindJSON(&noteRevertFromFront); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	userId, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	version, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	revertMessage, newVersion, err := n.dailyNotesService.RevertNote(clientContext(c), userId, noteRevertFromFront.Date, noteRevertFromFront.Version, version)
+	if err != nil {
+		respondError(c, err, "RevertNote")
+		return
+	}
+	c.Header("ETag", entryETag(newVersion))
+	c.JSON(http.StatusOK, gin.H{
+		"answer": i18n.T(c.Request.Context(), string(revertMessage)),
+	})
+}
+
+// clientContext - ctx запроса с адресом и User-Agent клиента для истории изменений
+func clientContext(c *gin.Context) context.Context {
+	return usecase.WithClientInfo(c.Request.Context(), domain.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
 	})
 }
@@ -3,12 +3,10 @@ package http
 import (
 	"chopper/internal/domain"
 	"chopper/internal/usecase"
-	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type UserHandler struct {
@@ -21,57 +19,41 @@ func NewUserHandler(userService *usecase.UserService) *UserHandler {
 	}
 }
 
-func (u *UserHandler) RegisterRoutes(public gin.IRouter, protected gin.IRouter) {
+// session - роуты, которые меняют аккаунт и недоступны по персональным токенам
+func (u *UserHandler) RegisterRoutes(public gin.IRouter, protected gin.IRouter, session gin.IRouter) {
 	public.POST("/register", u.UserRegister)
 	public.POST("/login", u.UserLogin)
 	protected.GET("/me", u.WhoAmI)
+	session.PUT("/me/language", u.SetLanguage)
+	session.PUT("/me/reminder", u.SetReminder)
 }
 
 func (u *UserHandler) UserRegister(c *gin.Context) {
-	ctx := c.Request.Context()
 	var userRegisterFromFront domain.UserRegisterFromFront
 	if err := c.ShouldBindJSON(&userRegisterFromFront); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request body",
-		})
+		respondInvalidBody(c, err)
 		return
 	}
-	if err := u.userService.CreateUser(ctx, userRegisterFromFront); err != nil && errors.Is(err, usecase.ErrUserExists) {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": "user already exists",
-		})
-		return
-	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+	if err := u.userService.CreateUser(c.Request.Context(), userRegisterFromFront); err != nil {
+		respondError(c, err, "UserRegister")
 		return
 	}
 	c.Status(http.StatusCreated)
 }
 
 func (u *UserHandler) UserLogin(c *gin.Context) {
-	ctx := c.Request.Context()
 	var userLoginFromFront domain.UserLoginFromFront
 	if err := c.ShouldBindJSON(&userLoginFromFront); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request body",
-		})
+		respondInvalidBody(c, err)
 		return
 	}
-	token, err := u.userService.CheckUserInDatabase(ctx, userLoginFromFront)
+	token, err := u.userService.CheckUserInDatabase(c.Request.Context(), userLoginFromFront)
 	if err != nil {
-		if errors.Is(err, usecase.ErrUserNotExist) || errors.Is(err, usecase.ErrWrongPassword) {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid credentials",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+		respondError(c, err, "UserLogin")
 		return
 	}
+	// токен не кэшируется и не сохраняется для повтора по Idempotency-Key
+	c.Header("Cache-Control", "no-store")
 	c.Header("Authorization", fmt.Sprintf("Bearer %v", token))
 	c.JSON(http.StatusOK, gin.H{
 		"token": token,
@@ -79,46 +61,50 @@ func (u *UserHandler) UserLogin(c *gin.Context) {
 }
 
 func (u *UserHandler) WhoAmI(c *gin.Context) {
-	id, ok := c.Get("user_id")
+	userId, username, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad token",
-		})
 		return
 	}
-	username, ok := c.Get("username")
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad token",
-		})
+	user, err := u.userService.GetIdUsernameRole(c.Request.Context(), userId, username)
+	if err != nil {
+		respondError(c, err, "WhoAmI")
 		return
 	}
-	userId, ok := id.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad token",
-		})
+	c.JSON(http.StatusOK, user)
+}
+
+// SetLanguage сохраняет язык ответов; пустой language возвращает выбор по Accept-Language
+func (u *UserHandler) SetLanguage(c *gin.Context) {
+	var userLanguageFromFront domain.UserLanguageFromFront
+	if err := c.ShouldBindJSON(&userLanguageFromFront); err != nil {
+		respondInvalidBody(c, err)
 		return
 	}
-	userUsername, ok := username.(string)
+	userId, _, ok := currentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "bad token",
-		})
 		return
 	}
-	ctx := c.Request.Context()
-	user, err := u.userService.GetIdUsernameRole(ctx, userId, userUsername)
-	if err != nil && errors.Is(err, usecase.ErrUserNotExist) {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "invalid credentials",
-		})
+	if err := u.userService.SetLanguage(c.Request.Context(), userId, userLanguageFromFront.Language); err != nil {
+		respondError(c, err, "SetLanguage")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetReminder включает ежедневное напоминание о незаполненном дне; пустой time выключает его
+func (u *UserHandler) SetReminder(c *gin.Context) {
+	var userReminderFromFront domain.UserReminderFromFront
+	if err := c.ShouldBindJSON(&userReminderFromFront); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	userId, _, ok := currentUser(c)
+	if !ok {
 		return
-	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "internal server error",
-		})
+	}
+	if err := u.userService.SetReminder(c.Request.Context(), userId, userReminderFromFront.TimeZone, userReminderFromFront.Time); err != nil {
+		respondError(c, err, "SetReminder")
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.Status(http.StatusNoContent)
 }
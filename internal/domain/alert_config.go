@@ -0,0 +1,8 @@
+package domain
+
+// AlertConfig - пороги правил алертов; день считается плохим, если значение на пороге или хуже
+type AlertConfig struct {
+	LowMood       int16
+	LowSleepHours float64
+	HighLoad      int16
+}
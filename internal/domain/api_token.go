@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiTokenPrefix отличает персональные токены от JWT в заголовке Authorization
+const ApiTokenPrefix = "chp_"
+
+// ограничения персонального токена
+const (
+	ApiTokenNameMaxLength = 64
+	ApiTokenMaxDays       = 365
+)
+
+type ApiToken struct {
+	Id         uuid.UUID  `json:"id"`
+	UserId     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []Scope    `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
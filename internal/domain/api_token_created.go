@@ -0,0 +1,7 @@
+package domain
+
+// ApiTokenCreated отдается один раз при создании, секрет в базе не хранится
+type ApiTokenCreated struct {
+	Token string `json:"token"`
+	ApiToken
+}
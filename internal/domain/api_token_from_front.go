@@ -0,0 +1,7 @@
+package domain
+
+type ApiTokenFromFront struct {
+	Name          string  `json:"name" binding:"api_token_name"`
+	Scopes        []Scope `json:"scopes" binding:"required,min=1,dive,scope"`
+	ExpiresInDays int     `json:"expires_in_days" binding:"api_token_days"`
+}
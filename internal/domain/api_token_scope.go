@@ -0,0 +1,18 @@
+package domain
+
+type Scope string
+
+const (
+	ScopeNotesRead  Scope = "notes:read"
+	ScopeNotesWrite Scope = "notes:write"
+	ScopeAlertsRead Scope = "alerts:read"
+	ScopeUsersRead  Scope = "users:read"
+)
+
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeNotesRead, ScopeNotesWrite, ScopeAlertsRead, ScopeUsersRead:
+		return true
+	}
+	return false
+}
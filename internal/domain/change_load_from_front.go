@@ -3,6 +3,6 @@ package domain
 import "time"
 
 type ChangeLoadFromFront struct {
-	Date time.Time `json:"date"`
-	Load int16     `json:"load"`
+	Date time.Time `json:"date" binding:"required"`
+	Load int16     `json:"load" binding:"load"`
 }
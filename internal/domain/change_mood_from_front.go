@@ -3,6 +3,6 @@ package domain
 import "time"
 
 type ChangeMoodFromFront struct {
-	Date time.Time `json:"date"`
-	Mood int16     `json:"mood"`
+	Date time.Time `json:"date" binding:"required"`
+	Mood int16     `json:"mood" binding:"mood"`
 }
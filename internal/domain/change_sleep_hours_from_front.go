@@ -3,6 +3,6 @@ package domain
 import "time"
 
 type ChangeSleepHoursFromFront struct {
-	Date       time.Time `json:"date"`
-	SleepHours float64   `json:"sleep_hours"`
+	Date       time.Time `json:"date" binding:"required"`
+	SleepHours float64   `json:"sleep_hours" binding:"sleep_hours"`
 }
@@ -0,0 +1,7 @@
+package domain
+
+// ClientInfo - откуда пришел запрос, сохраняется в истории записи
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
@@ -0,0 +1,19 @@
+package domain
+
+// Config - вся конфигурация сервиса, собирается и валидируется пакетом config
+type Config struct {
+	Server      ServerConfig
+	Database    DataBaseConfig
+	JWT         JWtConfig
+	RateLimiter RateLimiterConfig
+	Logger      LoggerConfig
+	Tracing     TracingConfig
+	Security    SecurityConfig
+	Alert       AlertConfig
+	CORS        CORSConfig
+	I18n        I18nConfig
+	Events      EventsConfig
+	Reminders   RemindersConfig
+	Outbox      OutboxConfig
+	Idempotency IdempotencyConfig
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// CORSConfig - пустой AllowedOrigins выключает CORS
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
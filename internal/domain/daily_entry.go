@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryFirstVersion - версия только что созданной записи
+const EntryFirstVersion = 1
+
+// DailyEntry - запись за день; Version растет на каждое изменение и отдается клиенту как ETag
+type DailyEntry struct {
+	Id         uuid.UUID `json:"-"`
+	Date       time.Time `json:"date"`
+	Mood       int16     `json:"mood"`
+	SleepHours float64   `json:"sleep_hours"`
+	Load       int16     `json:"load"`
+	Version    int       `json:"version"`
+}
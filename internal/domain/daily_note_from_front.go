@@ -1,7 +1,7 @@
 package domain
 
 type DailyNoteFromFront struct {
-	Mood       int16   `json:"mood"`
-	SleepHours float64 `json:"sleep_hours"`
-	Load       int16   `json:"load"`
+	Mood       int16   `json:"mood" binding:"mood"`
+	SleepHours float64 `json:"sleep_hours" binding:"sleep_hours"`
+	Load       int16   `json:"load" binding:"load"`
 }
@@ -1,9 +1,28 @@
 package domain
 
+import "fmt"
+
+// что делать со схемой базы при старте сервера
+type MigrationsMode string
+
+const (
+	MigrationsModeAuto  MigrationsMode = "auto"  // применить встроенные миграции, затем проверить версию
+	MigrationsModeCheck MigrationsMode = "check" // только проверить версию, миграции запускаются отдельно (chopper migrate up)
+)
+
 type DataBaseConfig struct {
-	User     string
-	Password string
-	Host     string
-	Port     string
-	Name     string
+	User       string
+	Password   string
+	Host       string
+	Port       string
+	Name       string
+	SSLMode    string
+	MaxConns   int32
+	MinConns   int32
+	Migrations MigrationsMode
+}
+
+// String скрывает пароль, чтобы конфиг можно было безопасно логировать
+func (d DataBaseConfig) String() string {
+	return fmt.Sprintf("{User:%v Password:*** Host:%v Port:%v Name:%v SSLMode:%v MaxConns:%v MinConns:%v Migrations:%v}", d.User, d.Host, d.Port, d.Name, d.SSLMode, d.MaxConns, d.MinConns, d.Migrations)
 }
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEventType - что произошло; подписчики релея выбирают события по типу
+type DomainEventType string
+
+const (
+	DomainEventUserRegistered DomainEventType = "user.registered"
+	DomainEventEntryCreated   DomainEventType = "entry.created"
+	DomainEventEntryChanged   DomainEventType = "entry.changed"
+	DomainEventAlertRaised    DomainEventType = "alert.raised"
+)
+
+// DomainEvent - запись outbox. Payload - json, у записей и алертов это UserEventPayload, у регистрации - UserRegisteredPayload
+type DomainEvent struct {
+	Id         int64
+	Type       DomainEventType
+	UserId     uuid.UUID
+	Payload    json.RawMessage
+	OccurredAt time.Time
+	Attempts   int // неудачных попыток доставки
+}
+
+type UserRegisteredPayload struct {
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryChangeReason - откуда взялось изменение
+type EntryChangeReason string
+
+const (
+	EntryChangeCreated  EntryChangeReason = "created"
+	EntryChangeEdited   EntryChangeReason = "edited"
+	EntryChangeReverted EntryChangeReason = "reverted"
+)
+
+// EntryChange - изменение одного поля записи. Version - версия записи после изменения,
+// OldValue == nil - поле появилось при создании записи
+type EntryChange struct {
+	EntryId   uuid.UUID         `json:"-"`
+	UserId    uuid.UUID         `json:"-"`
+	Version   int               `json:"version"`
+	Field     EntryField        `json:"field"`
+	OldValue  *float64          `json:"old_value"`
+	NewValue  float64           `json:"new_value"`
+	Reason    EntryChangeReason `json:"reason"`
+	ChangedAt time.Time         `json:"changed_at"`
+	ClientIP  string            `json:"client_ip"`
+	UserAgent string            `json:"user_agent"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+// EntryField - изменяемое поле записи, совпадает с колонкой DailyEntries и полем в JSON
+type EntryField string
+
+const (
+	EntryFieldMood       EntryField = "mood"
+	EntryFieldSleepHours EntryField = "sleep_hours"
+	EntryFieldLoad       EntryField = "load"
+)
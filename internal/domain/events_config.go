@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// EventsConfig - SSE поток событий пользователя
+type EventsConfig struct {
+	HeartbeatInterval time.Duration // комментарий в поток, чтобы прокси не закрывали простаивающее соединение
+	Retention         time.Duration // сколько хранить события для возобновления по Last-Event-ID
+	MaxStreams        int           // одновременных потоков на пользователя
+}
@@ -0,0 +1,6 @@
+package domain
+
+// I18nConfig - DefaultLanguage отдается, если ни настройка пользователя, ни Accept-Language не подошли
+type I18nConfig struct {
+	DefaultLanguage string
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "time"
+
+// IdempotencyConfig - сколько хранится ответ на запрос с Idempotency-Key
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
@@ -0,0 +1,9 @@
+package domain
+
+// IdempotencyRecord - сохраненный результат запроса с Idempotency-Key; Status == 0 - запрос еще выполняется
+type IdempotencyRecord struct {
+	Fingerprint string
+	Status      int
+	Headers     map[string]string
+	Body        []byte
+}
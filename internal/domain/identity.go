@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Identity - владелец запроса после проверки токена; Scopes заданы только у персональных токенов
+type Identity struct {
+	UserId   uuid.UUID
+	Username string
+	ApiToken bool
+	Scopes   []Scope
+	Language string // сохраненный язык ответов, "" - по Accept-Language
+}
+
+// Allows - JWT сессии доступно все, персональному токену - только его scope
+func (i Identity) Allows(scope Scope) bool {
+	return !i.ApiToken || slices.Contains(i.Scopes, scope)
+}
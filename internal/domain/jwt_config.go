@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type JWtConfig struct {
 	Secret         []byte
@@ -8,3 +11,8 @@ type JWtConfig struct {
 	Issuer         string
 	Audience       string
 }
+
+// String скрывает секрет, чтобы конфиг можно было безопасно логировать
+func (j JWtConfig) String() string {
+	return fmt.Sprintf("{Secret:*** ExpirationTime:%v Issuer:%v Audience:%v}", j.ExpirationTime, j.Issuer, j.Audience)
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// LegacyAPIConfig - старые пути без /api/v1. Пока Enabled, они работают как алиасы v1
+// и отвечают заголовками Deprecation и Sunset
+type LegacyAPIConfig struct {
+	Enabled      bool
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
@@ -0,0 +1,13 @@
+package domain
+
+type LogFormat string
+
+const (
+	LogFormatJSON LogFormat = "json"
+	LogFormatText LogFormat = "text"
+)
+
+type LoggerConfig struct {
+	Level  string
+	Format LogFormat
+}
@@ -0,0 +1,16 @@
+package domain
+
+// MessageKey - ключ сообщения для клиента; текст на языке запроса подставляет пакет i18n
+type MessageKey string
+
+const (
+	MessageMoodChanged       MessageKey = "note.mood_changed"
+	MessageSleepHoursChanged MessageKey = "note.sleep_hours_changed"
+	MessageLoadChanged       MessageKey = "note.load_changed"
+	MessageNoteReverted      MessageKey = "note.reverted"
+
+	MessageAlertOk        MessageKey = "alert.ok"
+	MessageAlertMoodSleep MessageKey = "alert.mood_sleep"
+	MessageAlertMoodLoad  MessageKey = "alert.mood_load"
+	MessageAlertSleepLoad MessageKey = "alert.sleep_load"
+)
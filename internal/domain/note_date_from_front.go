@@ -0,0 +1,7 @@
+package domain
+
+import "time"
+
+type NoteDateFromFront struct {
+	Date time.Time `form:"date" time_format:"2006-01-02" binding:"required"`
+}
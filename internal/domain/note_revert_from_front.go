@@ -0,0 +1,8 @@
+package domain
+
+import "time"
+
+type NoteRevertFromFront struct {
+	Date    time.Time `json:"date" binding:"required"`
+	Version int       `json:"version" binding:"required,min=1"`
+}
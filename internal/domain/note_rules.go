@@ -0,0 +1,23 @@
+package domain
+
+// диапазоны значений записи - единственное место, где они заданы
+const (
+	MoodMin       int16   = 0
+	MoodMax       int16   = 10
+	SleepHoursMin float64 = 0
+	SleepHoursMax float64 = 9.9
+	LoadMin       int16   = 0
+	LoadMax       int16   = 10
+)
+
+func ValidMood(mood int16) bool {
+	return mood >= MoodMin && mood <= MoodMax
+}
+
+func ValidSleepHours(sleepHours float64) bool {
+	return sleepHours >= SleepHoursMin && sleepHours <= SleepHoursMax
+}
+
+func ValidLoad(load int16) bool {
+	return load >= LoadMin && load <= LoadMax
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// OutboxConfig - релей доменных событий из таблицы Outbox подписчикам
+type OutboxConfig struct {
+	PollInterval time.Duration // как часто релей проверяет новые события
+	BatchSize    int           // событий за один проход
+	MaxAttempts  int           // после стольких неудач событие остается в таблице и больше не рассылается
+	Retention    time.Duration // сколько хранить разосланные события
+}
@@ -0,0 +1,12 @@
+package domain
+
+// PasswordPolicy - требования к новым паролям (регистрация, user create и set-password в CLI)
+type PasswordPolicy struct {
+	MinLength     int // в символах
+	MaxLength     int // в байтах, bcrypt не учитывает больше 72
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	BlocklistFile string // дополнительный список запрещенных паролей, по одному в строке
+}
@@ -0,0 +1,17 @@
+package domain
+
+// группы роутов, которым в конфиге назначаются политики рейт лимитера
+const (
+	RateLimitGroupUsersPublic  = "users_public"
+	RateLimitGroupUsers        = "users"
+	RateLimitGroupUsersSession = "users_session"
+	RateLimitGroupNotesRead    = "notes_read"
+	RateLimitGroupNotes        = "notes"
+	RateLimitGroupAlert        = "alert"
+	RateLimitGroupTokens       = "tokens"
+	RateLimitGroupEvents       = "events"
+
+	// gRPC: Register и Login - публичные методы, остальные под аутентификацией
+	RateLimitGroupGRPCPublic = "grpc_public"
+	RateLimitGroupGRPC       = "grpc"
+)
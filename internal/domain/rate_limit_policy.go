@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// по какому признаку клиенты делят одну корзину
+type RateLimitKey string
+
+const (
+	RateLimitKeyIP     RateLimitKey = "ip"
+	RateLimitKeyUser   RateLimitKey = "user"
+	RateLimitKeyUserIP RateLimitKey = "user_ip"
+)
+
+// имена встроенных политик
+const (
+	RateLimitPolicyAuth = "auth"
+	RateLimitPolicyUser = "user"
+)
+
+type RateLimitPolicy struct {
+	Name  string
+	Rate  time.Duration // интервал пополнения одного токена
+	Burst int
+	Key   RateLimitKey
+}
+
+func (k RateLimitKey) IsValid() bool {
+	switch k {
+	case RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyUserIP:
+		return true
+	}
+	return false
+}
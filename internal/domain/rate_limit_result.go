@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // через сколько корзина снова будет полной
+	RetryAfter time.Duration // через сколько появится следующий токен (если запрос отклонен)
+}
@@ -2,7 +2,17 @@ package domain
 
 import "time"
 
+// где хранятся корзины лимитера
+type LimiterStore string
+
+const (
+	LimiterStoreMemory   LimiterStore = "memory"
+	LimiterStorePostgres LimiterStore = "postgres"
+)
+
 type RateLimiterConfig struct {
-	Rate  time.Duration
-	Burst int
+	Policies    map[string]RateLimitPolicy
+	Groups      map[string]string // группа роутов -> имя политики
+	IdleTimeout time.Duration
+	Store       LimiterStore
 }
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reminder - напоминание пользователю, который еще не заполнил сегодняшний день
+type Reminder struct {
+	UserId   uuid.UUID
+	Username string
+	Email    string
+	Language string    // сохраненный язык, "" - язык по умолчанию
+	Date     time.Time // сегодняшняя дата в часовом поясе пользователя
+	Attempt  int       // номер попытки, начиная с 1
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// ReminderTimeLayout - формат времени напоминания, локальное время пользователя
+const ReminderTimeLayout = "15:04"
+
+// ValidTimeZone - только имена из базы IANA: Local и пустая строка зависят от сервера
+func ValidTimeZone(timeZone string) bool {
+	if timeZone == "" || timeZone == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(timeZone)
+	return err == nil
+}
+
+func ValidReminderTime(reminderTime string) bool {
+	_, err := time.Parse(ReminderTimeLayout, reminderTime)
+	return err == nil
+}
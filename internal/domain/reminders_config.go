@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// куда уходят напоминания
+type ReminderNotifier string
+
+const (
+	ReminderNotifierLog     ReminderNotifier = "log"
+	ReminderNotifierWebhook ReminderNotifier = "webhook"
+)
+
+// RemindersConfig - планировщик напоминаний о незаполненном дне
+type RemindersConfig struct {
+	Enabled        bool
+	CheckInterval  time.Duration // как часто искать пользователей, у которых наступило время напоминания
+	BatchSize      int           // напоминаний за один проход
+	MaxAttempts    int           // попыток доставки за день
+	RetryDelay     time.Duration // пауза перед первым повтором неудачной доставки, дальше удваивается
+	Notifier       ReminderNotifier
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
@@ -0,0 +1,8 @@
+package domain
+
+// SchemaVersion - версия схемы в базе и версия, с которой собран бинарник
+type SchemaVersion struct {
+	Current  uint `json:"current"`
+	Expected uint `json:"expected"`
+	Dirty    bool `json:"dirty"`
+}
@@ -0,0 +1,6 @@
+package domain
+
+type SecurityConfig struct {
+	BcryptCost     int
+	PasswordPolicy PasswordPolicy
+}
@@ -4,9 +4,16 @@ import "time"
 
 type ServerConfig struct {
 	Address        string
+	AdminAddress   string
 	ReadTimeout    time.Duration
 	WriteTimeout   time.Duration
 	IdleTimeout    time.Duration
 	TimeToShutdown time.Duration
+	DrainDelay     time.Duration
 	ServerMode     ServerMode
+	TLS            TLSConfig
+	UnixSocket     string // путь к unix сокету для sidecar прокси, пустой - не слушать
+	HTTP2          bool   // HTTP/2 поверх TLS и h2c на открытых соединениях
+	LegacyAPI      LegacyAPIConfig
+	GRPCAddress    string // адрес gRPC API для внутренних сервисов, пустой - не слушать
 }
@@ -0,0 +1,12 @@
+package domain
+
+// TLSConfig - пустые CertFile и KeyFile выключают TLS, ClientCAFile включает mTLS
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
@@ -0,0 +1,15 @@
+package domain
+
+type TraceExporter string
+
+const (
+	TraceExporterNone   TraceExporter = "none"
+	TraceExporterStdout TraceExporter = "stdout"
+	TraceExporterOTLP   TraceExporter = "otlp"
+)
+
+type TracingConfig struct {
+	Exporter    TraceExporter
+	ServiceName string
+	SampleRatio float64
+}
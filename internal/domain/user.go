@@ -14,4 +14,6 @@ type User struct {
 	Role         Role
 	CreatedAt    time.Time
 	DeletedAt    *time.Time
+	SuspendedAt  *time.Time
+	Language     string
 }
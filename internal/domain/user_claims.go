@@ -9,4 +9,6 @@ type UserClaims struct {
 	Username string
 	Email    string
 	Role     Role
+	// Language не попадает в JWT: настройку можно поменять, пока токен жив
+	Language string `json:"-"`
 }
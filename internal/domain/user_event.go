@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserEventType - имя события в SSE потоке (поле event)
+type UserEventType string
+
+const (
+	UserEventEntryCreated UserEventType = "entry.created"
+	UserEventEntryChanged UserEventType = "entry.changed"
+	UserEventAlert        UserEventType = "alert"
+)
+
+// UserEvent - изменение данных пользователя для клиентов в реальном времени; Id растет и служит Last-Event-ID
+type UserEvent struct {
+	Id        int64
+	UserId    uuid.UUID
+	Type      UserEventType
+	Payload   UserEventPayload
+	CreatedAt time.Time
+}
+
+// UserEventPayload - у записей дата и измененные поля, у алерта - ключ сообщения
+type UserEventPayload struct {
+	Date       string     `json:"date,omitempty"` // YYYY-MM-DD
+	Mood       *int16     `json:"mood,omitempty"`
+	SleepHours *float64   `json:"sleep_hours,omitempty"`
+	Load       *int16     `json:"load,omitempty"`
+	Key        MessageKey `json:"key,omitempty"`
+}
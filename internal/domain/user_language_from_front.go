@@ -0,0 +1,5 @@
+package domain
+
+type UserLanguageFromFront struct {
+	Language string `json:"language" binding:"omitempty,language"`
+}
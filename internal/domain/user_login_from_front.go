@@ -1,6 +1,6 @@
 package domain
 
 type UserLoginFromFront struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
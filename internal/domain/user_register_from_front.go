@@ -1,7 +1,7 @@
 package domain
 
 type UserRegisterFromFront struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required,username"`
+	Email    string `json:"email" binding:"required,email_length,email"`
+	Password string `json:"password" binding:"required,password"`
 }
@@ -0,0 +1,7 @@
+package domain
+
+// UserReminderFromFront - пустой time выключает напоминания
+type UserReminderFromFront struct {
+	TimeZone string `json:"time_zone" binding:"required_with=Time,omitempty,time_zone"`
+	Time     string `json:"time" binding:"omitempty,reminder_time"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "regexp"
+
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 32
+	EmailMaxLength    = 254
+)
+
+// имя пользователя попадает в логи и URL, поэтому только латиница, цифры и _ . -
+var usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+func ValidUsername(username string) bool {
+	return len(username) >= UsernameMinLength && len(username) <= UsernameMaxLength && usernamePattern.MatchString(username)
+}
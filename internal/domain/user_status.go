@@ -0,0 +1,7 @@
+package domain
+
+// UserStatus - то, что нужно проверить на каждый запрос: активность и язык ответов ("" - по Accept-Language)
+type UserStatus struct {
+	Active   bool
+	Language string
+}
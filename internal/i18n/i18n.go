@@ -0,0 +1,174 @@
+// Package i18n - каталог сообщений для клиентов. Тексты лежат в locales/<язык>.yaml,
+// код оперирует только ключами; язык запроса хранится в context
+package i18n
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// Fallback - язык, на который откатывается перевод, если ключа нет в выбранном языке.
+// В нем должны быть все ключи, это проверяет тест
+const Fallback = "en"
+
+//go:embed locales/*.yaml
+var locales embed.FS
+
+type catalog struct {
+	messages  map[string]map[string]string
+	languages []string
+	matcher   language.Matcher
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *catalog
+	loadErr  error
+)
+
+func load() (*catalog, error) {
+	loadOnce.Do(func() {
+		loaded, loadErr = parse()
+	})
+	return loaded, loadErr
+}
+
+func parse() (*catalog, error) {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	c := &catalog{messages: map[string]map[string]string{}}
+	for _, file := range files {
+		lang := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+		if _, err := language.Parse(lang); err != nil {
+			return nil, fmt.Errorf("locale %v: %w", file.Name(), err)
+		}
+		data, err := locales.ReadFile("locales/" + file.Name())
+		if err != nil {
+			return nil, err
+		}
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("locale %v: %w", file.Name(), err)
+		}
+		c.messages[lang] = messages
+		c.languages = append(c.languages, lang)
+	}
+	if _, ok := c.messages[Fallback]; !ok {
+		return nil, fmt.Errorf("fallback locale %v is missing", Fallback)
+	}
+	slices.Sort(c.languages)
+	tags := make([]language.Tag, 0, len(c.languages))
+	for _, lang := range c.languages {
+		tags = append(tags, language.MustParse(lang))
+	}
+	c.matcher = language.NewMatcher(tags)
+	return c, nil
+}
+
+// Check проверяет встроенный каталог; вызывается при старте, чтобы битый файл не всплыл на первом запросе
+func Check() error {
+	_, err := load()
+	return err
+}
+
+func mustLoad() *catalog {
+	c, err := load()
+	if err != nil {
+		panic(fmt.Sprintf("i18n: %v", err))
+	}
+	return c
+}
+
+// Languages - коды языков, для которых есть каталог
+func Languages() []string {
+	return slices.Clone(mustLoad().languages)
+}
+
+func Supported(lang string) bool {
+	_, ok := mustLoad().messages[lang]
+	return ok
+}
+
+// Negotiate выбирает язык по заголовку Accept-Language; если ничего не подошло - fallback
+func Negotiate(acceptLanguage, fallback string) string {
+	c := mustLoad()
+	if strings.TrimSpace(acceptLanguage) == "" {
+		return fallback
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fallback
+	}
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		return fallback
+	}
+	return c.languages[index]
+}
+
+// Message - ключ с параметрами, переводится в момент ответа на языке запроса
+type Message struct {
+	Key  string
+	Args []any
+}
+
+// Msg - args попарно: имя подстановки и значение, например Msg("detail.retry_in", "seconds", 5)
+func Msg(key string, args ...any) Message {
+	return Message{Key: key, Args: args}
+}
+
+func (m Message) IsZero() bool {
+	return m.Key == ""
+}
+
+// In переводит сообщение на lang; нет ключа - берется Fallback, нет и там - сам ключ
+func (m Message) In(lang string) string {
+	if m.Key == "" {
+		return ""
+	}
+	c := mustLoad()
+	text, ok := c.messages[lang][m.Key]
+	if !ok {
+		text, ok = c.messages[Fallback][m.Key]
+	}
+	if !ok {
+		return m.Key
+	}
+	if len(m.Args) == 0 {
+		return text
+	}
+	replacements := make([]string, 0, len(m.Args))
+	for i := 0; i+1 < len(m.Args); i += 2 {
+		replacements = append(replacements, "{"+fmt.Sprint(m.Args[i])+"}", fmt.Sprint(m.Args[i+1]))
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+type ctxKey struct{}
+
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// Language - язык запроса; без middleware (тесты, CLI) - Fallback
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Fallback
+}
+
+// T переводит ключ на язык из context
+func T(ctx context.Context, key string, args ...any) string {
+	return Msg(key, args...).In(Language(ctx))
+}
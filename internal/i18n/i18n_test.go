@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var placeholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// Тест каталога - в каждом языке те же ключи и те же подстановки, что в Fallback
+func TestCatalogComplete(t *testing.T) {
+	// preparing
+	c, err := load()
+	if err != nil {
+		t.Fatalf("load - %v", err)
+	}
+	base := c.messages[Fallback]
+	for _, lang := range c.languages {
+		messages := c.messages[lang]
+		t.Run(lang, func(t *testing.T) {
+			// assert
+			for key, text := range base {
+				translated, ok := messages[key]
+				if !ok {
+					t.Errorf("нет ключа %v", key)
+					continue
+				}
+				expected, got := placeholder.FindAllString(text, -1), placeholder.FindAllString(translated, -1)
+				slices.Sort(expected)
+				slices.Sort(got)
+				if !slices.Equal(expected, got) {
+					t.Errorf("ключ %v: ожидались подстановки %v, получены - %v", key, expected, got)
+				}
+			}
+			for key := range messages {
+				if _, ok := base[key]; !ok {
+					t.Errorf("лишний ключ %v, его нет в %v", key, Fallback)
+				}
+			}
+		})
+	}
+}
+
+// Тест Negotiate - q-веса, региональные варианты и откат на язык по умолчанию
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", "ru"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"ru-RU", "ru"},
+		{"de-DE,en;q=0.5,ru;q=0.8", "ru"},
+		{"ja", "ru"},
+		{"не заголовок;;", "ru"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			// test
+			got := Negotiate(tt.header, "ru")
+
+			// assert
+			if got != tt.expected {
+				t.Errorf("ожидался %v, получен - %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Тест перевода - подстановки, откат на Fallback и ключ без перевода
+func TestTranslate(t *testing.T) {
+	// preparing
+	ctx := WithLanguage(context.Background(), "ru")
+
+	// test
+	retry := T(ctx, "detail.retry_in", "seconds", 5)
+	unknown := T(ctx, "no.such.key")
+	english := T(context.Background(), "alert.ok")
+
+	// assert
+	if retry != "повторите через 5 с" {
+		t.Errorf("неожиданный перевод - %v", retry)
+	}
+	if unknown != "no.such.key" {
+		t.Errorf("ожидался сам ключ, получено - %v", unknown)
+	}
+	if english != "All good" {
+		t.Errorf("без языка в context ожидался %v, получено - %v", Fallback, english)
+	}
+}
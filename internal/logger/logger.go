@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ctxKey struct{}
+
+// New настраивает стандартный логгер logrus, чтобы и main, и все слои писали в одном формате
+func New(config domain.LoggerConfig) (*logrus.Logger, error) {
+	level, err := logrus.ParseLevel(config.Level)
+	if err != nil {
+		return nil, fmt.Errorf("wrong log level field: %w", err)
+	}
+	log := logrus.StandardLogger()
+	log.SetLevel(level)
+	switch config.Format {
+	case domain.LogFormatJSON:
+		log.SetFormatter(&logrus.JSONFormatter{})
+	case domain.LogFormatText:
+		log.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return nil, fmt.Errorf("wrong log format field")
+	}
+	return log, nil
+}
+
+// WithContext кладет логгер запроса в контекст
+func WithContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, ctxKey{}, entry)
+}
+
+// FromContext достает логгер запроса (с request_id и т.д.), вне запроса возвращает стандартный
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(ctxKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
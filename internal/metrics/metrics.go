@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "chopper"
+
+// Metrics - все метрики сервиса в отдельном реестре (отдается на admin листенере)
+type Metrics struct {
+	registry            *prometheus.Registry
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	rateLimitRejections *prometheus.CounterVec
+	logins              *prometheus.CounterVec
+	entriesCreated      prometheus.Counter
+	alertsTriggered     *prometheus.CounterVec
+	remindersDelivered  *prometheus.CounterVec
+}
+
+func New(pool *pgxpool.Pool) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		rateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_rejections_total",
+			Help:      "Requests rejected by the rate limiter by policy.",
+		}, []string{"policy"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Login attempts by result.",
+		}, []string{"result"}),
+		entriesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "daily_entries_created_total",
+			Help:      "Daily entries created, use increase(...[1d]) for entries per day.",
+		}),
+		alertsTriggered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "alerts_triggered_total",
+			Help:      "Alerts raised by a note change, by rule.",
+		}, []string{"rule"}),
+		remindersDelivered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reminders_delivered_total",
+			Help:      "Daily reminder delivery attempts by result.",
+		}, []string{"result"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.rateLimitRejections,
+		m.logins,
+		m.entriesCreated,
+		m.alertsTriggered,
+		m.remindersDelivered,
+	)
+	if pool != nil {
+		m.registry.MustRegister(newPoolCollector(pool))
+	}
+	return m
+}
+
+// Handler отдает метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveRequest(route, method string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(route, method, statusLabel).Inc()
+	m.httpDuration.WithLabelValues(route, method, statusLabel).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RateLimitRejected(policy string) {
+	m.rateLimitRejections.WithLabelValues(policy).Inc()
+}
+
+func (m *Metrics) LoginSucceeded() {
+	m.logins.WithLabelValues("success").Inc()
+}
+
+func (m *Metrics) LoginFailed() {
+	m.logins.WithLabelValues("failure").Inc()
+}
+
+func (m *Metrics) EntryCreated() {
+	m.entriesCreated.Inc()
+}
+
+func (m *Metrics) AlertTriggered(rule string) {
+	m.alertsTriggered.WithLabelValues(rule).Inc()
+}
+
+func (m *Metrics) ReminderDelivered(result string) {
+	m.remindersDelivered.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Тест Handler - счетчики из хуков попадают в вывод /metrics
+func TestMetricsHandler(t *testing.T) {
+	// preparing
+	m := New(nil)
+	m.ObserveRequest("/notes/new", http.MethodPost, http.StatusCreated, 30*time.Millisecond)
+	m.RateLimitRejected("auth")
+	m.LoginFailed()
+	m.EntryCreated()
+	m.AlertTriggered("mood_sleep")
+	w := httptest.NewRecorder()
+
+	// test
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// assert
+	body, _ := io.ReadAll(w.Body)
+	expected := []string{
+		`chopper_http_requests_total{method="POST",route="/notes/new",status="201"} 1`,
+		`chopper_rate_limit_rejections_total{policy="auth"} 1`,
+		`chopper_logins_total{result="failure"} 1`,
+		`chopper_daily_entries_created_total 1`,
+		`chopper_alerts_triggered_total{rule="mood_sleep"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("expected metric line was - %v", line)
+		}
+	}
+}
@@ -1,20 +1,25 @@
 package middleware
 
 import (
-	"chopper/internal/security"
-	"net/http"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/problem"
+	"chopper/internal/usecase"
+	"errors"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthMiddleware struct {
-	jwt *security.Jwt
+	authenticator *usecase.Authenticator
 }
 
-func NewAuthMiddleware(jwt *security.Jwt) *AuthMiddleware {
+func NewAuthMiddleware(authenticator *usecase.Authenticator) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwt: jwt,
+		authenticator: authenticator,
 	}
 }
 
@@ -22,27 +27,60 @@ func (a *AuthMiddleware) Auth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
 		if header == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "no token",
-			})
+			problem.Abort(c, problem.Unauthorized, i18n.Msg("detail.no_token"))
 			return
 		}
 		if !strings.HasPrefix(header, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid authorization field",
-			})
+			problem.Abort(c, problem.Unauthorized, i18n.Msg("detail.bearer_format"))
 			return
 		}
-		token := strings.TrimPrefix(header, "Bearer ")
-		claims, err := a.jwt.ValidateToken(token)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token",
-			})
+		identity, err := a.authenticator.Authenticate(c.Request.Context(), strings.TrimPrefix(header, "Bearer "))
+		if err != nil && errors.Is(err, usecase.ErrUnauthenticated) {
+			logger.FromContext(c.Request.Context()).WithError(err).Info("token rejected")
+			problem.Abort(c, problem.Unauthorized, i18n.Msg("detail.invalid_token"))
+			return
+		} else if err != nil {
+			logger.FromContext(c.Request.Context()).WithError(err).Error("authentication failed")
+			problem.Abort(c, problem.InternalError, i18n.Message{})
+			return
+		}
+		c.Set("user_id", identity.UserId)
+		c.Set("username", identity.Username)
+		if identity.ApiToken {
+			c.Set("scopes", identity.Scopes)
+		}
+		// сохраненная настройка важнее Accept-Language
+		if identity.Language != "" {
+			setLanguage(c, identity.Language)
+		}
+		c.Next()
+	}
+}
+
+// RequireScope пропускает JWT сессии целиком, а персональные токены - только с нужным scope
+func (a *AuthMiddleware) RequireScope(scope domain.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, ok := c.Get("scopes")
+		if !ok {
+			c.Next()
+			return
+		}
+		scopes, ok := s.([]domain.Scope)
+		if !ok || !slices.Contains(scopes, scope) {
+			problem.Abort(c, problem.InsufficientScope, i18n.Msg("detail.scope_required", "scope", scope))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession запрещает доступ по персональным токенам (например, к управлению самими токенами)
+func (a *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("scopes"); ok {
+			problem.Abort(c, problem.SessionRequired, i18n.Msg("detail.session_required"))
 			return
 		}
-		c.Set("user_id", claims.Id)
-		c.Set("username", claims.Username)
 		c.Next()
 	}
 }
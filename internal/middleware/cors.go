@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CORSMiddleware struct {
+	config atomic.Pointer[domain.CORSConfig]
+}
+
+func NewCORSMiddleware(config domain.CORSConfig) *CORSMiddleware {
+	corsMiddleware := &CORSMiddleware{}
+	corsMiddleware.config.Store(&config)
+	return corsMiddleware
+}
+
+// SetConfig атомарно подменяет настройки CORS при перезагрузке конфига
+func (m *CORSMiddleware) SetConfig(config domain.CORSConfig) {
+	m.config.Store(&config)
+}
+
+// Handle ставит CORS заголовки для разрешенных Origin и сам отвечает на preflight запросы
+func (m *CORSMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		config := m.config.Load()
+		origin := c.GetHeader("Origin")
+		if origin == "" || len(config.AllowedOrigins) == 0 {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+		allowed := slices.Contains(config.AllowedOrigins, "*") || slices.Contains(config.AllowedOrigins, origin)
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !allowed {
+			// чужой Origin не блокируется на сервере, браузер сам не отдаст ответ без заголовков
+			if preflight {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+			return
+		}
+		if slices.Contains(config.AllowedOrigins, "*") {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+			c.Header("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+			if config.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		if len(config.ExposedHeaders) > 0 {
+			c.Header("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation помечает ответы устаревших роутов: Deprecation (RFC 9745), Sunset (RFC 8594)
+// и Link на тот же путь в актуальной версии. successorPrefix - например "/api/v1"
+func Deprecation(config domain.LegacyAPIConfig, successorPrefix string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", config.DeprecatedAt.Unix())
+	sunset := config.Sunset.UTC().Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", deprecation)
+		header.Set("Sunset", sunset)
+		header.Add("Link", fmt.Sprintf("<%v%v>; rel=\"successor-version\"", successorPrefix, c.Request.URL.Path))
+		c.Next()
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/problem"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLength  = 255
+	idempotencyLockTimeout   = time.Minute // сколько ключ считается занятым, если реплика упала посреди запроса
+)
+
+// заголовки ответа, которые сохраняются и отдаются при повторе вместе с телом
+var idempotencyStoredHeaders = []string{"Content-Type", "Content-Language", "Location", "ETag"}
+
+type Idempotency struct {
+	store IdempotencyStore
+	ttl   time.Duration
+}
+
+func NewIdempotency(store IdempotencyStore, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Idempotent сохраняет ответ на изменяющий запрос с заголовком Idempotency-Key и отдает его на повторы.
+// Ключ действует в пределах пользователя (для публичных роутов - ip). Тот же ключ с другим телом - 422,
+// повтор, пока первый запрос выполняется, - 409. Ответы 5xx и с Cache-Control: no-store не сохраняются
+func (i *Idempotency) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := c.Request.Header[IdempotencyKeyHeader]
+		if !ok || !isStateChanging(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) != 1 || !validIdempotencyKey(key[0]) {
+			problem.Abort(c, problem.InvalidIdempotencyKey, i18n.Msg("detail.idempotency_key_format", "max", idempotencyKeyMaxLength))
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			problem.Abort(c, problem.InvalidBody, i18n.Msg("detail.invalid_body", "error", err.Error()))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := clientKey(domain.RateLimitKeyUser, c.ClientIP(), contextUserId(c))
+		fingerprint := requestFingerprint(c.Request.Method, c.FullPath(), c.GetHeader("If-Match"), body)
+		record, started, err := i.store.Begin(ctx, scope, key[0], fingerprint, time.Now().Add(idempotencyLockTimeout))
+		if err != nil {
+			// хранилище недоступно - выполняем запрос без защиты от повторов, как rate limiter
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+		if !started {
+			switch {
+			case record.Fingerprint != fingerprint:
+				problem.Abort(c, problem.IdempotencyKeyReused, i18n.Msg("detail.idempotency_key_reused"))
+			case record.Status == 0:
+				c.Header("Retry-After", "1")
+				problem.Abort(c, problem.IdempotencyInProgress, i18n.Msg("detail.idempotency_in_progress"))
+			default:
+				replay(c, record)
+			}
+			return
+		}
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// ответ сохраняется и после обрыва соединения клиента, иначе повтор выполнит запрос второй раз
+		storeCtx := context.WithoutCancel(ctx)
+		completed := false
+		defer func() {
+			// паника или ответ, который нельзя сохранять: ключ освобождается, повтор выполнится заново
+			if !completed {
+				if err := i.store.Release(storeCtx, scope, key[0]); err != nil {
+					logger.FromContext(ctx).WithError(err).Error("idempotency key release failed")
+				}
+			}
+		}()
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || strings.Contains(writer.Header().Get("Cache-Control"), "no-store") {
+			return
+		}
+		record = domain.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Headers:     map[string]string{},
+			Body:        writer.body.Bytes(),
+		}
+		for _, name := range idempotencyStoredHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				record.Headers[name] = value
+			}
+		}
+		if err := i.store.Complete(storeCtx, scope, key[0], record, time.Now().Add(i.ttl)); err != nil {
+			logger.FromContext(ctx).WithError(err).Error("idempotency response save failed")
+			return
+		}
+		completed = true
+	}
+}
+
+// Cleanup удаляет истекшие ключи
+func (i *Idempotency) Cleanup(ctx context.Context) error {
+	deleted, err := i.store.DeleteExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).WithField("deleted", deleted).Info("expired idempotency keys cleaned up")
+	}
+	return nil
+}
+
+func replay(c *gin.Context, record domain.IdempotencyRecord) {
+	for name, value := range record.Headers {
+		c.Header(name, value)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(record.Status)
+	if len(record.Body) > 0 {
+		_, _ = c.Writer.Write(record.Body)
+	}
+	c.Abort()
+}
+
+func isStateChanging(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// validIdempotencyKey - 1..255 печатных ASCII символов
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > idempotencyKeyMaxLength {
+		return false
+	}
+	for _, r := range key {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint - хэш метода, шаблона роута, If-Match и тела: тот же ключ на другой запрос - ошибка клиента.
+// If-Match входит в отпечаток, иначе повтор с другим предусловием получил бы чужой сохраненный ответ
+func requestFingerprint(method, route, ifMatch string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + route + "\n" + ifMatch + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// capturingWriter пишет ответ клиенту и копит тело для сохранения
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
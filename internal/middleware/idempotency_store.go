@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+)
+
+// IdempotencyStore - хранилище ответов на запросы с Idempotency-Key. Общее для всех реплик,
+// поэтому повтор, пришедший на другой экземпляр, получает тот же ответ
+type IdempotencyStore interface {
+	// Begin занимает ключ до lockExpiresAt. Если ключ уже занят и не истек - started == false
+	// и возвращается сохраненная запись
+	Begin(ctx context.Context, scope, key, fingerprint string, lockExpiresAt time.Time) (record domain.IdempotencyRecord, started bool, err error)
+	// Complete сохраняет ответ до expiresAt
+	Complete(ctx context.Context, scope, key string, record domain.IdempotencyRecord, expiresAt time.Time) error
+	// Release освобождает ключ, чтобы повтор выполнился заново
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
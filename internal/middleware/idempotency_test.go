@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Моки
+// Мок хранилища - записи в памяти, истечение не проверяется
+type MockIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func (m *MockIdempotencyStore) Begin(ctx context.Context, scope, key, fingerprint string, lockExpiresAt time.Time) (domain.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[scope+"|"+key]; ok {
+		return record, false, nil
+	}
+	m.records[scope+"|"+key] = domain.IdempotencyRecord{Fingerprint: fingerprint}
+	return domain.IdempotencyRecord{Fingerprint: fingerprint}, true, nil
+}
+
+func (m *MockIdempotencyStore) Complete(ctx context.Context, scope, key string, record domain.IdempotencyRecord, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[scope+"|"+key] = record
+	return nil
+}
+
+func (m *MockIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, scope+"|"+key)
+	return nil
+}
+
+func (m *MockIdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// newIdempotencyRouter - хендлер считает вызовы и отвечает 201 с номером вызова
+func newIdempotencyRouter(store IdempotencyStore, calls *int, cacheControl string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(NewIdempotency(store, time.Hour).Idempotent())
+	r.POST("/notes/new", func(c *gin.Context) {
+		*calls++
+		if cacheControl != "" {
+			c.Header("Cache-Control", cacheControl)
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": *calls})
+	})
+	return r
+}
+
+func idempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/notes/new", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req
+}
+
+// Тест - повтор с тем же ключом и телом получает сохраненный ответ, хендлер не вызывается второй раз
+func TestIdempotencyReplay(t *testing.T) {
+	// preparing
+	calls := 0
+	router := newIdempotencyRouter(&MockIdempotencyStore{records: map[string]domain.IdempotencyRecord{}}, &calls, "")
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, idempotentRequest("key-1", `{"mood":7}`))
+
+	// test
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, idempotentRequest("key-1", `{"mood":7}`))
+
+	// assert
+	if calls != 1 {
+		t.Errorf("expected one handler call, got - %v", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("expected the stored response %v %q, got - %v %q", first.Code, first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected %v header on the replay", IdempotentReplayedHeader)
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("expected Content-Type %q, got - %q", first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	}
+}
+
+// Тест - тот же ключ с другим телом отклоняется 422
+func TestIdempotencyKeyReused(t *testing.T) {
+	// preparing
+	calls := 0
+	router := newIdempotencyRouter(&MockIdempotencyStore{records: map[string]domain.IdempotencyRecord{}}, &calls, "")
+	router.ServeHTTP(httptest.NewRecorder(), idempotentRequest("key-1", `{"mood":7}`))
+
+	// test
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, idempotentRequest("key-1", `{"mood":3}`))
+
+	// assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got - %v", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("expected one handler call, got - %v", calls)
+	}
+}
+
+// Тест - тот же ключ и тело с другим If-Match отклоняется 422
+func TestIdempotencyKeyReusedIfMatch(t *testing.T) {
+	// preparing
+	calls := 0
+	router := newIdempotencyRouter(&MockIdempotencyStore{records: map[string]domain.IdempotencyRecord{}}, &calls, "")
+	first := idempotentRequest("key-1", `{"mood":7}`)
+	first.Header.Set("If-Match", `"1"`)
+	router.ServeHTTP(httptest.NewRecorder(), first)
+
+	// test
+	second := idempotentRequest("key-1", `{"mood":7}`)
+	second.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, second)
+
+	// assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got - %v", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("expected one handler call, got - %v", calls)
+	}
+}
+
+// Тест - ответ с Cache-Control: no-store не сохраняется, повтор выполняется заново
+func TestIdempotencyNoStore(t *testing.T) {
+	// preparing
+	calls := 0
+	store := &MockIdempotencyStore{records: map[string]domain.IdempotencyRecord{}}
+	router := newIdempotencyRouter(store, &calls, "no-store")
+	router.ServeHTTP(httptest.NewRecorder(), idempotentRequest("key-1", `{"name":"ci"}`))
+
+	// test
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, idempotentRequest("key-1", `{"name":"ci"}`))
+
+	// assert
+	if calls != 2 {
+		t.Errorf("expected two handler calls, got - %v", calls)
+	}
+	if w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("no-store response must not be replayed")
+	}
+	if len(store.records) != 0 {
+		t.Errorf("expected no stored records, got - %v", len(store.records))
+	}
+}
+
+// Тест - некорректный ключ отклоняется 400 до хендлера
+func TestIdempotencyInvalidKey(t *testing.T) {
+	// preparing
+	calls := 0
+	router := newIdempotencyRouter(&MockIdempotencyStore{records: map[string]domain.IdempotencyRecord{}}, &calls, "")
+
+	// test
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, idempotentRequest("key with spaces", `{}`))
+
+	// assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got - %v", w.Code)
+	}
+	if calls != 0 {
+		t.Errorf("expected no handler calls, got - %v", calls)
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"chopper/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageMiddleware выбирает язык ответа по Accept-Language; сохраненную настройку пользователя
+// поверх него применяет AuthMiddleware, когда пользователь уже известен
+type LanguageMiddleware struct {
+	defaultLanguage string
+}
+
+func NewLanguageMiddleware(defaultLanguage string) *LanguageMiddleware {
+	return &LanguageMiddleware{
+		defaultLanguage: defaultLanguage,
+	}
+}
+
+func (l *LanguageMiddleware) Negotiate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// ответы зависят от заголовка, кэши должны это учитывать
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		setLanguage(c, i18n.Negotiate(c.GetHeader("Accept-Language"), l.defaultLanguage))
+		c.Next()
+	}
+}
+
+func setLanguage(c *gin.Context, language string) {
+	c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), language))
+	c.Header("Content-Language", language)
+}
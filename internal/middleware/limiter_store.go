@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+)
+
+// LimiterStore - хранилище корзин. В памяти - для одного экземпляра,
+// в Postgres - когда несколько реплик должны соблюдать один общий лимит
+type LimiterStore interface {
+	Take(ctx context.Context, key string, policy domain.RateLimitPolicy, now time.Time) (domain.RateLimitResult, error)
+	EvictIdle(ctx context.Context, idleTimeout time.Duration) error
+}
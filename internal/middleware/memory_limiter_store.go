@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"hash/maphash"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// количество шардов - степень двойки, чтобы индекс считался маской
+const shardsCount = 64
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64 // unix nano последнего запроса
+}
+
+type limiterShard struct {
+	mu      sync.RWMutex
+	entries map[string]*limiterEntry
+}
+
+type MemoryLimiterStore struct {
+	shards [shardsCount]limiterShard // карты лимитеров, разбитые по шардам
+	seed   maphash.Seed              // сид для выбора шарда
+}
+
+func NewMemoryLimiterStore() *MemoryLimiterStore {
+	store := &MemoryLimiterStore{
+		seed: maphash.MakeSeed(),
+	}
+	for i := range store.shards {
+		store.shards[i].entries = make(map[string]*limiterEntry)
+	}
+	return store
+}
+
+func (m *MemoryLimiterStore) Take(ctx context.Context, key string, policy domain.RateLimitPolicy, now time.Time) (domain.RateLimitResult, error) {
+	limiter := m.getRateLimiter(key, policy, now)
+	// политика могла смениться при перезагрузке конфига - существующие корзины подстраиваются под нее
+	if limit := rate.Every(policy.Rate); limiter.Limit() != limit {
+		limiter.SetLimitAt(now, limit)
+	}
+	if limiter.Burst() != policy.Burst {
+		limiter.SetBurstAt(now, policy.Burst)
+	}
+	allowed := limiter.AllowN(now, 1)
+	tokens := limiter.TokensAt(now)
+	result := domain.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     policy.Burst,
+		Remaining: max(int(math.Floor(tokens)), 0),
+		Reset:     time.Duration((float64(policy.Burst) - tokens) * float64(policy.Rate)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(policy.Rate))
+	}
+	return result, nil
+}
+
+// EvictIdle удаляет лимитеры, к которым не обращались дольше idleTimeout. RateLimiter передает простой не меньше
+// времени пополнения корзины, поэтому удаляются только полные
+func (m *MemoryLimiterStore) EvictIdle(ctx context.Context, idleTimeout time.Duration) error {
+	m.evictIdle(time.Now().Add(-idleTimeout))
+	return nil
+}
+
+// Len - количество лимитеров в памяти
+func (m *MemoryLimiterStore) Len() int {
+	total := 0
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.mu.RLock()
+		total += len(shard.entries)
+		shard.mu.RUnlock()
+	}
+	return total
+}
+
+func (m *MemoryLimiterStore) getRateLimiter(key string, policy domain.RateLimitPolicy, now time.Time) *rate.Limiter {
+	shard := m.shard(key)
+	// быстрый путь - лимитер уже есть, нужна только блокировка на чтение
+	shard.mu.RLock()
+	entry, ok := shard.entries[key]
+	shard.mu.RUnlock()
+	if ok {
+		entry.lastSeen.Store(now.UnixNano())
+		return entry.limiter
+	}
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	entry, ok = shard.entries[key]
+	if !ok {
+		entry = &limiterEntry{
+			limiter: rate.NewLimiter(rate.Every(policy.Rate), policy.Burst),
+		}
+		shard.entries[key] = entry
+	}
+	entry.lastSeen.Store(now.UnixNano())
+	return entry.limiter
+}
+
+func (m *MemoryLimiterStore) shard(key string) *limiterShard {
+	return &m.shards[maphash.String(m.seed, key)&(shardsCount-1)]
+}
+
+// evictIdle удаляет лимитеры, к которым не обращались с deadline.
+// Шарды чистятся по одному, поэтому запросы к остальным шардам не ждут
+func (m *MemoryLimiterStore) evictIdle(deadline time.Time) {
+	deadlineNano := deadline.UnixNano()
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.mu.Lock()
+		for key, entry := range shard.entries {
+			if entry.lastSeen.Load() < deadlineNano {
+				delete(shard.entries, key)
+			}
+		}
+		shard.mu.Unlock()
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPMetrics - хуки HTTP метрик, реализуются пакетом metrics
+type HTTPMetrics interface {
+	ObserveRequest(route, method string, status int, duration time.Duration)
+	RateLimitRejected(policy string)
+}
+
+type MetricsMiddleware struct {
+	metrics HTTPMetrics
+}
+
+func NewMetricsMiddleware(metrics HTTPMetrics) *MetricsMiddleware {
+	return &MetricsMiddleware{
+		metrics: metrics,
+	}
+}
+
+func (m *MetricsMiddleware) Observe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		// шаблон роута, а не путь, чтобы id в путях не раздували число серий
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.metrics.ObserveRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -1,131 +1,168 @@
 package middleware
 
 import (
-	"net/http"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/problem"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type RateLimiter struct {
 	// различные параметры
-	ips   map[string]*rate.Limiter // карта
-	mu    sync.Mutex               // мутекс для карты
-	r     rate.Limit               // объект лимитера
-	burst int                      // поток обновления
+	policies    atomic.Pointer[map[string]domain.RateLimitPolicy] // именованные политики, меняются при перезагрузке конфига
+	store       LimiterStore                                      // где лежат корзины
+	idleTimeout time.Duration                                     // через сколько простоя корзина удаляется
+	metrics     HTTPMetrics                                       // может быть nil
+	stop        chan struct{}
+	done        chan struct{}
+	stopOnce    sync.Once
 }
 
-func NewRateLimiter(r rate.Limit, burst int) *RateLimiter {
-	return &RateLimiter{
-		ips:   make(map[string]*rate.Limiter),
-		mu:    sync.Mutex{},
-		r:     r,
-		burst: burst,
+func NewRateLimiter(policies map[string]domain.RateLimitPolicy, store LimiterStore, idleTimeout time.Duration, metrics HTTPMetrics) *RateLimiter {
+	rateLimiter := &RateLimiter{
+		store:       store,
+		idleTimeout: idleTimeout,
+		metrics:     metrics,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
 	}
+	rateLimiter.policies.Store(&policies)
+	go rateLimiter.janitor()
+	return rateLimiter
+}
+
+// SetPolicies атомарно подменяет политики, следующие запросы идут уже по новым лимитам
+func (r *RateLimiter) SetPolicies(policies map[string]domain.RateLimitPolicy) {
+	r.policies.Store(&policies)
 }
 
-func (r *RateLimiter) RateLimit() gin.HandlerFunc {
+// LimitFunc списывает токен для клиента с адресом ip и пользователем userId (uuid.Nil - без аутентификации)
+type LimitFunc func(ctx context.Context, ip string, userId uuid.UUID) (domain.RateLimitResult, error)
+
+// RateLimit ограничивает запросы по политике с именем policyName.
+// Неизвестное имя - ошибка конфигурации, поэтому паникует при сборке роутов, а не на запросе
+func (r *RateLimiter) RateLimit(policyName string) gin.HandlerFunc {
+	limit := r.Limit(policyName)
 	return func(ctx *gin.Context) {
-		ip := ctx.ClientIP()
-		limiter := r.getRateLimiter(ip)
-		if !limiter.Allow() {
-			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "too many requests",
-			})
+		result, err := limit(ctx.Request.Context(), ctx.ClientIP(), contextUserId(ctx))
+		if err != nil {
+			// хранилище недоступно - пропускаем запрос, чтобы лимитер не положил весь сервис
+			_ = ctx.Error(err)
+			ctx.Next()
+			return
+		}
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("X-RateLimit-Reset", strconv.Itoa(CeilSeconds(result.Reset)))
+		if !result.Allowed {
+			retryAfter := CeilSeconds(result.RetryAfter)
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			problem.Abort(ctx, problem.TooManyRequests, i18n.Msg("detail.retry_in", "seconds", retryAfter))
 			return
 		}
 		ctx.Next()
 	}
 }
 
-func (r *RateLimiter) getRateLimiter(ip string) *rate.Limiter {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	limiter, ok := r.ips[ip]
+// Limit - проверка по политике policyName без привязки к транспорту, ее используют HTTP middleware и gRPC интерцептор.
+// Неизвестное имя паникует сразу, как и в RateLimit
+func (r *RateLimiter) Limit(policyName string) LimitFunc {
+	initialPolicy, ok := (*r.policies.Load())[policyName]
 	if !ok {
-		rateLimiter := r.newRateLimiter()
-		r.ips[ip] = rateLimiter
-		return rateLimiter
+		panic(fmt.Sprintf("unknown rate limit policy %q", policyName))
+	}
+	return func(ctx context.Context, ip string, userId uuid.UUID) (domain.RateLimitResult, error) {
+		// если политику убрали из конфига на лету, роут продолжает работать по последней известной
+		policy, ok := (*r.policies.Load())[policyName]
+		if !ok {
+			policy = initialPolicy
+		}
+		key := policy.Name + "|" + clientKey(policy.Key, ip, userId)
+		spanCtx, span := otel.Tracer(tracerName).Start(ctx, "RateLimiter.Take",
+			trace.WithAttributes(attribute.String("rate_limit.policy", policy.Name)),
+		)
+		defer span.End()
+		result, err := r.store.Take(spanCtx, key, policy, time.Now())
+		span.SetAttributes(attribute.Bool("rate_limit.allowed", err != nil || result.Allowed))
+		if err != nil {
+			span.RecordError(err)
+			return domain.RateLimitResult{}, err
+		}
+		if !result.Allowed && r.metrics != nil {
+			r.metrics.RateLimitRejected(policy.Name)
+		}
+		return result, nil
 	}
-	return limiter
-}
-
-func (r *RateLimiter) newRateLimiter() *rate.Limiter {
-	return rate.NewLimiter(r.r, r.burst)
 }
 
-/*package middleware
-
-import (
-	"github.com/gin-gonic/gin"
-	"net/http"
-	"sync"
-	"time"
-)
-
-type RateLimiter struct {
-	mu         sync.Mutex
-	buckets    map[string]int
-	refillRate time.Duration
-	capacity   int
+// Stop останавливает janitor, вызывается при graceful shutdown
+func (r *RateLimiter) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
 }
 
-func NewRateLimiter(refillRate time.Duration, capacity int) *RateLimiter {
-	return &RateLimiter{
-		mu:         sync.Mutex{},
-		buckets:    make(map[string]int),
-		refillRate: refillRate,
-		capacity:   capacity,
+// clientKey - ключ корзины; для пользовательских политик без аутентификации используется ip
+func clientKey(key domain.RateLimitKey, ip string, userId uuid.UUID) string {
+	if key == domain.RateLimitKeyIP || userId == uuid.Nil {
+		return "ip:" + ip
+	}
+	if key == domain.RateLimitKeyUserIP {
+		return "user:" + userId.String() + "|ip:" + ip
 	}
+	return "user:" + userId.String()
 }
 
-func (r *RateLimiter) Start() chan<- struct{} {
-	stop := make(chan struct{})
-	ticker := time.NewTicker(r.refillRate)
-	go func() {
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				r.mu.Lock()
-				for ip := range r.buckets {
-					r.buckets[ip] = r.capacity
-				}
-				r.mu.Unlock()
-			case <-stop:
-				return
-			}
-		}
-	}()
-	return stop
+// contextUserId - пользователь, которого положил AuthMiddleware, uuid.Nil без аутентификации
+func contextUserId(ctx *gin.Context) uuid.UUID {
+	uid, _ := ctx.Get("user_id")
+	userId, _ := uid.(uuid.UUID)
+	return userId
 }
 
-func (r *RateLimiter) RateLimit() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		ip := ctx.ClientIP()
-		if !r.isAllow(ip) {
-			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "too manu requests",
-			})
+func (r *RateLimiter) janitor() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.idleTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.idleTimeout)
+			_ = r.store.EvictIdle(ctx, r.evictAfter())
+			cancel()
+		case <-r.stop:
 			return
 		}
-		ctx.Next()
 	}
 }
 
-func (r *RateLimiter) isAllow(ip string) bool {
-	defer r.mu.Unlock()
-	r.mu.Lock()
-	tokens, ok := r.buckets[ip]
-	if !ok {
-		r.buckets[ip] = r.capacity
-		return true
+// evictAfter - простой, после которого корзину можно удалить: не меньше idleTimeout и не меньше времени,
+// за которое пополняется самая медленная корзина. Раньше удалять нельзя - следующий запрос получил бы полный burst
+func (r *RateLimiter) evictAfter() time.Duration {
+	evictAfter := r.idleTimeout
+	for _, policy := range *r.policies.Load() {
+		evictAfter = max(evictAfter, policy.Rate*time.Duration(policy.Burst))
 	}
-	if tokens < 1 {
-		return false
+	return evictAfter
+}
+
+// CeilSeconds - секунды для Retry-After, округленные вверх
+func CeilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
 	}
-	tokens--
-	r.buckets[ip] = tokens
-	return true
-}*/
+	return int(math.Ceil(d.Seconds()))
+}
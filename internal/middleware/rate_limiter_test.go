@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+var testPolicy = domain.RateLimitPolicy{Name: "test", Rate: time.Second, Burst: 2, Key: domain.RateLimitKeyIP}
+
+func newTestRateLimiter(policies ...domain.RateLimitPolicy) *RateLimiter {
+	policiesMap := map[string]domain.RateLimitPolicy{testPolicy.Name: testPolicy}
+	for _, policy := range policies {
+		policiesMap[policy.Name] = policy
+	}
+	return NewRateLimiter(policiesMap, NewMemoryLimiterStore(), time.Minute, nil)
+}
+
+// Тест EvictIdle - простаивающие лимитеры удаляются, активные остаются
+func TestMemoryLimiterStoreEvictIdle(t *testing.T) {
+	// preparing
+	store := NewMemoryLimiterStore()
+	store.getRateLimiter("10.0.0.1", testPolicy, time.Now().Add(-2*time.Minute))
+	store.getRateLimiter("10.0.0.2", testPolicy, time.Now())
+
+	// test
+	err := store.EvictIdle(context.Background(), time.Minute)
+
+	// assert
+	if err != nil {
+		t.Errorf("error was not expected - %v", err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected one limiter after eviction, got - %v", store.Len())
+	}
+	if _, ok := store.shard("10.0.0.2").entries["10.0.0.2"]; !ok {
+		t.Errorf("active limiter was evicted")
+	}
+}
+
+// Тест evictAfter - корзину медленной политики нельзя удалять, пока она не пополнилась
+func TestRateLimiterEvictAfter(t *testing.T) {
+	// preparing
+	rateLimiter := newTestRateLimiter(domain.RateLimitPolicy{Name: "export", Rate: 5 * time.Minute, Burst: 5, Key: domain.RateLimitKeyUser})
+	defer rateLimiter.Stop()
+
+	// test
+	evictAfter := rateLimiter.evictAfter()
+
+	// assert
+	if evictAfter != 25*time.Minute {
+		t.Errorf("expected eviction after - %v, got - %v", 25*time.Minute, evictAfter)
+	}
+}
+
+// Тест getRateLimiter - один и тот же ключ получает один и тот же лимитер
+func TestMemoryLimiterStoreSameLimiter(t *testing.T) {
+	// preparing
+	store := NewMemoryLimiterStore()
+
+	// test
+	first := store.getRateLimiter("10.0.0.1", testPolicy, time.Now())
+	second := store.getRateLimiter("10.0.0.1", testPolicy, time.Now())
+
+	// assert
+	if first != second {
+		t.Errorf("expected the same limiter for the same ip")
+	}
+}
+
+// Тест Stop - повторный вызов не паникует
+func TestRateLimiterStopTwice(t *testing.T) {
+	rateLimiter := newTestRateLimiter()
+	rateLimiter.Stop()
+	rateLimiter.Stop()
+}
+
+func newTestRouter(rateLimiter *RateLimiter, policyName string, userId *uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if userId != nil {
+		r.Use(func(c *gin.Context) {
+			c.Set("user_id", *userId)
+		})
+	}
+	r.Use(rateLimiter.RateLimit(policyName))
+	r.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func doTestRequest(r *gin.Engine, ip string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = ip + ":1234"
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// Тест RateLimit - заголовки X-RateLimit-* и Retry-After после исчерпания корзины
+func TestRateLimitHeaders(t *testing.T) {
+	// preparing
+	rateLimiter := newTestRateLimiter()
+	defer rateLimiter.Stop()
+	r := newTestRouter(rateLimiter, testPolicy.Name, nil)
+
+	// test
+	first := doTestRequest(r, "10.0.0.1")
+	doTestRequest(r, "10.0.0.1")
+	third := doTestRequest(r, "10.0.0.1")
+
+	// assert
+	if first.Code != http.StatusOK {
+		t.Errorf("expected status was - %v", http.StatusOK)
+	}
+	if first.Header().Get("X-RateLimit-Limit") != "2" || first.Header().Get("X-RateLimit-Remaining") != "1" {
+		t.Errorf("wrong rate limit headers - %v", first.Header())
+	}
+	if third.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status was - %v", http.StatusTooManyRequests)
+	}
+	if third.Header().Get("Retry-After") != "1" || third.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("wrong rate limit headers - %v", third.Header())
+	}
+}
+
+// Тест RateLimit - пользовательская политика не делит корзину между пользователями за одним ip
+func TestRateLimitUserKey(t *testing.T) {
+	// preparing
+	userPolicy := domain.RateLimitPolicy{Name: "per-user", Rate: time.Minute, Burst: 1, Key: domain.RateLimitKeyUser}
+	rateLimiter := newTestRateLimiter(userPolicy)
+	defer rateLimiter.Stop()
+	firstUser, secondUser := uuid.MustParse("11111111-1111-1111-1111-111111111111"), uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	firstRouter := newTestRouter(rateLimiter, userPolicy.Name, &firstUser)
+	secondRouter := newTestRouter(rateLimiter, userPolicy.Name, &secondUser)
+
+	// test
+	doTestRequest(firstRouter, "10.0.0.1")
+	blocked := doTestRequest(firstRouter, "10.0.0.1")
+	other := doTestRequest(secondRouter, "10.0.0.1")
+
+	// assert
+	if blocked.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status was - %v", http.StatusTooManyRequests)
+	}
+	if other.Code != http.StatusOK {
+		t.Errorf("expected status for another user was - %v", http.StatusOK)
+	}
+}
+
+// мок хранилища, которое всегда падает
+type failingLimiterStore struct {
+}
+
+func (f *failingLimiterStore) Take(ctx context.Context, key string, policy domain.RateLimitPolicy, now time.Time) (domain.RateLimitResult, error) {
+	return domain.RateLimitResult{}, errors.New("database is down")
+}
+
+func (f *failingLimiterStore) EvictIdle(ctx context.Context, idleTimeout time.Duration) error {
+	return nil
+}
+
+// Тест RateLimit - при недоступном хранилище запросы пропускаются
+func TestRateLimitStoreFailureFailsOpen(t *testing.T) {
+	// preparing
+	rateLimiter := NewRateLimiter(map[string]domain.RateLimitPolicy{testPolicy.Name: testPolicy}, &failingLimiterStore{}, time.Minute, nil)
+	defer rateLimiter.Stop()
+	r := newTestRouter(rateLimiter, testPolicy.Name, nil)
+
+	// test
+	w := doTestRequest(r, "10.0.0.1")
+
+	// assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status was - %v", http.StatusOK)
+	}
+}
+
+// прежняя реализация - одна карта под одним мутексом, для сравнения в бенчмарках
+type singleMutexRateLimiter struct {
+	ips   map[string]*rate.Limiter
+	mu    sync.Mutex
+	r     rate.Limit
+	burst int
+}
+
+func (r *singleMutexRateLimiter) getRateLimiter(ip string) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limiter, ok := r.ips[ip]
+	if !ok {
+		limiter = rate.NewLimiter(r.r, r.burst)
+		r.ips[ip] = limiter
+	}
+	return limiter
+}
+
+func benchmarkIps(n int) []string {
+	ips := make([]string, n)
+	for i := range ips {
+		ips[i] = fmt.Sprintf("10.%d.%d.%d", i>>16&0xff, i>>8&0xff, i&0xff)
+	}
+	return ips
+}
+
+// go test ./internal/middleware -bench RateLimiter -cpu 1,8,32
+func BenchmarkRateLimiterSingleMutex(b *testing.B) {
+	ips := benchmarkIps(4096)
+	rateLimiter := &singleMutexRateLimiter{ips: make(map[string]*rate.Limiter), r: rate.Inf, burst: 1}
+	var worker atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		// у каждой горутины свой счетчик, чтобы сам бенчмарк не добавлял общей точки конкуренции
+		i := worker.Add(1) * 997
+		for pb.Next() {
+			i++
+			rateLimiter.getRateLimiter(ips[i%uint64(len(ips))]).Allow()
+		}
+	})
+}
+
+func BenchmarkRateLimiterSharded(b *testing.B) {
+	ips := benchmarkIps(4096)
+	store := NewMemoryLimiterStore()
+	var worker atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		// у каждой горутины свой счетчик, чтобы сам бенчмарк не добавлял общей точки конкуренции
+		i := worker.Add(1) * 997
+		for pb.Next() {
+			i++
+			store.getRateLimiter(ips[i%uint64(len(ips))], testPolicy, time.Now()).Allow()
+		}
+	})
+}
+
+// Тест SetPolicies - новые лимиты применяются и к уже существующим корзинам
+func TestRateLimiterSetPolicies(t *testing.T) {
+	// preparing
+	rateLimiter := newTestRateLimiter()
+	defer rateLimiter.Stop()
+	r := newTestRouter(rateLimiter, testPolicy.Name, nil)
+	doTestRequest(r, "10.0.0.1")
+
+	// test
+	rateLimiter.SetPolicies(map[string]domain.RateLimitPolicy{
+		testPolicy.Name: {Name: testPolicy.Name, Rate: time.Second, Burst: 10, Key: domain.RateLimitKeyIP},
+	})
+	w := doTestRequest(r, "10.0.0.1")
+
+	// assert
+	if w.Header().Get("X-RateLimit-Limit") != "10" {
+		t.Errorf("expected limit after reload was - %v, got - %v", 10, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
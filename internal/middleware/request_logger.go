@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"chopper/internal/problem"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// входящий X-Request-ID принимается только в безопасном виде, иначе генерируется новый
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+type RequestLogger struct {
+	log *logrus.Logger
+}
+
+func NewRequestLogger(log *logrus.Logger) *RequestLogger {
+	return &RequestLogger{
+		log: log,
+	}
+}
+
+// Log назначает или пробрасывает X-Request-ID, кладет логгер запроса в контекст и пишет access лог
+func (r *RequestLogger) Log() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		fields := logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+		}
+		// trace_id связывает строку лога с трейсом
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			fields["trace_id"] = spanContext.TraceID().String()
+			fields["span_id"] = spanContext.SpanID().String()
+		}
+		entry := r.log.WithFields(fields)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), entry))
+
+		c.Next()
+
+		fields = logrus.Fields{
+			"route":      c.FullPath(),
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+			"size":       c.Writer.Size(),
+		}
+		if userId, ok := c.Get("user_id"); ok {
+			fields["user_id"] = userId
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
+		}
+		accessEntry := entry.WithFields(fields)
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			accessEntry.Error("request completed")
+		case status >= http.StatusBadRequest:
+			accessEntry.Warn("request completed")
+		default:
+			accessEntry.Info("request completed")
+		}
+	}
+}
+
+// Recovery заменяет gin.Recovery, чтобы паники попадали в тот же JSON лог вместе с request_id
+func (r *RequestLogger) Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+					"panic": rec,
+					"stack": string(debug.Stack()),
+				}).Error("panic recovered")
+				problem.Abort(c, problem.InternalError, i18n.Message{})
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"chopper/internal/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func newTestLoggerRouter(log *logrus.Logger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	requestLogger := NewRequestLogger(log)
+	r := gin.New()
+	r.Use(requestLogger.Log())
+	r.Use(requestLogger.Recovery())
+	r.GET("/", func(c *gin.Context) {
+		logger.FromContext(c.Request.Context()).Info("from handler")
+		c.Status(http.StatusOK)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return r
+}
+
+// Тест Log - входящий X-Request-ID пробрасывается в ответ и в логгер запроса
+func TestRequestLoggerPropagatesRequestID(t *testing.T) {
+	// preparing
+	log, hook := test.NewNullLogger()
+	r := newTestLoggerRouter(log)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+
+	// test
+	r.ServeHTTP(w, req)
+
+	// assert
+	if w.Header().Get(RequestIDHeader) != "abc-123" {
+		t.Errorf("expected request id was - %v", "abc-123")
+	}
+	if len(hook.Entries) != 2 {
+		t.Fatalf("expected handler and access log entries, got - %v", len(hook.Entries))
+	}
+	for _, entry := range hook.Entries {
+		if entry.Data["request_id"] != "abc-123" {
+			t.Errorf("log entry without request id - %v", entry.Message)
+		}
+	}
+}
+
+// Тест Log - небезопасный X-Request-ID заменяется сгенерированным
+func TestRequestLoggerReplacesInvalidRequestID(t *testing.T) {
+	// preparing
+	log, _ := test.NewNullLogger()
+	r := newTestLoggerRouter(log)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+
+	// test
+	r.ServeHTTP(w, req)
+
+	// assert
+	requestID := w.Header().Get(RequestIDHeader)
+	if requestID == "" || requestID == "bad id\nwith newline" {
+		t.Errorf("expected generated request id, got - %q", requestID)
+	}
+}
+
+// Тест Recovery - паника превращается в 500 и пишется в лог
+func TestRequestLoggerRecovery(t *testing.T) {
+	// preparing
+	log, hook := test.NewNullLogger()
+	r := newTestLoggerRouter(log)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+
+	// test
+	r.ServeHTTP(w, req)
+
+	// assert
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status was - %v", http.StatusInternalServerError)
+	}
+	found := false
+	for _, entry := range hook.Entries {
+		if entry.Message == "panic recovered" && entry.Level == logrus.ErrorLevel {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("panic was not logged")
+	}
+}
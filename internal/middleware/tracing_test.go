@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setTestTracerProvider подменяет глобальный провайдер на провайдер с in-memory экспортером
+func setTestTracerProvider(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+// Тест Trace - серверный спан продолжает входящий traceparent, а спан лимитера становится дочерним
+func TestTracingContinuesIncomingTrace(t *testing.T) {
+	// preparing
+	exporter := setTestTracerProvider(t)
+	rateLimiter := newTestRateLimiter()
+	defer rateLimiter.Stop()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(NewTracingMiddleware().Trace())
+	r.Use(rateLimiter.RateLimit(testPolicy.Name))
+	r.GET("/notes/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/notes/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// test
+	r.ServeHTTP(w, req)
+
+	// assert
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected rate limiter and server spans, got - %v", len(spans))
+	}
+	limiterSpan, serverSpan := spans[0], spans[1]
+	if serverSpan.Name != "GET /notes/:id" || serverSpan.SpanKind != trace.SpanKindServer {
+		t.Errorf("wrong server span - %v", serverSpan.Name)
+	}
+	if serverSpan.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("incoming trace id was not continued")
+	}
+	if limiterSpan.Name != "RateLimiter.Take" || limiterSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+		t.Errorf("rate limiter span is not a child of the server span")
+	}
+}
+
+// Тест Log - trace_id попадает в строки лога запроса
+func TestRequestLoggerAddsTraceID(t *testing.T) {
+	// preparing
+	setTestTracerProvider(t)
+	log, hook := test.NewNullLogger()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(NewTracingMiddleware().Trace())
+	r.Use(NewRequestLogger(log).Log())
+	r.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// test
+	r.ServeHTTP(w, req)
+
+	// assert
+	entry := hook.LastEntry()
+	if entry == nil || entry.Data["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace id in access log")
+	}
+}
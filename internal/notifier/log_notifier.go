@@ -0,0 +1,41 @@
+// Package notifier - способы доставки напоминаний, выбираются настройкой reminders.notifier
+package notifier
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier пишет напоминание в лог - для разработки и как заглушка, пока нет канала доставки
+type LogNotifier struct {
+	defaultLanguage string
+}
+
+func NewLogNotifier(defaultLanguage string) *LogNotifier {
+	return &LogNotifier{
+		defaultLanguage: defaultLanguage,
+	}
+}
+
+func (l *LogNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"user_id":  reminder.UserId,
+		"username": reminder.Username,
+		"date":     reminder.Date.Format("2006-01-02"),
+		"text":     reminderText(reminder, l.defaultLanguage),
+	}).Info("reminder")
+	return nil
+}
+
+// reminderText - текст на языке пользователя, без сохраненного языка - на языке по умолчанию
+func reminderText(reminder domain.Reminder, defaultLanguage string) string {
+	lang := reminder.Language
+	if lang == "" {
+		lang = defaultLanguage
+	}
+	return i18n.Msg("reminder.text", "date", reminder.Date.Format("2006-01-02")).In(lang)
+}
@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"bytes"
+	"chopper/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// webhookReminder - тело запроса вебхука, доставку пользователю (почта, push) делает получатель
+type webhookReminder struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Language string    `json:"language"`
+	Date     string    `json:"date"`
+	Attempt  int       `json:"attempt"`
+	Text     string    `json:"text"`
+}
+
+// WebhookNotifier отправляет напоминание POST запросом; любой ответ кроме 2xx - неудачная доставка
+type WebhookNotifier struct {
+	url             string
+	client          *http.Client
+	defaultLanguage string
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration, defaultLanguage string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:             url,
+		client:          &http.Client{Timeout: timeout},
+		defaultLanguage: defaultLanguage,
+	}
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	lang := reminder.Language
+	if lang == "" {
+		lang = w.defaultLanguage
+	}
+	body, err := json.Marshal(webhookReminder{
+		UserId:   reminder.UserId,
+		Username: reminder.Username,
+		Email:    reminder.Email,
+		Language: lang,
+		Date:     reminder.Date.Format("2006-01-02"),
+		Attempt:  reminder.Attempt,
+		Text:     reminderText(reminder, w.defaultLanguage),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// повторная попытка за тот же день приходит с тем же ключом, получатель может отбросить дубль
+	req.Header.Set("Idempotency-Key", fmt.Sprintf("reminder:%v:%v", reminder.UserId, reminder.Date.Format("2006-01-02")))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Тест - вебхук получает текст на языке по умолчанию, если у пользователя язык не сохранен
+func TestWebhookNotify(t *testing.T) {
+	// preparing
+	var received webhookReminder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("тело не разобрано - %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	notifier := NewWebhookNotifier(server.URL, time.Second, "en")
+	reminder := domain.Reminder{UserId: uuid.New(), Username: "chopper", Date: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), Attempt: 1}
+
+	// test
+	err := notifier.Notify(context.Background(), reminder)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if received.UserId != reminder.UserId || received.Date != "2026-10-19" || received.Language != "en" {
+		t.Errorf("получено неверное тело - %+v", received)
+	}
+	if expected := "You haven't logged today yet: add mood, sleep and load for 2026-10-19"; received.Text != expected {
+		t.Errorf("ожидался текст - %q, получен - %q", expected, received.Text)
+	}
+}
+
+// Тест - ответ не 2xx считается неудачной доставкой
+func TestWebhookNotifyFailure(t *testing.T) {
+	// preparing
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	notifier := NewWebhookNotifier(server.URL, time.Second, "ru")
+
+	// test
+	err := notifier.Notify(context.Background(), domain.Reminder{UserId: uuid.New()})
+
+	// assert
+	if err == nil {
+		t.Errorf("ожидалась ошибка доставки")
+	}
+}
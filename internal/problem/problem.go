@@ -0,0 +1,90 @@
+// Package problem - ответы с ошибками в формате RFC 7807 (application/problem+json)
+package problem
+
+import (
+	"chopper/internal/i18n"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+// typeBase - префикс type URI; относительная ссылка допустима по RFC 7807 и не привязана к домену
+const typeBase = "/problems/"
+
+// Type - стабильный код ошибки, на который клиенты могут опираться вместо текста.
+// Заголовок берется из каталога i18n по ключу problem.<code>
+type Type struct {
+	Code   string
+	Status int
+}
+
+func (t Type) URI() string {
+	return typeBase + t.Code
+}
+
+var (
+	InvalidBody           = Type{"invalid-request-body", http.StatusBadRequest}
+	ValidationFailed      = Type{"validation-failed", http.StatusBadRequest}
+	Unauthorized          = Type{"unauthorized", http.StatusUnauthorized}
+	InvalidCredentials    = Type{"invalid-credentials", http.StatusUnauthorized}
+	UserSuspended         = Type{"user-suspended", http.StatusForbidden}
+	InsufficientScope     = Type{"insufficient-scope", http.StatusForbidden}
+	SessionRequired       = Type{"session-required", http.StatusForbidden}
+	NotFound              = Type{"not-found", http.StatusNotFound}
+	MethodNotAllowed      = Type{"method-not-allowed", http.StatusMethodNotAllowed}
+	UserExists            = Type{"user-already-exists", http.StatusConflict}
+	NoteExists            = Type{"note-already-exists", http.StatusConflict}
+	NoteNotFound          = Type{"note-not-found", http.StatusNotFound}
+	NoteVersionNotFound   = Type{"note-version-not-found", http.StatusNotFound}
+	PreconditionFailed    = Type{"precondition-failed", http.StatusPreconditionFailed}
+	PreconditionRequired  = Type{"precondition-required", http.StatusPreconditionRequired}
+	ApiTokenExists        = Type{"api-token-already-exists", http.StatusConflict}
+	ApiTokenNotFound      = Type{"api-token-not-found", http.StatusNotFound}
+	TooManyRequests       = Type{"too-many-requests", http.StatusTooManyRequests}
+	InvalidIdempotencyKey = Type{"invalid-idempotency-key", http.StatusBadRequest}
+	IdempotencyKeyReused  = Type{"idempotency-key-reused", http.StatusUnprocessableEntity}
+	IdempotencyInProgress = Type{"idempotency-request-in-progress", http.StatusConflict}
+	ServiceUnavailable    = Type{"service-unavailable", http.StatusServiceUnavailable}
+	InternalError         = Type{"internal-error", http.StatusInternalServerError}
+)
+
+// FieldError - ошибка конкретного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+func (t Type) Title(lang string) string {
+	return i18n.Msg("problem." + t.Code).In(lang)
+}
+
+// Abort прерывает цепочку и отвечает problem+json на языке запроса; request_id берется из RequestLogger
+func Abort(c *gin.Context, problemType Type, detail i18n.Message, fieldErrors ...FieldError) {
+	lang := i18n.Language(c.Request.Context())
+	p := Problem{
+		Type:     problemType.URI(),
+		Title:    problemType.Title(lang),
+		Status:   problemType.Status,
+		Detail:   detail.In(lang),
+		Instance: c.Request.URL.Path,
+		Errors:   fieldErrors,
+	}
+	if requestID, ok := c.Get("request_id"); ok {
+		p.RequestID, _ = requestID.(string)
+	}
+	// gin не перезаписывает уже выставленный Content-Type
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
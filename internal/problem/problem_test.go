@@ -0,0 +1,85 @@
+package problem
+
+import (
+	"chopper/internal/i18n"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Тест Abort - problem+json с type, статусом, request_id и ошибками полей
+func TestAbort(t *testing.T) {
+	// preparing
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/notes/new", nil)
+	c.Set("request_id", "req-1")
+
+	// test
+	Abort(c, ValidationFailed, i18n.Msg("detail.field", "field", "mood", "message", "must be between 0 and 10"), FieldError{Field: "mood", Message: "must be between 0 and 10"})
+
+	// assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got - %v", w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != ContentType {
+		t.Errorf("expected content type %v, got - %v", ContentType, contentType)
+	}
+	if !c.IsAborted() {
+		t.Errorf("expected aborted context")
+	}
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("unmarshal - %v", err)
+	}
+	expected := Problem{
+		Type:      "/problems/validation-failed",
+		Title:     "Validation failed",
+		Status:    http.StatusBadRequest,
+		Detail:    "mood must be between 0 and 10",
+		Instance:  "/notes/new",
+		RequestID: "req-1",
+	}
+	if p.Type != expected.Type || p.Title != expected.Title || p.Status != expected.Status || p.Detail != expected.Detail || p.Instance != expected.Instance || p.RequestID != expected.RequestID {
+		t.Errorf("expected %+v, got - %+v", expected, p)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "mood" {
+		t.Errorf("expected field error for mood, got - %+v", p.Errors)
+	}
+}
+
+// Тест Abort - заголовок и detail на языке запроса
+func TestAbortLocalized(t *testing.T) {
+	// preparing
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/alert/get", nil)
+	c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), "ru"))
+
+	// test
+	Abort(c, TooManyRequests, i18n.Msg("detail.retry_in", "seconds", 3))
+
+	// assert
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("unmarshal - %v", err)
+	}
+	if p.Title != "Слишком много запросов" || p.Detail != "повторите через 3 с" {
+		t.Errorf("expected russian title and detail, got - %+v", p)
+	}
+}
+
+// Тест - у каждого типа есть заголовок в каталоге
+func TestTypeTitles(t *testing.T) {
+	types := []Type{InvalidBody, ValidationFailed, Unauthorized, InvalidCredentials, UserSuspended, InsufficientScope, SessionRequired, NotFound, MethodNotAllowed, UserExists, NoteExists, NoteNotFound, NoteVersionNotFound, PreconditionFailed, PreconditionRequired, ApiTokenExists, ApiTokenNotFound, TooManyRequests, InvalidIdempotencyKey, IdempotencyKeyReused, IdempotencyInProgress, ServiceUnavailable, InternalError}
+	for _, typ := range types {
+		if title := typ.Title(i18n.Fallback); title == "problem."+typ.Code {
+			t.Errorf("no title for %v", typ.Code)
+		}
+	}
+}
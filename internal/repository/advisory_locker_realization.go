@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"chopper/internal/logger"
+	"context"
+	"hash/fnv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdvisoryLockerRealization - сессионные advisory блокировки Postgres: пока задача выполняется,
+// соединение с блокировкой не возвращается в пул, а при обрыве соединения блокировка снимается сама
+type AdvisoryLockerRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdvisoryLockerRealization(pool *pgxpool.Pool) *AdvisoryLockerRealization {
+	return &AdvisoryLockerRealization{
+		pool: pool,
+	}
+}
+
+// TryLock не ждет: если блокировку держит другая реплика, возвращает ok = false
+func (a *AdvisoryLockerRealization) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	key := advisoryLockKey(name)
+	conn, err := a.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		conn.Release()
+		return nil, false, err
+	}
+	if !locked {
+		conn.Release()
+		return nil, false, nil
+	}
+	unlock := func() {
+		ctx := context.WithoutCancel(ctx)
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			// соединение с неснятой блокировкой нельзя отдавать в пул
+			logger.FromContext(ctx).WithField("lock", name).WithError(err).Warn("advisory unlock failed, closing connection")
+			_ = conn.Conn().Close(ctx)
+		}
+		conn.Release()
+	}
+	return unlock, true, nil
+}
+
+// advisoryLockKey - ключ из имени задачи, одинаковый на всех репликах
+func advisoryLockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("chopper:" + name))
+	return int64(hash.Sum64())
+}
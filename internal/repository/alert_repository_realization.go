@@ -20,21 +20,21 @@ func NewAlertRepositoryRealization(pool *pgxpool.Pool) *AlertRepositoryRealizati
 
 func (a *AlertRepositoryRealization) GetLastSevenDays(ctx context.Context, userId uuid.UUID) ([]domain.Day, error) {
 	sql := "SELECT date, mood, sleep_hours, load FROM DailyEntries WHERE user_id = $1 ORDER BY date DESC LIMIT 7"
-	rows, err := a.pool.Query(ctx, sql, userId)
+	rows, err := db(ctx, a.pool).Query(ctx, sql, userId)
 	if err != nil {
-		return []domain.Day{}, err
+		return []domain.Day{}, dbError(ctx, "AlertRepository.GetLastSevenDays", err)
 	}
 	defer rows.Close()
 	days := []domain.Day{}
 	for rows.Next() {
 		var day domain.Day
 		if err := rows.Scan(&day.Date, &day.Mood, &day.SleepHours, &day.Load); err != nil {
-			return []domain.Day{}, err
+			return []domain.Day{}, dbError(ctx, "AlertRepository.GetLastSevenDays", err)
 		}
 		days = append(days, day)
 	}
 	if err := rows.Err(); err != nil {
-		return []domain.Day{}, err
+		return []domain.Day{}, dbError(ctx, "AlertRepository.GetLastSevenDays", err)
 	}
 	return days, nil
 }
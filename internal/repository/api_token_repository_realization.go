@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ApiTokenRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewApiTokenRepositoryRealization(pool *pgxpool.Pool) *ApiTokenRepositoryRealization {
+	return &ApiTokenRepositoryRealization{
+		pool: pool,
+	}
+}
+
+func (a *ApiTokenRepositoryRealization) CreateApiToken(ctx context.Context, id, userId uuid.UUID, name, tokenHash string, scopes []domain.Scope, expiresAt time.Time) error {
+	sql := "INSERT INTO ApiTokens (id, user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5, $6)"
+	_, err := db(ctx, a.pool).Exec(ctx, sql, id, userId, name, tokenHash, scopesToStrings(scopes), expiresAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return classified(ctx, "ApiTokenRepository.CreateApiToken", ErrUniqueViolation)
+		}
+		return dbError(ctx, "ApiTokenRepository.CreateApiToken", err)
+	}
+	return nil
+}
+
+func (a *ApiTokenRepositoryRealization) GetApiTokens(ctx context.Context, userId uuid.UUID) ([]domain.ApiToken, error) {
+	sql := "SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at FROM ApiTokens WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := db(ctx, a.pool).Query(ctx, sql, userId)
+	if err != nil {
+		return []domain.ApiToken{}, dbError(ctx, "ApiTokenRepository.GetApiTokens", err)
+	}
+	defer rows.Close()
+	apiTokens := []domain.ApiToken{}
+	for rows.Next() {
+		var apiToken domain.ApiToken
+		var scopes []string
+		if err := rows.Scan(&apiToken.Id, &apiToken.UserId, &apiToken.Name, &scopes, &apiToken.ExpiresAt, &apiToken.LastUsedAt, &apiToken.CreatedAt); err != nil {
+			return []domain.ApiToken{}, dbError(ctx, "ApiTokenRepository.GetApiTokens", err)
+		}
+		apiToken.Scopes = stringsToScopes(scopes)
+		apiTokens = append(apiTokens, apiToken)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.ApiToken{}, dbError(ctx, "ApiTokenRepository.GetApiTokens", err)
+	}
+	return apiTokens, nil
+}
+
+func (a *ApiTokenRepositoryRealization) DeleteApiToken(ctx context.Context, id, userId uuid.UUID) error {
+	sql := "DELETE FROM ApiTokens WHERE id = $1 AND user_id = $2"
+	tag, err := db(ctx, a.pool).Exec(ctx, sql, id, userId)
+	if err != nil {
+		return dbError(ctx, "ApiTokenRepository.DeleteApiToken", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return classified(ctx, "ApiTokenRepository.DeleteApiToken", ErrNoRow)
+	}
+	return nil
+}
+
+func (a *ApiTokenRepositoryRealization) GetApiTokenByHash(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error) {
+	sql := `SELECT t.id, t.user_id, t.name, t.scopes, t.expires_at, t.last_used_at, t.created_at, u.username, u.email, u.role, COALESCE(u.language, '')
+		FROM ApiTokens t JOIN Users u ON u.id = t.user_id
+		WHERE t.token_hash = $1 AND u.deleted_at IS NULL AND u.suspended_at IS NULL`
+	row := db(ctx, a.pool).QueryRow(ctx, sql, tokenHash)
+	var apiToken domain.ApiToken
+	var claims domain.UserClaims
+	var scopes []string
+	if err := row.Scan(&apiToken.Id, &apiToken.UserId, &apiToken.Name, &scopes, &apiToken.ExpiresAt, &apiToken.LastUsedAt, &apiToken.CreatedAt, &claims.Username, &claims.Email, &claims.Role, &claims.Language); err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return domain.ApiToken{}, domain.UserClaims{}, classified(ctx, "ApiTokenRepository.GetApiTokenByHash", ErrNoRow)
+	} else if err != nil {
+		return domain.ApiToken{}, domain.UserClaims{}, dbError(ctx, "ApiTokenRepository.GetApiTokenByHash", err)
+	}
+	apiToken.Scopes = stringsToScopes(scopes)
+	claims.Id = apiToken.UserId
+	return apiToken, claims, nil
+}
+
+func (a *ApiTokenRepositoryRealization) TouchApiToken(ctx context.Context, id uuid.UUID, lastUsedAt time.Time) error {
+	sql := "UPDATE ApiTokens SET last_used_at = $1 WHERE id = $2"
+	_, err := db(ctx, a.pool).Exec(ctx, sql, lastUsedAt, id)
+	if err != nil {
+		return dbError(ctx, "ApiTokenRepository.TouchApiToken", err)
+	}
+	return nil
+}
+
+func scopesToStrings(scopes []domain.Scope) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+	return result
+}
+
+func stringsToScopes(scopes []string) []domain.Scope {
+	result := make([]domain.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, domain.Scope(scope))
+	}
+	return result
+}
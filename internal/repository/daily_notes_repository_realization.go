@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"chopper/internal/domain"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -22,49 +25,98 @@ func NewDailyNotesRepositoryRealization(pool *pgxpool.Pool) *DailyNotesRepositor
 
 func (d *DailyNotesRepositoryRealization) CreateNote(ctx context.Context, id, userId uuid.UUID, date time.Time, mood int16, sleepHours float64, load int16) error {
 	sql := "INSERT INTO DailyEntries (id, user_id, date, mood, sleep_hours, load) VALUES ($1, $2, $3, $4, $5, $6)"
-	_, err := d.pool.Exec(ctx, sql, id, userId, date, mood, sleepHours, load)
+	_, err := db(ctx, d.pool).Exec(ctx, sql, id, userId, date, mood, sleepHours, load)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return ErrUniqueViolation
+			return classified(ctx, "DailyNotesRepository.CreateNote", ErrUniqueViolation)
 		}
-		return err
+		return dbError(ctx, "DailyNotesRepository.CreateNote", err)
 	}
 	return nil
 }
 
-func (d *DailyNotesRepositoryRealization) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16) error {
-	sql := "UPDATE DailyEntries SET mood = $1 WHERE user_id = $2 AND date = $3"
-	tag, err := d.pool.Exec(ctx, sql, mood, userId, date)
-	if err != nil {
-		return err
+// GetNote - запись пользователя за date вместе с версией
+func (d *DailyNotesRepositoryRealization) GetNote(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error) {
+	sql := "SELECT id, date, mood, sleep_hours, load, version FROM DailyEntries WHERE user_id = $1 AND date = $2"
+	var entry domain.DailyEntry
+	err := db(ctx, d.pool).QueryRow(ctx, sql, userId, date).Scan(&entry.Id, &entry.Date, &entry.Mood, &entry.SleepHours, &entry.Load, &entry.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DailyEntry{}, classified(ctx, "DailyNotesRepository.GetNote", ErrDailyEntryNotFound)
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrDailyEntryNotFound
+	if err != nil {
+		return domain.DailyEntry{}, dbError(ctx, "DailyNotesRepository.GetNote", err)
 	}
-	return nil
+	return entry, nil
 }
 
-func (d *DailyNotesRepositoryRealization) ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64) error {
-	sql := "UPDATE DailyEntries SET sleep_hours = $1 WHERE user_id = $2 AND date = $3"
-	tag, err := d.pool.Exec(ctx, sql, sleepHours, userId, date)
-	if err != nil {
-		return err
+// GetTimeZone - часовой пояс пользователя из настроек напоминаний, "" если не задан
+func (d *DailyNotesRepositoryRealization) GetTimeZone(ctx context.Context, userId uuid.UUID) (string, error) {
+	sql := "SELECT COALESCE(time_zone, '') FROM Users WHERE id = $1"
+	var timeZone string
+	err := db(ctx, d.pool).QueryRow(ctx, sql, userId).Scan(&timeZone)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrDailyEntryNotFound
+	if err != nil {
+		return "", dbError(ctx, "DailyNotesRepository.GetTimeZone", err)
 	}
-	return nil
+	return timeZone, nil
+}
+
+// ChangeMood меняет настроение, если версия записи равна version (0 - без проверки)
+func (d *DailyNotesRepositoryRealization) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16, version int) (domain.EntryChange, error) {
+	return d.changeEntry(ctx, domain.EntryFieldMood, mood, userId, date, version)
+}
+
+func (d *DailyNotesRepositoryRealization) ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64, version int) (domain.EntryChange, error) {
+	return d.changeEntry(ctx, domain.EntryFieldSleepHours, sleepHours, userId, date, version)
 }
 
-func (d *DailyNotesRepositoryRealization) ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, load int16) error {
-	sql := "UPDATE DailyEntries SET load = $1 WHERE user_id = $2 AND date = $3"
-	tag, err := d.pool.Exec(ctx, sql, load, userId, date)
+func (d *DailyNotesRepositoryRealization) ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, load int16, version int) (domain.EntryChange, error) {
+	return d.changeEntry(ctx, domain.EntryFieldLoad, load, userId, date, version)
+}
+
+// RestoreNote записывает все три значения одной новой версией, если версия записи все еще entry.Version
+func (d *DailyNotesRepositoryRealization) RestoreNote(ctx context.Context, userId uuid.UUID, entry domain.DailyEntry) (int, error) {
+	sql := `UPDATE DailyEntries SET mood = $1, sleep_hours = $2, load = $3, version = version + 1
+		WHERE id = $4 AND user_id = $5 AND version = $6 RETURNING version`
+	var newVersion int
+	err := db(ctx, d.pool).QueryRow(ctx, sql, entry.Mood, entry.SleepHours, entry.Load, entry.Id, userId, entry.Version).Scan(&newVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, classified(ctx, "DailyNotesRepository.RestoreNote", ErrDailyEntryVersionMismatch)
+	}
 	if err != nil {
-		return err
+		return 0, dbError(ctx, "DailyNotesRepository.RestoreNote", err)
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrDailyEntryNotFound
+	return newVersion, nil
+}
+
+// changeEntry меняет одно поле и возвращает старое значение. Строка блокируется в CTE, поэтому старое значение -
+// именно то, что перезаписано. Если строка не обновилась, отдельный запрос отличает отсутствующую запись от устаревшей версии
+func (d *DailyNotesRepositoryRealization) changeEntry(ctx context.Context, field domain.EntryField, value any, userId uuid.UUID, date time.Time, version int) (domain.EntryChange, error) {
+	// field - константа из domain, не пользовательский ввод
+	sql := fmt.Sprintf(`WITH old AS (SELECT id, %[1]v FROM DailyEntries WHERE user_id = $2 AND date = $3 FOR UPDATE)
+		UPDATE DailyEntries e SET %[1]v = $1, version = e.version + 1 FROM old
+		WHERE e.id = old.id AND ($4::int = 0 OR e.version = $4)
+		RETURNING e.id, e.version, old.%[1]v::double precision, e.%[1]v::double precision`, field)
+	change := domain.EntryChange{Field: field}
+	var oldValue float64
+	err := db(ctx, d.pool).QueryRow(ctx, sql, value, userId, date, version).Scan(&change.EntryId, &change.Version, &oldValue, &change.NewValue)
+	if err == nil {
+		change.OldValue = &oldValue
+		return change, nil
 	}
-	return nil
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.EntryChange{}, dbError(ctx, "DailyNotesRepository.changeEntry", err)
+	}
+	var exists bool
+	existsSql := "SELECT EXISTS (SELECT 1 FROM DailyEntries WHERE user_id = $1 AND date = $2)"
+	if err := db(ctx, d.pool).QueryRow(ctx, existsSql, userId, date).Scan(&exists); err != nil {
+		return domain.EntryChange{}, dbError(ctx, "DailyNotesRepository.changeEntry", err)
+	}
+	if exists {
+		return domain.EntryChange{}, classified(ctx, "DailyNotesRepository.changeEntry", ErrDailyEntryVersionMismatch)
+	}
+	return domain.EntryChange{}, classified(ctx, "DailyNotesRepository.changeEntry", ErrDailyEntryNotFound)
 }
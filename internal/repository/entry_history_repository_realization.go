@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EntryHistoryRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewEntryHistoryRepositoryRealization(pool *pgxpool.Pool) *EntryHistoryRepositoryRealization {
+	return &EntryHistoryRepositoryRealization{
+		pool: pool,
+	}
+}
+
+// AddEntryChanges пишет в транзакцию из ctx, поэтому изменение и его история появляются вместе
+func (e *EntryHistoryRepositoryRealization) AddEntryChanges(ctx context.Context, changes ...domain.EntryChange) error {
+	sql := `INSERT INTO DailyEntryHistory (entry_id, user_id, version, field, old_value, new_value, reason, client_ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	batch := &pgx.Batch{}
+	for _, change := range changes {
+		batch.Queue(sql, change.EntryId, change.UserId, change.Version, change.Field, change.OldValue, change.NewValue, change.Reason, change.ClientIP, change.UserAgent)
+	}
+	return db(ctx, e.pool).SendBatch(ctx, batch).Close()
+}
+
+func (e *EntryHistoryRepositoryRealization) GetEntryHistory(ctx context.Context, entryId uuid.UUID) ([]domain.EntryChange, error) {
+	sql := `SELECT entry_id, user_id, version, field, old_value, new_value, reason, changed_at, client_ip, user_agent
+		FROM DailyEntryHistory WHERE entry_id = $1 ORDER BY version, id`
+	rows, err := db(ctx, e.pool).Query(ctx, sql, entryId)
+	if err != nil {
+		return nil, dbError(ctx, "EntryHistoryRepository.GetEntryHistory", err)
+	}
+	defer rows.Close()
+	changes := []domain.EntryChange{}
+	for rows.Next() {
+		var change domain.EntryChange
+		if err := rows.Scan(&change.EntryId, &change.UserId, &change.Version, &change.Field, &change.OldValue, &change.NewValue,
+			&change.Reason, &change.ChangedAt, &change.ClientIP, &change.UserAgent); err != nil {
+			return nil, dbError(ctx, "EntryHistoryRepository.GetEntryHistory", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "EntryHistoryRepository.GetEntryHistory", err)
+	}
+	return changes, nil
+}
@@ -5,3 +5,4 @@ import "errors"
 var ErrUniqueViolation = errors.New("unique violation")
 var ErrNoRow = errors.New("no rows found")
 var ErrDailyEntryNotFound = errors.New("daily entry not found")
+var ErrDailyEntryVersionMismatch = errors.New("daily entry version mismatch")
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HealthRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewHealthRepositoryRealization(pool *pgxpool.Pool) *HealthRepositoryRealization {
+	return &HealthRepositoryRealization{
+		pool: pool,
+	}
+}
+
+func (h *HealthRepositoryRealization) Ping(ctx context.Context) error {
+	return h.pool.Ping(ctx)
+}
+
+// GetSchemaVersion читает версию из таблицы golang-migrate
+func (h *HealthRepositoryRealization) GetSchemaVersion(ctx context.Context) (uint, bool, error) {
+	sql := "SELECT version, dirty FROM schema_migrations LIMIT 1"
+	var version int64
+	var dirty bool
+	if err := h.pool.QueryRow(ctx, sql).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, classified(ctx, "HealthRepository.GetSchemaVersion", ErrNoRow)
+		}
+		return 0, false, dbError(ctx, "HealthRepository.GetSchemaVersion", err)
+	}
+	return uint(version), dirty, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyRepositoryRealization - ответы на запросы с Idempotency-Key, общие для всех реплик
+type IdempotencyRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepositoryRealization(pool *pgxpool.Pool) *IdempotencyRepositoryRealization {
+	return &IdempotencyRepositoryRealization{
+		pool: pool,
+	}
+}
+
+// Begin занимает ключ одним INSERT: истекшую запись он перезаписывает, живую не трогает.
+// Если ключ занят, возвращается сохраненная запись. Между INSERT и SELECT запись могла удалиться - тогда вторая попытка
+func (i *IdempotencyRepositoryRealization) Begin(ctx context.Context, scope, key, fingerprint string, lockExpiresAt time.Time) (domain.IdempotencyRecord, bool, error) {
+	insertSql := `INSERT INTO IdempotencyKeys AS k (scope, key, fingerprint, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint, status = NULL, headers = NULL, body = NULL,
+			created_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE k.expires_at < NOW()`
+	selectSql := "SELECT fingerprint, status, headers, body FROM IdempotencyKeys WHERE scope = $1 AND key = $2"
+	for range 2 {
+		tag, err := i.pool.Exec(ctx, insertSql, scope, key, fingerprint, lockExpiresAt)
+		if err != nil {
+			return domain.IdempotencyRecord{}, false, dbError(ctx, "IdempotencyRepository.Begin", err)
+		}
+		if tag.RowsAffected() == 1 {
+			return domain.IdempotencyRecord{Fingerprint: fingerprint}, true, nil
+		}
+		var record domain.IdempotencyRecord
+		var status *int
+		err = i.pool.QueryRow(ctx, selectSql, scope, key).Scan(&record.Fingerprint, &status, &record.Headers, &record.Body)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return domain.IdempotencyRecord{}, false, dbError(ctx, "IdempotencyRepository.Begin", err)
+		}
+		if status != nil {
+			record.Status = *status
+		}
+		return record, false, nil
+	}
+	return domain.IdempotencyRecord{}, false, classified(ctx, "IdempotencyRepository.Begin", ErrNoRow)
+}
+
+func (i *IdempotencyRepositoryRealization) Complete(ctx context.Context, scope, key string, record domain.IdempotencyRecord, expiresAt time.Time) error {
+	sql := "UPDATE IdempotencyKeys SET status = $3, headers = $4, body = $5, expires_at = $6 WHERE scope = $1 AND key = $2"
+	_, err := i.pool.Exec(ctx, sql, scope, key, record.Status, record.Headers, record.Body, expiresAt)
+	if err != nil {
+		return dbError(ctx, "IdempotencyRepository.Complete", err)
+	}
+	return nil
+}
+
+// Release удаляет только незавершенную запись, сохраненный ответ остается
+func (i *IdempotencyRepositoryRealization) Release(ctx context.Context, scope, key string) error {
+	sql := "DELETE FROM IdempotencyKeys WHERE scope = $1 AND key = $2 AND status IS NULL"
+	_, err := i.pool.Exec(ctx, sql, scope, key)
+	if err != nil {
+		return dbError(ctx, "IdempotencyRepository.Release", err)
+	}
+	return nil
+}
+
+func (i *IdempotencyRepositoryRealization) DeleteExpired(ctx context.Context) (int64, error) {
+	sql := "DELETE FROM IdempotencyKeys WHERE expires_at < NOW()"
+	tag, err := i.pool.Exec(ctx, sql)
+	if err != nil {
+		return 0, dbError(ctx, "IdempotencyRepository.DeleteExpired", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"chopper/internal/logger"
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// classified пишет в лог запроса, во что репозиторий превратил ответ базы, и возвращает err.
+// Сервисы часто обрабатывают такие ошибки молча, а по request_id видно, какой запрос их получил
+func classified(ctx context.Context, op string, err error) error {
+	logger.FromContext(ctx).WithFields(logrus.Fields{"op": op, "result": err.Error()}).Debug("database result classified")
+	return err
+}
+
+// dbError пишет ошибку базы в лог запроса вместе с операцией и возвращает ее без изменений
+func dbError(ctx context.Context, op string, err error) error {
+	logger.FromContext(ctx).WithField("op", op).WithError(err).Error("database query failed")
+	return err
+}
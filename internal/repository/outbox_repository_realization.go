@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepositoryRealization(pool *pgxpool.Pool) *OutboxRepositoryRealization {
+	return &OutboxRepositoryRealization{
+		pool: pool,
+	}
+}
+
+// AddOutboxEvents пишет в транзакцию из ctx, поэтому событие появляется только вместе с изменением
+func (o *OutboxRepositoryRealization) AddOutboxEvents(ctx context.Context, events ...domain.DomainEvent) error {
+	sql := "INSERT INTO Outbox (type, user_id, payload) VALUES ($1, $2, $3)"
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		batch.Queue(sql, event.Type, event.UserId, event.Payload)
+	}
+	return db(ctx, o.pool).SendBatch(ctx, batch).Close()
+}
+
+// GetPendingOutboxEvents - по порядку id; событие с исчерпанными попытками остается в таблице для разбора
+func (o *OutboxRepositoryRealization) GetPendingOutboxEvents(ctx context.Context, maxAttempts, limit int) ([]domain.DomainEvent, error) {
+	sql := `SELECT id, type, user_id, payload, occurred_at, attempts FROM Outbox
+		WHERE published_at IS NULL AND attempts < $1 AND available_at <= NOW()
+		ORDER BY id LIMIT $2`
+	rows, err := db(ctx, o.pool).Query(ctx, sql, maxAttempts, limit)
+	if err != nil {
+		return nil, dbError(ctx, "OutboxRepository.GetPendingOutboxEvents", err)
+	}
+	defer rows.Close()
+	events := []domain.DomainEvent{}
+	for rows.Next() {
+		var event domain.DomainEvent
+		if err := rows.Scan(&event.Id, &event.Type, &event.UserId, &event.Payload, &event.OccurredAt, &event.Attempts); err != nil {
+			return nil, dbError(ctx, "OutboxRepository.GetPendingOutboxEvents", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "OutboxRepository.GetPendingOutboxEvents", err)
+	}
+	return events, nil
+}
+
+func (o *OutboxRepositoryRealization) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	sql := "UPDATE Outbox SET published_at = NOW(), last_error = NULL WHERE id = $1"
+	_, err := db(ctx, o.pool).Exec(ctx, sql, id)
+	if err != nil {
+		return dbError(ctx, "OutboxRepository.MarkOutboxEventPublished", err)
+	}
+	return nil
+}
+
+func (o *OutboxRepositoryRealization) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, retryAt time.Time) error {
+	sql := "UPDATE Outbox SET attempts = attempts + 1, last_error = $1, available_at = $2 WHERE id = $3"
+	_, err := db(ctx, o.pool).Exec(ctx, sql, lastError, retryAt, id)
+	if err != nil {
+		return dbError(ctx, "OutboxRepository.MarkOutboxEventFailed", err)
+	}
+	return nil
+}
+
+func (o *OutboxRepositoryRealization) DeleteOutboxEventsPublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	sql := "DELETE FROM Outbox WHERE published_at < $1"
+	tag, err := db(ctx, o.pool).Exec(ctx, sql, before)
+	if err != nil {
+		return 0, dbError(ctx, "OutboxRepository.DeleteOutboxEventsPublishedBefore", err)
+	}
+	return tag.RowsAffected(), nil
+}
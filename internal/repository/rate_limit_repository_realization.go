@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimitRepositoryRealization - общее хранилище корзин для нескольких экземпляров сервиса
+type RateLimitRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewRateLimitRepositoryRealization(pool *pgxpool.Pool) *RateLimitRepositoryRealization {
+	return &RateLimitRepositoryRealization{
+		pool: pool,
+	}
+}
+
+// Take пополняет корзину за прошедшее время и списывает токен одним запросом.
+// Строка блокируется на время ON CONFLICT DO UPDATE, поэтому параллельные запросы с разных реплик не теряют списания.
+// Время берется из базы, чтобы расхождение часов между репликами не влияло на пополнение
+func (r *RateLimitRepositoryRealization) Take(ctx context.Context, key string, policy domain.RateLimitPolicy, now time.Time) (domain.RateLimitResult, error) {
+	sql := `INSERT INTO RateLimitBuckets AS b (key, tokens, allowed, updated_at) VALUES ($1, $2::double precision - 1, TRUE, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::double precision * $3::double precision)
+				- CASE WHEN LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::double precision * $3::double precision) >= 1 THEN 1 ELSE 0 END,
+			allowed = LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::double precision * $3::double precision) >= 1,
+			updated_at = NOW()
+		RETURNING tokens, allowed`
+	perSecond := float64(time.Second) / float64(policy.Rate)
+	var tokens float64
+	var allowed bool
+	if err := r.pool.QueryRow(ctx, sql, key, float64(policy.Burst), perSecond).Scan(&tokens, &allowed); err != nil {
+		return domain.RateLimitResult{}, dbError(ctx, "RateLimitRepository.Take", err)
+	}
+	result := domain.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     policy.Burst,
+		Remaining: max(int(math.Floor(tokens)), 0),
+		Reset:     time.Duration((float64(policy.Burst) - tokens) * float64(policy.Rate)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(policy.Rate))
+	}
+	return result, nil
+}
+
+// EvictIdle удаляет корзины, которые не трогали дольше idleTimeout. RateLimiter передает простой не меньше
+// времени пополнения корзины, поэтому удаляются только полные
+func (r *RateLimitRepositoryRealization) EvictIdle(ctx context.Context, idleTimeout time.Duration) error {
+	sql := "DELETE FROM RateLimitBuckets WHERE updated_at < NOW() - $1::interval"
+	if _, err := r.pool.Exec(ctx, sql, idleTimeout); err != nil {
+		// janitor лимитера ошибку не обрабатывает, поэтому она попадает в лог здесь
+		return dbError(ctx, "RateLimitRepository.EvictIdle", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReminderRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewReminderRepositoryRealization(pool *pgxpool.Pool) *ReminderRepositoryRealization {
+	return &ReminderRepositoryRealization{
+		pool: pool,
+	}
+}
+
+// GetDueReminders - "сегодня" и время напоминания считаются в часовом поясе пользователя.
+// Неудачные доставки возвращаются снова после retry_at, пока не кончатся попытки
+func (r *ReminderRepositoryRealization) GetDueReminders(ctx context.Context, maxAttempts, limit int) ([]domain.Reminder, error) {
+	sql := `SELECT u.id, u.username, u.email, COALESCE(u.language, ''), n.local_now::date, COALESCE(d.attempts, 0) + 1
+		FROM Users u
+		CROSS JOIN LATERAL (SELECT NOW() AT TIME ZONE u.time_zone AS local_now) n
+		LEFT JOIN ReminderDeliveries d ON d.user_id = u.id AND d.date = n.local_now::date
+		WHERE u.reminder_time IS NOT NULL AND u.time_zone IS NOT NULL
+			AND u.deleted_at IS NULL AND u.suspended_at IS NULL
+			AND n.local_now::time >= u.reminder_time
+			AND NOT EXISTS (SELECT 1 FROM DailyEntries e WHERE e.user_id = u.id AND e.date = n.local_now::date)
+			AND (d.user_id IS NULL OR (d.sent_at IS NULL AND d.attempts < $1 AND (d.retry_at IS NULL OR d.retry_at <= NOW())))
+		ORDER BY u.reminder_time
+		LIMIT $2`
+	rows, err := db(ctx, r.pool).Query(ctx, sql, maxAttempts, limit)
+	if err != nil {
+		return nil, dbError(ctx, "ReminderRepository.GetDueReminders", err)
+	}
+	defer rows.Close()
+	reminders := []domain.Reminder{}
+	for rows.Next() {
+		var reminder domain.Reminder
+		if err := rows.Scan(&reminder.UserId, &reminder.Username, &reminder.Email, &reminder.Language, &reminder.Date, &reminder.Attempt); err != nil {
+			return nil, dbError(ctx, "ReminderRepository.GetDueReminders", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "ReminderRepository.GetDueReminders", err)
+	}
+	return reminders, nil
+}
+
+func (r *ReminderRepositoryRealization) RecordReminderDelivery(ctx context.Context, userId uuid.UUID, date time.Time, deliveryErr string, retryAt time.Time) error {
+	sql := `INSERT INTO ReminderDeliveries (user_id, date, attempts, sent_at, last_error, retry_at)
+		VALUES ($1, $2, 1, CASE WHEN $3::text = '' THEN NOW() END, NULLIF($3::text, ''), CASE WHEN $3::text <> '' THEN $4::timestamptz END)
+		ON CONFLICT (user_id, date) DO UPDATE SET
+			attempts = ReminderDeliveries.attempts + 1,
+			sent_at = EXCLUDED.sent_at,
+			last_error = EXCLUDED.last_error,
+			retry_at = EXCLUDED.retry_at,
+			updated_at = NOW()`
+	_, err := db(ctx, r.pool).Exec(ctx, sql, userId, date, deliveryErr, retryAt)
+	if err != nil {
+		return dbError(ctx, "ReminderRepository.RecordReminderDelivery", err)
+	}
+	return nil
+}
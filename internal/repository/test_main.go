@@ -11,13 +11,13 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
 
+	"chopper/migrations"
+
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 var testPool *pgxpool.Pool
-var migrationsPath = "file://../../migrations" // internal//repository
 
 func TestMain(m *testing.M) {
 	testDataBaseConfig, err := TestDbConfigLoad()
@@ -37,7 +37,11 @@ func TestMain(m *testing.M) {
 	}
 
 	// добавление миграций
-	mgrt, err := migrate.New(migrationsPath, testUrl)
+	source, err := migrations.Source()
+	if err != nil {
+		logrus.Fatalf("error while trying to open embedded migrations: %v", err)
+	}
+	mgrt, err := migrate.NewWithSourceInstance("iofs", source, testUrl)
 	if err != nil {
 		logrus.Fatalf("error whyle trying to create migration object: %v", err)
 	}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// txKey - ключ транзакции в ctx, которую открыл TransactorRealization
+type txKey struct{}
+
+// querier - общее у пула и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// db возвращает транзакцию из ctx, если репозиторий вызван внутри WithinTx, иначе пул
+func db(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+type TransactorRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewTransactorRealization(pool *pgxpool.Pool) *TransactorRealization {
+	return &TransactorRealization{
+		pool: pool,
+	}
+}
+
+// WithinTx - вложенный вызов выполняется в уже открытой транзакции, коммитит только внешний
+func (t *TransactorRealization) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// после Commit откат ничего не делает
+	defer tx.Rollback(context.WithoutCancel(ctx))
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// userEventsChannel - канал pg_notify из триггера userevents_notify
+const userEventsChannel = "chopper_user_events"
+
+// UserEventListenerRealization держит отдельное соединение с LISTEN, пока идет Listen
+type UserEventListenerRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserEventListenerRealization(pool *pgxpool.Pool) *UserEventListenerRealization {
+	return &UserEventListenerRealization{
+		pool: pool,
+	}
+}
+
+// userEventNotification - json_build_object из триггера
+type userEventNotification struct {
+	Id        int64                   `json:"id"`
+	UserId    uuid.UUID               `json:"user_id"`
+	Type      domain.UserEventType    `json:"type"`
+	Payload   domain.UserEventPayload `json:"payload"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+func (u *UserEventListenerRealization) Listen(ctx context.Context, handle func(domain.UserEvent)) error {
+	conn, err := u.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// соединение в режиме LISTEN не возвращается в пул, а закрывается
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.WithoutCancel(ctx))
+	if _, err := pgConn.Exec(ctx, "LISTEN "+userEventsChannel); err != nil {
+		return err
+	}
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var event userEventNotification
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			return fmt.Errorf("parse user event notification: %w", err)
+		}
+		handle(domain.UserEvent{
+			Id:        event.Id,
+			UserId:    event.UserId,
+			Type:      event.Type,
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+}
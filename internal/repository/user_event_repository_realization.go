@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserEventRepositoryRealization struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserEventRepositoryRealization(pool *pgxpool.Pool) *UserEventRepositoryRealization {
+	return &UserEventRepositoryRealization{
+		pool: pool,
+	}
+}
+
+// CreateUserEvent - триггер userevents_notify рассылает событие всем репликам после коммита.
+// Вставки одного пользователя идут под транзакционной advisory блокировкой: id берется из
+// последовательности уже под ней, поэтому события пользователя коммитятся в порядке id и догон
+// по id > Last-Event-ID не пропускает событие с меньшим id, закоммиченное позже большего
+func (u *UserEventRepositoryRealization) CreateUserEvent(ctx context.Context, userId uuid.UUID, eventType domain.UserEventType, payload domain.UserEventPayload) (domain.UserEvent, error) {
+	sql := `WITH lock AS (SELECT pg_advisory_xact_lock($4))
+		INSERT INTO UserEvents (user_id, type, payload) SELECT $1::uuid, $2::text, $3::jsonb FROM lock RETURNING id, created_at`
+	event := domain.UserEvent{
+		UserId:  userId,
+		Type:    eventType,
+		Payload: payload,
+	}
+	if err := db(ctx, u.pool).QueryRow(ctx, sql, userId, eventType, payload, advisoryLockKey("userevents:"+userId.String())).Scan(&event.Id, &event.CreatedAt); err != nil {
+		return domain.UserEvent{}, dbError(ctx, "UserEventRepository.CreateUserEvent", err)
+	}
+	return event, nil
+}
+
+func (u *UserEventRepositoryRealization) GetUserEventsAfter(ctx context.Context, userId uuid.UUID, afterId int64, limit int) ([]domain.UserEvent, error) {
+	sql := "SELECT id, user_id, type, payload, created_at FROM UserEvents WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3"
+	rows, err := db(ctx, u.pool).Query(ctx, sql, userId, afterId, limit)
+	if err != nil {
+		return []domain.UserEvent{}, dbError(ctx, "UserEventRepository.GetUserEventsAfter", err)
+	}
+	defer rows.Close()
+	events := []domain.UserEvent{}
+	for rows.Next() {
+		var event domain.UserEvent
+		if err := rows.Scan(&event.Id, &event.UserId, &event.Type, &event.Payload, &event.CreatedAt); err != nil {
+			return []domain.UserEvent{}, dbError(ctx, "UserEventRepository.GetUserEventsAfter", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.UserEvent{}, dbError(ctx, "UserEventRepository.GetUserEventsAfter", err)
+	}
+	return events, nil
+}
+
+func (u *UserEventRepositoryRealization) GetLastUserEvent(ctx context.Context, userId uuid.UUID, eventType domain.UserEventType) (domain.UserEvent, error) {
+	sql := "SELECT id, user_id, type, payload, created_at FROM UserEvents WHERE user_id = $1 AND type = $2 ORDER BY id DESC LIMIT 1"
+	var event domain.UserEvent
+	row := db(ctx, u.pool).QueryRow(ctx, sql, userId, eventType)
+	if err := row.Scan(&event.Id, &event.UserId, &event.Type, &event.Payload, &event.CreatedAt); err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return domain.UserEvent{}, classified(ctx, "UserEventRepository.GetLastUserEvent", ErrNoRow)
+	} else if err != nil {
+		return domain.UserEvent{}, dbError(ctx, "UserEventRepository.GetLastUserEvent", err)
+	}
+	return event, nil
+}
+
+func (u *UserEventRepositoryRealization) DeleteUserEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	sql := "DELETE FROM UserEvents WHERE created_at < $1"
+	tag, err := db(ctx, u.pool).Exec(ctx, sql, before)
+	if err != nil {
+		return 0, dbError(ctx, "UserEventRepository.DeleteUserEventsBefore", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -4,7 +4,7 @@ import (
 	"chopper/internal/domain"
 	"context"
 	"errors"
-	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -24,26 +24,25 @@ func NewUserRepositoryRealization(pool *pgxpool.Pool) *UserRepositoryRealization
 
 func (u *UserRepositoryRealization) CreateUser(ctx context.Context, uuid uuid.UUID, username, email, hashPassword string, role domain.Role) error {
 	sql := "INSERT INTO Users (id, username, email, password_hash, role) VALUES ($1, $2, $3, $4, $5)"
-	_, err := u.pool.Exec(ctx, sql, uuid, username, email, hashPassword, role)
+	_, err := db(ctx, u.pool).Exec(ctx, sql, uuid, username, email, hashPassword, role)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return ErrUniqueViolation
+			return classified(ctx, "UserRepository.CreateUser", ErrUniqueViolation)
 		}
-		return err
+		return dbError(ctx, "UserRepository.CreateUser", err)
 	}
 	return nil
 }
 
 func (u *UserRepositoryRealization) CheckUser(ctx context.Context, username string) (domain.User, error) {
-	sql := "SELECT id, username, email, password_hash, role, created_at, deleted_at FROM Users WHERE username = $1"
-	row := u.pool.QueryRow(ctx, sql, username)
+	sql := "SELECT id, username, email, password_hash, role, created_at, deleted_at, suspended_at, COALESCE(language, '') FROM Users WHERE username = $1"
+	row := db(ctx, u.pool).QueryRow(ctx, sql, username)
 	var user domain.User
-	if err := row.Scan(&user.Id, &user.Username, &user.Email, &user.HashPassword, &user.Role, &user.CreatedAt, &user.DeletedAt); err != nil && errors.Is(err, pgx.ErrNoRows) {
-		return domain.User{}, ErrNoRow
+	if err := row.Scan(&user.Id, &user.Username, &user.Email, &user.HashPassword, &user.Role, &user.CreatedAt, &user.DeletedAt, &user.SuspendedAt, &user.Language); err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return domain.User{}, classified(ctx, "UserRepository.CheckUser", ErrNoRow)
 	} else if err != nil {
-		fmt.Println(err)
-		return domain.User{}, err
+		return domain.User{}, dbError(ctx, "UserRepository.CheckUser", err)
 	}
 	return user, nil
 }
@@ -51,11 +50,73 @@ func (u *UserRepositoryRealization) CheckUser(ctx context.Context, username stri
 func (u *UserRepositoryRealization) GetIdUsernameRole(ctx context.Context, id uuid.UUID, username string) (domain.UserWhoAmI, error) {
 	sql := "SELECT id, username, role FROM Users WHERE id = $1 AND username = $2"
 	var user domain.UserWhoAmI
-	row := u.pool.QueryRow(ctx, sql, id, username)
+	row := db(ctx, u.pool).QueryRow(ctx, sql, id, username)
 	if err := row.Scan(&user.Id, &user.Username, &user.Role); err != nil && errors.Is(err, pgx.ErrNoRows) {
-		return domain.UserWhoAmI{}, ErrNoRow
+		return domain.UserWhoAmI{}, classified(ctx, "UserRepository.GetIdUsernameRole", ErrNoRow)
 	} else if err != nil {
-		return domain.UserWhoAmI{}, err
+		return domain.UserWhoAmI{}, dbError(ctx, "UserRepository.GetIdUsernameRole", err)
 	}
 	return user, nil
 }
+
+func (u *UserRepositoryRealization) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	sql := "UPDATE Users SET password_hash = $1 WHERE username = $2 AND deleted_at IS NULL"
+	tag, err := db(ctx, u.pool).Exec(ctx, sql, hashPassword, username)
+	if err != nil {
+		return dbError(ctx, "UserRepository.SetPasswordHash", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return classified(ctx, "UserRepository.SetPasswordHash", ErrNoRow)
+	}
+	return nil
+}
+
+// SuspendUser - повторная блокировка не сдвигает время первой
+func (u *UserRepositoryRealization) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	sql := "UPDATE Users SET suspended_at = COALESCE(suspended_at, $1) WHERE username = $2 AND deleted_at IS NULL"
+	tag, err := db(ctx, u.pool).Exec(ctx, sql, suspendedAt, username)
+	if err != nil {
+		return dbError(ctx, "UserRepository.SuspendUser", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return classified(ctx, "UserRepository.SuspendUser", ErrNoRow)
+	}
+	return nil
+}
+
+func (u *UserRepositoryRealization) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	sql := "SELECT deleted_at IS NULL AND suspended_at IS NULL, COALESCE(language, '') FROM Users WHERE id = $1"
+	var status domain.UserStatus
+	if err := db(ctx, u.pool).QueryRow(ctx, sql, id).Scan(&status.Active, &status.Language); err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return domain.UserStatus{}, classified(ctx, "UserRepository.GetUserStatus", ErrNoRow)
+	} else if err != nil {
+		return domain.UserStatus{}, dbError(ctx, "UserRepository.GetUserStatus", err)
+	}
+	return status, nil
+}
+
+// SetUserLanguage - пустая строка хранится как NULL
+func (u *UserRepositoryRealization) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	sql := "UPDATE Users SET language = NULLIF($1, '') WHERE id = $2 AND deleted_at IS NULL"
+	tag, err := db(ctx, u.pool).Exec(ctx, sql, language, id)
+	if err != nil {
+		return dbError(ctx, "UserRepository.SetUserLanguage", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return classified(ctx, "UserRepository.SetUserLanguage", ErrNoRow)
+	}
+	return nil
+}
+
+// SetUserReminder - пустые строки хранятся как NULL, без reminder_time напоминаний нет
+func (u *UserRepositoryRealization) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	sql := "UPDATE Users SET time_zone = NULLIF($1, ''), reminder_time = NULLIF($2, '')::time WHERE id = $3 AND deleted_at IS NULL"
+	tag, err := db(ctx, u.pool).Exec(ctx, sql, timeZone, reminderTime, id)
+	if err != nil {
+		return dbError(ctx, "UserRepository.SetUserReminder", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return classified(ctx, "UserRepository.SetUserReminder", ErrNoRow)
+	}
+	return nil
+}
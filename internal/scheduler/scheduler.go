@@ -0,0 +1,120 @@
+// Package scheduler запускает периодические задачи внутри процесса.
+// Каждый запуск берет распределенную блокировку, поэтому при нескольких репликах задача
+// выполняется только на одной из них
+package scheduler
+
+import (
+	"chopper/internal/logger"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Locker - распределенная блокировка по имени задачи; unlock вызывается после выполнения
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// Job - задача и период ее запуска. Run получает ctx, который отменяется при Stop:
+// задача должна остановиться в удобной точке, не бросая начатое
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	locker Locker
+	log    *logrus.Logger
+	jobs   []Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(locker Locker, log *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		log:    log,
+	}
+}
+
+// Add регистрирует задачу, вызывается до Start
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start запускает по горутине на задачу; первый запуск - через Interval после старта
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	s.log.WithField("jobs", len(s.jobs)).Info("scheduler started")
+}
+
+// Stop отменяет задачи и ждет, пока текущие запуски закончатся, но не дольше ctx
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	stopped := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		s.log.Info("scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// run - ошибки только логируются, следующий запуск будет по расписанию
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	log := s.log.WithField("job", job.Name)
+	// сервисы и репозитории пишут в лог с именем задачи, как в запросе - с request_id
+	ctx = logger.WithContext(ctx, log)
+	unlock, ok, err := s.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("job lock failed")
+		}
+		return
+	}
+	if !ok {
+		log.Debug("job is running on another replica")
+		return
+	}
+	defer unlock()
+	start := time.Now()
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		log.WithError(err).Error("job failed")
+		return
+	}
+	log.WithField("duration", time.Since(start).String()).Debug("job completed")
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Моки
+// Мок блокировки - locked имитирует задачу, которую выполняет другая реплика
+type MockLocker struct {
+	mu       sync.Mutex
+	locked   bool
+	unlocked int
+}
+
+func (m *MockLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locked {
+		return nil, false, nil
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.unlocked++
+	}, true, nil
+}
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+// Тест - задача запускается по расписанию и снимает блокировку после каждого запуска
+func TestSchedulerRunsJob(t *testing.T) {
+	// preparing
+	locker := &MockLocker{}
+	var runs atomic.Int32
+	scheduler := New(locker, testLogger())
+	scheduler.Add(Job{Name: "test", Interval: time.Millisecond * 5, Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	// test
+	scheduler.Start()
+	time.Sleep(time.Millisecond * 50)
+	err := scheduler.Stop(context.Background())
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if runs.Load() == 0 {
+		t.Fatalf("ожидался хотя бы один запуск")
+	}
+	if locker.unlocked != int(runs.Load()) {
+		t.Errorf("ожидалось снятий блокировки - %v, получено - %v", runs.Load(), locker.unlocked)
+	}
+}
+
+// Тест - задача не запускается, пока блокировку держит другая реплика
+func TestSchedulerSkipsLockedJob(t *testing.T) {
+	// preparing
+	locker := &MockLocker{locked: true}
+	var runs atomic.Int32
+	scheduler := New(locker, testLogger())
+	scheduler.Add(Job{Name: "test", Interval: time.Millisecond * 5, Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	// test
+	scheduler.Start()
+	time.Sleep(time.Millisecond * 30)
+	err := scheduler.Stop(context.Background())
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if runs.Load() != 0 {
+		t.Errorf("ожидалось запусков - 0, получено - %v", runs.Load())
+	}
+}
+
+// Тест - Stop отменяет ctx задачи и дожидается ее завершения
+func TestSchedulerStopWaitsForJob(t *testing.T) {
+	// preparing
+	started := make(chan struct{})
+	var finished atomic.Bool
+	scheduler := New(&MockLocker{}, testLogger())
+	scheduler.Add(Job{Name: "test", Interval: time.Millisecond, Run: func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 10)
+		finished.Store(true)
+		return ctx.Err()
+	}})
+	scheduler.Start()
+	<-started
+
+	// test
+	err := scheduler.Stop(context.Background())
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if !finished.Load() {
+		t.Errorf("Stop вернулся до завершения задачи")
+	}
+}
@@ -0,0 +1,33 @@
+package security
+
+import (
+	"chopper/internal/domain"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const apiTokenBytes = 32
+
+type ApiTokenGenerator struct {
+}
+
+func NewApiTokenGenerator() *ApiTokenGenerator {
+	return &ApiTokenGenerator{}
+}
+
+func (a *ApiTokenGenerator) Generate() (string, string, error) {
+	secret := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token := domain.ApiTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return token, a.Hash(token), nil
+}
+
+// Hash - у токена 256 бит энтропии, поэтому достаточно sha256 без соли (в отличие от паролей)
+func (a *ApiTokenGenerator) Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
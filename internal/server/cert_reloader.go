@@ -0,0 +1,101 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// как часто на хендшейке проверяется время изменения файлов сертификата
+const certCheckInterval = time.Second
+
+// certReloader отдает сертификат в tls.Config.GetCertificate и перечитывает файлы, когда они меняются
+// (например, после продления cert-manager или certbot), без перезапуска сервера
+type certReloader struct {
+	certFile  string
+	keyFile   string
+	log       *logrus.Logger
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt atomic.Int64 // unix nano последней проверки файлов
+}
+
+func newCertReloader(certFile, keyFile string, log *logrus.Logger) (*certReloader, error) {
+	c := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		log:      log,
+	}
+	modTime, err := c.filesModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.load(modTime); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	now := time.Now()
+	if checkedAt := c.checkedAt.Load(); now.UnixNano()-checkedAt >= int64(certCheckInterval) && c.checkedAt.CompareAndSwap(checkedAt, now.UnixNano()) {
+		c.reloadIfChanged()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// reloadIfChanged - при ошибке (файлы записаны наполовину, ключ не подходит) остается прежний сертификат
+func (c *certReloader) reloadIfChanged() {
+	modTime, err := c.filesModTime()
+	if err != nil {
+		c.log.WithError(err).Error("tls certificate check failed, keeping current certificate")
+		return
+	}
+	c.mu.RLock()
+	changed := !modTime.Equal(c.modTime)
+	c.mu.RUnlock()
+	if !changed {
+		return
+	}
+	if err := c.load(modTime); err != nil {
+		c.log.WithError(err).Error("tls certificate reload failed, keeping current certificate")
+		return
+	}
+	c.log.WithField("cert_file", c.certFile).Info("tls certificate reloaded")
+}
+
+func (c *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("load tls certificate: %w", err)
+	}
+	c.mu.Lock()
+	c.cert = &cert
+	c.modTime = modTime
+	c.mu.Unlock()
+	return nil
+}
+
+// filesModTime - самое позднее время изменения сертификата и ключа
+func (c *certReloader) filesModTime() (time.Time, error) {
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if keyInfo.ModTime().After(certInfo.ModTime()) {
+		return keyInfo.ModTime(), nil
+	}
+	return certInfo.ModTime(), nil
+}
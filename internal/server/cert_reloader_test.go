@@ -0,0 +1,91 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// writeTestCert пишет самоподписанный сертификат с serial и выставляет файлам время изменения modTime
+func writeTestCert(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key - %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate - %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key - %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert - %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600); err != nil {
+		t.Fatalf("write key - %v", err)
+	}
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("chtimes - %v", err)
+		}
+	}
+}
+
+func certSerial(t *testing.T, reloader *certReloader) int64 {
+	reloader.checkedAt.Store(0)
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("error was not expected - %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate - %v", err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+// Тест certReloader - новый сертификат подхватывается после изменения файлов, битый файл не заменяет рабочий
+func TestCertReloader(t *testing.T) {
+	// preparing
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	start := time.Now().Add(-time.Minute)
+	writeTestCert(t, certFile, keyFile, 1, start)
+	log, _ := test.NewNullLogger()
+	reloader, err := newCertReloader(certFile, keyFile, log)
+	if err != nil {
+		t.Fatalf("error was not expected - %v", err)
+	}
+
+	// test + assert
+	if serial := certSerial(t, reloader); serial != 1 {
+		t.Errorf("expected serial was - %v, got - %v", 1, serial)
+	}
+	writeTestCert(t, certFile, keyFile, 2, start.Add(time.Second))
+	if serial := certSerial(t, reloader); serial != 2 {
+		t.Errorf("expected serial after renewal was - %v, got - %v", 2, serial)
+	}
+	if err := os.WriteFile(certFile, []byte("half written"), 0o600); err != nil {
+		t.Fatalf("write cert - %v", err)
+	}
+	if serial := certSerial(t, reloader); serial != 2 {
+		t.Errorf("broken certificate must not replace the current one")
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+// listener - открытый сокет и сервер, который его обслуживает: HTTP или gRPC
+type listener struct {
+	name   string
+	server *http.Server
+	grpc   *grpc.Server
+	ln     net.Listener
+	tls    bool
+}
+
+func (l listener) serve() error {
+	if l.grpc != nil {
+		// TLS уже в ln, Serve возвращает nil после GracefulStop
+		return l.grpc.Serve(l.ln)
+	}
+	if l.tls {
+		// сертификат берется из TLSConfig.GetCertificate, поэтому файлы не передаются
+		return l.server.ServeTLS(l.ln, "", "")
+	}
+	return l.server.Serve(l.ln)
+}
+
+// listen открывает все сокеты заранее, чтобы ошибка bind вернулась из StartServer, а не потерялась в горутине
+func (s *Server) listen() ([]listener, error) {
+	listeners := []listener{}
+	fail := func(err error) ([]listener, error) {
+		for _, l := range listeners {
+			l.ln.Close()
+		}
+		return nil, err
+	}
+	if s.tls.Enabled() {
+		tlsConfig, err := s.newTLSConfig()
+		if err != nil {
+			return fail(err)
+		}
+		s.server.TLSConfig = tlsConfig
+	}
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fail(fmt.Errorf("listen %v: %w", s.server.Addr, err))
+	}
+	listeners = append(listeners, listener{name: "server", server: s.server, ln: ln, tls: s.tls.Enabled()})
+	if s.unixSocket != "" {
+		ln, err := listenUnix(s.unixSocket)
+		if err != nil {
+			return fail(err)
+		}
+		// sidecar на том же хосте, поэтому без TLS
+		listeners = append(listeners, listener{name: "unix socket", server: s.server, ln: ln})
+	}
+	ln, err = net.Listen("tcp", s.adminServer.Addr)
+	if err != nil {
+		return fail(fmt.Errorf("listen admin %v: %w", s.adminServer.Addr, err))
+	}
+	listeners = append(listeners, listener{name: "admin server", server: s.adminServer, ln: ln})
+	if s.grpcServer != nil && s.grpcAddress != "" {
+		ln, err := net.Listen("tcp", s.grpcAddress)
+		if err != nil {
+			return fail(fmt.Errorf("listen grpc %v: %w", s.grpcAddress, err))
+		}
+		// тот же сертификат и mTLS, что у HTTP; gRPC клиенты требуют h2 в ALPN
+		if s.tls.Enabled() {
+			tlsConfig := s.server.TLSConfig.Clone()
+			tlsConfig.NextProtos = []string{"h2"}
+			ln = tls.NewListener(ln, tlsConfig)
+		}
+		listeners = append(listeners, listener{name: "grpc server", grpc: s.grpcServer, ln: ln, tls: s.tls.Enabled()})
+	}
+	return listeners, nil
+}
+
+func (s *Server) newTLSConfig() (*tls.Config, error) {
+	reloader, err := newCertReloader(s.tls.CertFile, s.tls.KeyFile, s.log)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	// mTLS - без клиентского сертификата от доверенного CA соединение не устанавливается
+	if s.tls.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(s.tls.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client ca: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("tls client ca file %v has no certificates", s.tls.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// listenUnix удаляет сокет, оставшийся от упавшего процесса, и открывает новый только для владельца и группы
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("unix socket path %v exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale unix socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen unix %v: %w", path, err)
+	}
+	if err := os.Chmod(path, 0o660); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("chmod unix socket: %w", err)
+	}
+	return ln, nil
+}
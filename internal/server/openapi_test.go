@@ -0,0 +1,158 @@
+package server
+
+import (
+	"chopper/api"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/metrics"
+	"chopper/internal/middleware"
+	"chopper/internal/usecase"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// newTestRouter собирает роутер через NewServer, сервисы не нужны - хендлеры не вызываются
+func newTestRouter(t *testing.T) *gin.Engine {
+	return newTestRouterWithMetrics(t, metrics.New(nil))
+}
+
+func newTestRouterWithMetrics(t *testing.T, appMetrics *metrics.Metrics) *gin.Engine {
+	policies := map[string]domain.RateLimitPolicy{
+		"test": {Name: "test", Rate: time.Second, Burst: 100, Key: domain.RateLimitKeyIP},
+	}
+	groups := map[string]string{}
+	for _, group := range []string{domain.RateLimitGroupUsersPublic, domain.RateLimitGroupUsers, domain.RateLimitGroupUsersSession, domain.RateLimitGroupNotesRead,
+		domain.RateLimitGroupNotes, domain.RateLimitGroupAlert, domain.RateLimitGroupTokens, domain.RateLimitGroupEvents} {
+		groups[group] = "test"
+	}
+	rateLimiter := middleware.NewRateLimiter(policies, middleware.NewMemoryLimiterStore(), time.Minute, nil)
+	t.Cleanup(rateLimiter.Stop)
+	idempotency := middleware.NewIdempotency(nil, time.Hour)
+	log := logrus.New()
+	serverConfig := domain.ServerConfig{
+		ServerMode: domain.TestMode,
+		LegacyAPI: domain.LegacyAPIConfig{
+			Enabled:      true,
+			DeprecatedAt: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			Sunset:       time.Date(2027, 4, 19, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	server := NewServer(serverConfig, nil, nil, nil, nil, nil, nil,
+		middleware.NewAuthMiddleware(usecase.NewAuthenticator(nil, nil, nil)), middleware.NewLanguageMiddleware(i18n.Fallback), rateLimiter, groups, idempotency, middleware.NewRequestLogger(log),
+		middleware.NewCORSMiddleware(domain.CORSConfig{}), nil, nil, appMetrics, log)
+	return server.server.Handler.(*gin.Engine)
+}
+
+// Тест - каждый зарегистрированный роут описан в openapi.yaml, и в спецификации нет лишних.
+// Устаревшие пути без версии в спецификацию не входят, но у каждого должен быть двойник в /api/v1
+func TestOpenAPICoversRoutes(t *testing.T) {
+	// preparing
+	data, err := api.SpecJSON()
+	if err != nil {
+		t.Fatalf("spec - %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("unmarshal spec - %v", err)
+	}
+	// /tokens/:id -> /tokens/{id}
+	param := regexp.MustCompile(`:(\w+)`)
+
+	// test
+	routes := newTestRouter(t).Routes()
+
+	// assert
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[strings.ToLower(route.Method)+" "+param.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+	for _, route := range routes {
+		path := param.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+		if legacyPath.MatchString(path) {
+			if !registered[method+" "+apiV1Prefix+path] {
+				t.Errorf("legacy route %v %v has no %v twin", route.Method, route.Path, apiV1Prefix)
+			}
+			continue
+		}
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("route %v %v is missing from api/openapi.yaml", route.Method, route.Path)
+		}
+	}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			if !registered[method+" "+path] {
+				t.Errorf("api/openapi.yaml documents %v %v, but it is not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// legacyPath - пути v1 без префикса версии
+var legacyPath = regexp.MustCompile(`^/(users|notes|alert|tokens|events)(/|$)`)
+
+// Тест - устаревший путь отвечает как v1 и несет Deprecation, Sunset и ссылку на замену, а /api/v1 - нет
+func TestLegacyRoutesDeprecated(t *testing.T) {
+	// preparing
+	router := newTestRouter(t)
+
+	for _, path := range []string{"/users/me", apiV1Prefix + "/users/me"} {
+		// test
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		// assert
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%v: expected status 401 from the v1 handler chain, got - %v", path, w.Code)
+		}
+		legacy := !strings.HasPrefix(path, apiV1Prefix)
+		if deprecation := w.Header().Get("Deprecation"); (deprecation != "") != legacy {
+			t.Errorf("%v: unexpected Deprecation header - %q", path, deprecation)
+		}
+		if !legacy {
+			continue
+		}
+		if deprecation := w.Header().Get("Deprecation"); deprecation != "@1792368000" {
+			t.Errorf("%v: expected Deprecation @1792368000, got - %v", path, deprecation)
+		}
+		if sunset := w.Header().Get("Sunset"); sunset != "Mon, 19 Apr 2027 00:00:00 GMT" {
+			t.Errorf("%v: expected Sunset date, got - %v", path, sunset)
+		}
+		if link := w.Header().Get("Link"); link != `</api/v1/users/me>; rel="successor-version"` {
+			t.Errorf("%v: expected successor link, got - %v", path, link)
+		}
+	}
+}
+
+// Тест - /openapi.json и /docs отдаются без авторизации
+func TestOpenAPIServed(t *testing.T) {
+	// preparing
+	router := newTestRouter(t)
+
+	for path, contentType := range map[string]string{"/openapi.json": "application/json", "/docs": "text/html"} {
+		// test
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		// assert
+		if w.Code != http.StatusOK {
+			t.Errorf("%v: expected status 200, got - %v", path, w.Code)
+		}
+		if !strings.HasPrefix(w.Header().Get("Content-Type"), contentType) {
+			t.Errorf("%v: expected content type %v, got - %v", path, contentType, w.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	h "chopper/internal/delivery/http"
+	"chopper/internal/domain"
+	"chopper/internal/middleware"
+	"chopper/internal/usecase"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiRoutes - то, что общее у всех версий API: usecase сервисы и middleware.
+// Версия - метод, который монтирует свои хендлеры в переданную группу; /api/v2 добавляется
+// новым методом с новыми хендлерами поверх тех же сервисов, v1 при этом не меняется
+type apiRoutes struct {
+	userService       *usecase.UserService
+	dailyNotesService *usecase.DailyNotesService
+	alertService      *usecase.AlertService
+	apiTokenService   *usecase.ApiTokenService
+	userEventService  *usecase.UserEventService
+	authMiddleware    *middleware.AuthMiddleware
+	rateLimiter       *middleware.RateLimiter
+	rateLimitGroups   map[string]string
+	idempotency       *middleware.Idempotency
+}
+
+// apiV1Prefix - актуальный адрес v1; корневые пути - его устаревшие алиасы
+const apiV1Prefix = "/api/v1"
+
+// v1 монтирует хендлеры v1 в base: /api/v1 или корень для устаревших путей
+func (a *apiRoutes) v1(base *gin.RouterGroup) {
+	// users public
+	usersPublic := base.Group("/users")
+	usersPublic.Use(a.rateLimit(domain.RateLimitGroupUsersPublic))
+	usersPublic.Use(a.idempotency.Idempotent())
+
+	// users protected
+	usersProtected := base.Group("/users")
+	usersProtected.Use(a.authMiddleware.Auth())
+	usersProtected.Use(a.authMiddleware.RequireScope(domain.ScopeUsersRead))
+	usersProtected.Use(a.rateLimit(domain.RateLimitGroupUsers))
+	usersProtected.Use(a.idempotency.Idempotent())
+
+	// users protected (только по JWT)
+	usersSession := base.Group("/users")
+	usersSession.Use(a.authMiddleware.Auth())
+	usersSession.Use(a.authMiddleware.RequireSession())
+	usersSession.Use(a.rateLimit(domain.RateLimitGroupUsersSession))
+	usersSession.Use(a.idempotency.Idempotent())
+
+	// notes read
+	notesRead := base.Group("/notes")
+	notesRead.Use(a.authMiddleware.Auth())
+	notesRead.Use(a.authMiddleware.RequireScope(domain.ScopeNotesRead))
+	notesRead.Use(a.rateLimit(domain.RateLimitGroupNotesRead))
+
+	// notes protected
+	notesProtected := base.Group("/notes")
+	notesProtected.Use(a.authMiddleware.Auth())
+	notesProtected.Use(a.authMiddleware.RequireScope(domain.ScopeNotesWrite))
+	notesProtected.Use(a.rateLimit(domain.RateLimitGroupNotes))
+	notesProtected.Use(a.idempotency.Idempotent())
+
+	// alert protected
+	alertProtected := base.Group("/alert")
+	alertProtected.Use(a.authMiddleware.Auth())
+	alertProtected.Use(a.authMiddleware.RequireScope(domain.ScopeAlertsRead))
+	alertProtected.Use(a.rateLimit(domain.RateLimitGroupAlert))
+	alertProtected.Use(a.idempotency.Idempotent())
+
+	// tokens protected (только по JWT)
+	tokensProtected := base.Group("/tokens")
+	tokensProtected.Use(a.authMiddleware.Auth())
+	tokensProtected.Use(a.authMiddleware.RequireSession())
+	tokensProtected.Use(a.rateLimit(domain.RateLimitGroupTokens))
+	tokensProtected.Use(a.idempotency.Idempotent())
+
+	// events protected, scope проверяется по типу события в хендлере; только GET, Idempotency-Key не нужен
+	eventsProtected := base.Group("/events")
+	eventsProtected.Use(a.authMiddleware.Auth())
+	eventsProtected.Use(a.rateLimit(domain.RateLimitGroupEvents))
+
+	userHandler := h.NewUserHandler(a.userService)
+	userHandler.RegisterRoutes(usersPublic, usersProtected, usersSession)
+	noteHandler := h.NewNoteHandler(a.dailyNotesService)
+	noteHandler.RegisterRoutes(notesRead, notesProtected)
+	alertHandler := h.NewAlertHandler(a.alertService)
+	alertHandler.RegisterRoutes(alertProtected)
+	apiTokenHandler := h.NewApiTokenHandler(a.apiTokenService)
+	apiTokenHandler.RegisterRoutes(tokensProtected)
+	eventHandler := h.NewEventHandler(a.userEventService)
+	eventHandler.RegisterRoutes(eventsProtected)
+}
+
+// rateLimit - лимитер по политике, которую конфиг назначил группе. Группа без политики - ошибка сборки роутов,
+// config.Load заполняет все группы, которые здесь используются
+func (a *apiRoutes) rateLimit(group string) gin.HandlerFunc {
+	policy, ok := a.rateLimitGroups[group]
+	if !ok {
+		panic(fmt.Sprintf("no rate limit policy for group %q", group))
+	}
+	return a.rateLimiter.RateLimit(policy)
+}
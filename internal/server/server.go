@@ -3,9 +3,13 @@ package server
 import (
 	h "chopper/internal/delivery/http"
 	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/metrics"
 	"chopper/internal/middleware"
+	"chopper/internal/problem"
 	"chopper/internal/usecase"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,74 +18,207 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
+// ConfigReloader перечитывает конфиг по SIGHUP, реализуется в build
+type ConfigReloader interface {
+	Reload() error
+}
+
 type Server struct {
 	server            *http.Server
+	adminServer       *http.Server
+	grpcServer        *grpc.Server
+	grpcAddress       string
 	timeoutToShutdown time.Duration
+	drainDelay        time.Duration
+	healthService     *usecase.HealthService
+	reloader          ConfigReloader
+	tls               domain.TLSConfig
+	unixSocket        string
+	rateLimiter       *middleware.RateLimiter
+	log               *logrus.Logger
 }
 
-func NewServer(address string, readTimeout, writeTimeout, idleTimeout, timeoutToShutdown time.Duration, serverMode domain.ServerMode, userService *usecase.UserService, dailyNotesService *usecase.DailyNotesService, alertService *usecase.AlertService, authMiddleware *middleware.AuthMiddleware, rateLimiter *middleware.RateLimiter) *Server {
+func NewServer(serverConfig domain.ServerConfig, userService *usecase.UserService, dailyNotesService *usecase.DailyNotesService, alertService *usecase.AlertService, apiTokenService *usecase.ApiTokenService, userEventService *usecase.UserEventService, healthService *usecase.HealthService, authMiddleware *middleware.AuthMiddleware, languageMiddleware *middleware.LanguageMiddleware, rateLimiter *middleware.RateLimiter, rateLimitGroups map[string]string, idempotency *middleware.Idempotency, requestLogger *middleware.RequestLogger, corsMiddleware *middleware.CORSMiddleware, reloader ConfigReloader, grpcServer *grpc.Server, metrics *metrics.Metrics, log *logrus.Logger) *Server {
 	// создание gin core
-	gin.SetMode(string(serverMode))
+	gin.SetMode(string(serverConfig.ServerMode))
 	r := gin.New()
-	r.Use(gin.Recovery())
-	r.Use(gin.Logger())
-
-	// users public
-	usersPublic := r.Group("/users")
-	usersPublic.Use(rateLimiter.RateLimit())
-
-	// users protected
-	usersProtected := r.Group("/users")
-	usersProtected.Use(authMiddleware.Auth())
-	usersProtected.Use(rateLimiter.RateLimit())
+	r.Use(middleware.NewTracingMiddleware().Trace())
+	// язык нужен раньше всех, кто может ответить ошибкой, включая recovery
+	r.Use(languageMiddleware.Negotiate())
+	r.Use(requestLogger.Log())
+	// метрики снаружи recovery, как и лог: иначе 500 после паники не попадут в счетчик
+	r.Use(middleware.NewMetricsMiddleware(metrics).Observe())
+	r.Use(requestLogger.Recovery())
+	r.Use(corsMiddleware.Handle())
+	// неизвестные роуты и методы отвечают тем же problem+json, что и хендлеры
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		problem.Abort(c, problem.NotFound, i18n.Msg("detail.no_route", "path", c.Request.URL.Path))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		problem.Abort(c, problem.MethodNotAllowed, i18n.Msg("detail.method_not_allowed", "method", c.Request.Method, "path", c.Request.URL.Path))
+	})
 
-	// notes protected
-	notesProtected := r.Group("/notes")
-	notesProtected.Use(authMiddleware.Auth())
-	notesProtected.Use(rateLimiter.RateLimit())
+	// health пробы без авторизации и rate limiter
+	healthHandler := h.NewHealthHandler(healthService)
+	healthHandler.RegisterRoutes(r)
 
-	// alert protected
-	alertProtected := r.Group("/alert")
-	alertProtected.Use(authMiddleware.Auth())
-	alertProtected.Use(rateLimiter.RateLimit())
+	// спецификация и документация без авторизации
+	docsHandler := h.NewDocsHandler()
+	docsHandler.RegisterRoutes(r)
 
-	userHandler := h.NewUserHandler(userService)
-	userHandler.RegisterRoutes(usersPublic, usersProtected)
-	noteHandler := h.NewNoteHandler(dailyNotesService)
-	noteHandler.RegisterRoutes(notesProtected)
-	alertHandler := h.NewAlertHandler(alertService)
-	alertHandler.RegisterRoutes(alertProtected)
+	// API по версиям, сервисы и лимиты общие
+	routes := &apiRoutes{
+		userService:       userService,
+		dailyNotesService: dailyNotesService,
+		alertService:      alertService,
+		apiTokenService:   apiTokenService,
+		userEventService:  userEventService,
+		authMiddleware:    authMiddleware,
+		rateLimiter:       rateLimiter,
+		rateLimitGroups:   rateLimitGroups,
+		idempotency:       idempotency,
+	}
+	routes.v1(r.Group(apiV1Prefix))
+	// старые пути без версии - алиасы v1 до отключения server.legacy_routes
+	if serverConfig.LegacyAPI.Enabled {
+		routes.v1(r.Group("/", middleware.Deprecation(serverConfig.LegacyAPI, apiV1Prefix)))
+	}
 
 	server := &http.Server{
-		Addr:         address,
-		ReadTimeout:  readTimeout,
-		WriteTimeout: writeTimeout,
-		IdleTimeout:  idleTimeout,
+		Addr:         serverConfig.Address,
+		ReadTimeout:  serverConfig.ReadTimeout,
+		WriteTimeout: serverConfig.WriteTimeout,
+		IdleTimeout:  serverConfig.IdleTimeout,
 		Handler:      r,
 	}
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	if serverConfig.HTTP2 {
+		protocols.SetHTTP2(true)
+		// h2c нужен sidecar прокси (envoy и т.п.), которые ходят по HTTP/2 без TLS
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	server.Protocols = protocols
+	// SSE потоки сами не заканчиваются, Shutdown закрывает их после остановки приема соединений
+	if userEventService != nil {
+		server.RegisterOnShutdown(userEventService.CloseStreams)
+	}
+
+	// admin листенер - отдельный порт, наружу не публикуется
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", metrics.Handler())
+	adminServer := &http.Server{
+		Addr:         serverConfig.AdminAddress,
+		ReadTimeout:  serverConfig.ReadTimeout,
+		WriteTimeout: serverConfig.WriteTimeout,
+		IdleTimeout:  serverConfig.IdleTimeout,
+		Handler:      adminMux,
+	}
 	return &Server{
 		server:            server,
-		timeoutToShutdown: timeoutToShutdown,
+		adminServer:       adminServer,
+		grpcServer:        grpcServer,
+		grpcAddress:       serverConfig.GRPCAddress,
+		timeoutToShutdown: serverConfig.TimeToShutdown,
+		drainDelay:        serverConfig.DrainDelay,
+		healthService:     healthService,
+		reloader:          reloader,
+		tls:               serverConfig.TLS,
+		unixSocket:        serverConfig.UnixSocket,
+		rateLimiter:       rateLimiter,
+		log:               log,
 	}
 }
 
+// StartServer слушает до SIGINT/SIGTERM; ошибка любого листенера останавливает сервер и возвращается
 func (s *Server) StartServer() error {
-	address := s.server.Addr
+	defer s.rateLimiter.Stop()
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		fmt.Printf("server has been started on %v\n", address)
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			panic(err) // заменить в будущем
+	defer signal.Stop(stop)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	listeners, err := s.listen()
+	if err != nil {
+		return err
+	}
+	serveErrors := make(chan error, len(listeners))
+	for _, l := range listeners {
+		s.log.WithFields(logrus.Fields{
+			"listener": l.name,
+			"address":  l.ln.Addr().String(),
+			"tls":      l.tls,
+		}).Info("server has been started")
+		go func() {
+			if err := l.serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErrors <- fmt.Errorf("%v: %w", l.name, err)
+			}
+		}()
+	}
+
+	var serveErr error
+	for serveErr == nil {
+		select {
+		case <-reload:
+			s.log.Info("SIGHUP received, reloading config")
+			// ошибка уже залогирована, сервер продолжает работать на прежнем конфиге
+			_ = s.reloader.Reload()
+		case sig := <-stop:
+			s.log.WithField("signal", sig.String()).Info("shutting down server")
+			return s.shutdown(true)
+		case serveErr = <-serveErrors:
 		}
-	}()
-	<-stop
+	}
+	s.log.WithError(serveErr).Error("listener failed, shutting down server")
+	return errors.Join(serveErr, s.shutdown(false))
+}
+
+// shutdown - drain нужен только при штатной остановке, при упавшем листенере ждать балансировщик нет смысла
+func (s *Server) shutdown(drain bool) error {
+	// сначала /readyz начинает отвечать 503, и балансировщик успевает снять трафик
+	s.healthService.BeginShutdown()
+	if drain && s.drainDelay > 0 {
+		s.log.WithField("drain_delay", s.drainDelay.String()).Info("waiting for load balancer to drain traffic")
+		time.Sleep(s.drainDelay)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutToShutdown)
 	defer cancel()
-	if err := s.server.Shutdown(ctx); err != nil {
-		return err
+	// каждый листенер останавливается, даже если предыдущий не уложился в таймаут
+	httpErr := s.server.Shutdown(ctx)
+	if httpErr != nil {
+		httpErr = fmt.Errorf("http server: %w", httpErr)
+	}
+	grpcErr := s.stopGRPC(ctx)
+	// метрики отдаются до конца, чтобы последний scrape увидел завершение запросов
+	adminErr := s.adminServer.Shutdown(ctx)
+	if adminErr != nil {
+		adminErr = fmt.Errorf("admin server: %w", adminErr)
+	}
+	return errors.Join(httpErr, grpcErr, adminErr)
+}
+
+// stopGRPC дожидается текущих вызовов, как http.Server.Shutdown, и обрывает их по истечении ctx
+func (s *Server) stopGRPC(ctx context.Context) error {
+	if s.grpcServer == nil || s.grpcAddress == "" {
+		return nil
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return fmt.Errorf("grpc server: %w", ctx.Err())
 	}
-	return nil
 }
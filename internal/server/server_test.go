@@ -0,0 +1,148 @@
+package server
+
+import (
+	"chopper/internal/metrics"
+	"chopper/internal/problem"
+	"chopper/internal/usecase"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Тест - неизвестный роут и неразрешенный метод отвечают problem+json
+func TestUnknownRouteProblem(t *testing.T) {
+	// preparing
+	router := newTestRouter(t)
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/nope", http.StatusNotFound},
+		{http.MethodGet, "/notes/new", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		// test
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		// assert
+		if w.Code != tt.status {
+			t.Errorf("%v %v: expected status %v, got - %v", tt.method, tt.path, tt.status, w.Code)
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != problem.ContentType {
+			t.Errorf("%v %v: expected content type %v, got - %v", tt.method, tt.path, problem.ContentType, contentType)
+		}
+	}
+}
+
+// Тест - язык ответа выбирается по Accept-Language, без подходящего - язык по умолчанию (в тестовом роутере en)
+func TestProblemLanguage(t *testing.T) {
+	// preparing
+	router := newTestRouter(t)
+	tests := []struct {
+		acceptLanguage string
+		language       string
+		title          string
+	}{
+		{"ru-RU,ru;q=0.9,en;q=0.8", "ru", "Ресурс не найден"},
+		{"en-GB", "en", "Resource not found"},
+		{"de", "en", "Resource not found"},
+	}
+
+	for _, tt := range tests {
+		// test
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/nope", nil)
+		r.Header.Set("Accept-Language", tt.acceptLanguage)
+		router.ServeHTTP(w, r)
+
+		// assert
+		var p problem.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &p)
+		if p.Title != tt.title {
+			t.Errorf("%v: expected title %v, got - %v", tt.acceptLanguage, tt.title, p.Title)
+		}
+		if language := w.Header().Get("Content-Language"); language != tt.language {
+			t.Errorf("%v: expected Content-Language %v, got - %v", tt.acceptLanguage, tt.language, language)
+		}
+		if vary := w.Header().Values("Vary"); len(vary) == 0 || vary[0] != "Accept-Language" {
+			t.Errorf("%v: expected Vary: Accept-Language, got - %v", tt.acceptLanguage, vary)
+		}
+	}
+}
+
+// Тест shutdown - зависший HTTP запрос не мешает остановить admin листенер, ошибка HTTP возвращается
+func TestShutdownStopsAllListeners(t *testing.T) {
+	// preparing
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	adminLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen - %v", err)
+	}
+	adminServer := &http.Server{Handler: http.NotFoundHandler()}
+	adminDone := make(chan error, 1)
+	go func() { adminDone <- adminServer.Serve(adminLn) }()
+	s := &Server{
+		server:            httpServer.Config,
+		adminServer:       adminServer,
+		timeoutToShutdown: 50 * time.Millisecond,
+		healthService:     usecase.NewHealthService(nil, 0, time.Second),
+		log:               logrus.New(),
+	}
+	go func() { _, _ = http.Get(httpServer.URL) }()
+	<-started
+
+	// test
+	err = s.shutdown(false)
+
+	// assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got - %v", context.DeadlineExceeded, err)
+	}
+	select {
+	case adminErr := <-adminDone:
+		if !errors.Is(adminErr, http.ErrServerClosed) {
+			t.Errorf("expected admin server to be closed, got - %v", adminErr)
+		}
+	case <-time.After(time.Second):
+		t.Error("admin server is still serving")
+	}
+}
+
+// Тест - паника в хендлере попадает в метрики запросов с кодом 500
+func TestPanicCountedInMetrics(t *testing.T) {
+	// preparing
+	appMetrics := metrics.New(nil)
+	router := newTestRouterWithMetrics(t, appMetrics)
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	// test
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	// assert
+	w := httptest.NewRecorder()
+	appMetrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	expected := `chopper_http_requests_total{method="GET",route="/panic",status="500"} 1`
+	if !strings.Contains(w.Body.String(), expected) {
+		t.Errorf("expected %v in metrics", expected)
+	}
+}
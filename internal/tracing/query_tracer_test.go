@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Тест QueryTracer - спан на запрос с операцией в имени, ошибка запроса пишется в статус
+func TestQueryTracer(t *testing.T) {
+	// preparing
+	exporter := tracetest.NewInMemoryExporter()
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previousProvider)
+	queryTracer := NewQueryTracer()
+
+	// test
+	ctx := queryTracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "\n\tselect * from DailyNotes where user_id = $1"})
+	queryTracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+
+	// assert
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got - %v", len(spans))
+	}
+	if spans[0].Name != "db SELECT" {
+		t.Errorf("expected span name was - %v", "db SELECT")
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected error status")
+	}
+}
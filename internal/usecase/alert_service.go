@@ -2,35 +2,97 @@ package usecase
 
 import (
 	"chopper/internal/domain"
+	"chopper/internal/logger"
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
+// правила алертов - используются как label в метриках
+const (
+	alertRuleMoodSleep = "mood_sleep"
+	alertRuleMoodLoad  = "mood_load"
+	alertRuleSleepLoad = "sleep_load"
+)
+
+var alertMessages = map[string]domain.MessageKey{
+	alertRuleMoodSleep: domain.MessageAlertMoodSleep,
+	alertRuleMoodLoad:  domain.MessageAlertMoodLoad,
+	alertRuleSleepLoad: domain.MessageAlertSleepLoad,
+}
+
 type AlertService struct {
 	alertRepository AlertRepository
+	rules           atomic.Pointer[domain.AlertConfig]
+	metrics         MetricsRecorder
 }
 
-func NewAlertServcie(alertRepository AlertRepository) *AlertService {
-	return &AlertService{
+func NewAlertServcie(alertRepository AlertRepository, rules domain.AlertConfig, metrics MetricsRecorder) *AlertService {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	alertService := &AlertService{
 		alertRepository: alertRepository,
+		metrics:         metrics,
 	}
+	alertService.rules.Store(&rules)
+	return alertService
 }
 
-func (a *AlertService) GetLastSevenDays(ctx context.Context, userId uuid.UUID) (string, error) {
-	notes, err := a.alertRepository.GetLastSevenDays(ctx, userId)
+// SetRules атомарно подменяет пороги алертов при перезагрузке конфига
+func (a *AlertService) SetRules(rules domain.AlertConfig) {
+	a.rules.Store(&rules)
+}
+
+// GetLastSevenDays возвращает ключ сообщения, текст на языке запроса подставляет хендлер.
+// Чтение алерта в метриках не считается, клиенты могут опрашивать его сколько угодно
+func (a *AlertService) GetLastSevenDays(ctx context.Context, userId uuid.UUID) (domain.MessageKey, error) {
+	ctx, span := startSpan(ctx, "AlertService.GetLastSevenDays")
+	defer span.End()
+	return a.Evaluate(ctx, userId)
+}
+
+// AlertRaised - результат анализа сменился на сработавший алерт key (alert.raised в outbox): лог и метрика по правилу.
+// Возврат к alert.ok не считается
+func (a *AlertService) AlertRaised(ctx context.Context, userId uuid.UUID, key domain.MessageKey) {
+	for rule, message := range alertMessages {
+		if message == key {
+			logger.FromContext(ctx).WithFields(logrus.Fields{"user_id": userId, "rule": rule}).Info("alert raised")
+			a.metrics.AlertTriggered(rule)
+			return
+		}
+	}
+}
+
+// Evaluate - результат анализа последних дней: проверка после каждого изменения записей и ответ GetLastSevenDays
+func (a *AlertService) Evaluate(ctx context.Context, userId uuid.UUID) (domain.MessageKey, error) {
+	ctx, span := startSpan(ctx, "AlertService.Evaluate")
+	defer span.End()
+	rule, err := a.evaluate(ctx, userId)
 	if err != nil {
 		return "", err
 	}
-	alert, ok := isAlert(notes)
-	if ok {
-		return alert, nil
+	if rule != "" {
+		return alertMessages[rule], nil
+	}
+	return domain.MessageAlertOk, nil
+}
+
+// evaluate возвращает сработавшее правило или ""
+func (a *AlertService) evaluate(ctx context.Context, userId uuid.UUID) (string, error) {
+	notes, err := a.alertRepository.GetLastSevenDays(ctx, userId)
+	if err != nil {
+		return "", err
 	}
-	return "Все хорошо", nil
+	rule, _ := isAlert(notes, *a.rules.Load())
+	return rule, nil
 }
 
-func isAlert(days []domain.Day) (string, bool) {
+// isAlert возвращает сработавшее правило
+func isAlert(days []domain.Day, rules domain.AlertConfig) (string, bool) {
 	if len(days) < 3 {
 		return "", false
 	}
@@ -46,23 +108,23 @@ func isAlert(days []domain.Day) (string, bool) {
 		moodOne := zero.Mood
 		moodTwo := one.Mood
 		moodThree := two.Mood
-		badMood := isMoodBad(moodOne, moodTwo, moodThree)
+		badMood := isMoodBad(moodOne, moodTwo, moodThree, rules.LowMood)
 		sleepHoursOne := zero.SleepHours
 		sleepHoursTwo := one.SleepHours
 		sleepHoursThird := two.SleepHours
-		lowSleepHours := isSleepHoursLow(sleepHoursOne, sleepHoursTwo, sleepHoursThird)
+		lowSleepHours := isSleepHoursLow(sleepHoursOne, sleepHoursTwo, sleepHoursThird, rules.LowSleepHours)
 		loadOne := zero.Load
 		loadTwo := one.Load
 		loadThree := two.Load
-		highLoad := isLoadHigh(loadOne, loadTwo, loadThree)
+		highLoad := isLoadHigh(loadOne, loadTwo, loadThree, rules.HighLoad)
 		if badMood && lowSleepHours {
-			return "За последние дни низкий уровень настроения и мало сна", true
+			return alertRuleMoodSleep, true
 		}
 		if badMood && highLoad {
-			return "За последние дни низкий уровень настроения и большая загрузка", true
+			return alertRuleMoodLoad, true
 		}
 		if lowSleepHours && highLoad {
-			return "За последние дни мало сна и большая загрузка", true
+			return alertRuleSleepLoad, true
 		}
 	}
 	return "", false
@@ -77,24 +139,24 @@ func isNextDay(one, two time.Time) bool {
 	return false
 }
 
-func isMoodBad(moodOne, moodTwo, moodThree int16) bool {
-	if moodOne <= 5 && moodTwo <= 5 {
+func isMoodBad(moodOne, moodTwo, moodThree, lowMood int16) bool {
+	if moodOne <= lowMood && moodTwo <= lowMood {
 		return true
 	}
-	if moodOne <= 5 && moodThree <= 5 {
+	if moodOne <= lowMood && moodThree <= lowMood {
 		return true
 	}
-	if moodTwo <= 5 && moodThree <= 5 {
+	if moodTwo <= lowMood && moodThree <= lowMood {
 		return true
 	}
 	return false
 }
 
-func isSleepHoursLow(sleepHoursOne, sleepHoursTwo, sleepHoursThree float64) bool {
+func isSleepHoursLow(sleepHoursOne, sleepHoursTwo, sleepHoursThree, lowSleepHours float64) bool {
 	hours := []float64{sleepHoursOne, sleepHoursTwo, sleepHoursThree}
 	bad := 0
 	for _, hour := range hours {
-		if hour <= 7.0 {
+		if hour <= lowSleepHours {
 			bad++
 		}
 	}
@@ -104,11 +166,11 @@ func isSleepHoursLow(sleepHoursOne, sleepHoursTwo, sleepHoursThree float64) bool
 	return false
 }
 
-func isLoadHigh(loadOne, loadTwo, loadThree int16) bool {
+func isLoadHigh(loadOne, loadTwo, loadThree, highLoad int16) bool {
 	loads := []int16{loadOne, loadTwo, loadThree}
 	bad := 0
 	for _, load := range loads {
-		if load >= 5 {
+		if load >= highLoad {
 			bad++
 		}
 	}
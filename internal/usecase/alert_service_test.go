@@ -10,6 +10,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// пороги по умолчанию из конфига
+var testAlertRules = domain.AlertConfig{LowMood: 5, LowSleepHours: 7, HighLoad: 5}
+
 type MockAlertRepository struct {
 	GetLastSevenDaysFn func(ctx context.Context, userId uuid.UUID) ([]domain.Day, error)
 	// переданные аргументы
@@ -85,7 +88,7 @@ func TestGetLastSevenDaysSuccess(t *testing.T) {
 		ctx                 context.Context
 		userId              uuid.UUID
 		mockAlertRepository *MockAlertRepository
-		expectedResponse    string
+		expectedResponse    domain.MessageKey
 		expectedError       error
 		expectedIsCalled    bool
 		expectedUserId      uuid.UUID
@@ -95,7 +98,7 @@ func TestGetLastSevenDaysSuccess(t *testing.T) {
 			ctx:                 ctx,
 			userId:              userId,
 			mockAlertRepository: mockAlertRepositoryAlert,
-			expectedResponse:    "За последние дни низкий уровень настроения и большая загрузка",
+			expectedResponse:    domain.MessageAlertMoodLoad,
 			expectedError:       nil,
 			expectedIsCalled:    true,
 			expectedUserId:      userId,
@@ -105,7 +108,7 @@ func TestGetLastSevenDaysSuccess(t *testing.T) {
 			ctx:                 ctx,
 			userId:              userId,
 			mockAlertRepository: mockAlertRepositoryNotAlert,
-			expectedResponse:    "Все хорошо",
+			expectedResponse:    domain.MessageAlertOk,
 			expectedError:       nil,
 			expectedIsCalled:    true,
 			expectedUserId:      userId,
@@ -115,7 +118,7 @@ func TestGetLastSevenDaysSuccess(t *testing.T) {
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			alertService := NewAlertServcie(test.mockAlertRepository)
+			alertService := NewAlertServcie(test.mockAlertRepository, testAlertRules, nil)
 			response, err := alertService.GetLastSevenDays(test.ctx, test.userId)
 			if !errors.Is(err, test.expectedError) {
 				t.Errorf("expected error was - %v", test.expectedError)
@@ -143,7 +146,7 @@ func TestGetLastSevenDaysErr(t *testing.T) {
 		},
 	}
 	ctx, userId := context.Background(), uuid.MustParse("11111111-1111-1111-1111-111111111111")
-	alertService := NewAlertServcie(mockAlertRepository)
+	alertService := NewAlertServcie(mockAlertRepository, testAlertRules, nil)
 	expectedError := needError
 
 	// test
@@ -207,7 +210,7 @@ func TestIsMoodBad(t *testing.T) {
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			ok := isMoodBad(test.moodOne, test.moodTwo, test.moodThree)
+			ok := isMoodBad(test.moodOne, test.moodTwo, test.moodThree, testAlertRules.LowMood)
 			if ok != test.expectedOk {
 				t.Errorf("expected ok was - %v", test.expectedOk)
 			}
@@ -258,7 +261,7 @@ func TestIsSleepHoursLow(t *testing.T) {
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			ok := isSleepHoursLow(test.sleepHoursOne, test.sleepHoursTwo, test.sleepHoursThree)
+			ok := isSleepHoursLow(test.sleepHoursOne, test.sleepHoursTwo, test.sleepHoursThree, testAlertRules.LowSleepHours)
 			if ok != test.expectedOk {
 				t.Errorf("expected ok was - %v", test.expectedOk)
 			}
@@ -309,7 +312,7 @@ func TestIsLoadHigh(t *testing.T) {
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			ok := isLoadHigh(test.loadOne, test.loadTwo, test.loadThree)
+			ok := isLoadHigh(test.loadOne, test.loadTwo, test.loadThree, testAlertRules.HighLoad)
 			if ok != test.expectedOk {
 				t.Errorf("expected ok was - %v", test.expectedOk)
 			}
@@ -0,0 +1,6 @@
+package usecase
+
+type ApiTokenGenerator interface {
+	Generate() (token string, tokenHash string, err error)
+	Hash(token string) string
+}
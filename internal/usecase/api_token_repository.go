@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApiTokenRepository interface {
+	CreateApiToken(ctx context.Context, id, userId uuid.UUID, name, tokenHash string, scopes []domain.Scope, expiresAt time.Time) error
+	GetApiTokens(ctx context.Context, userId uuid.UUID) ([]domain.ApiToken, error)
+	DeleteApiToken(ctx context.Context, id, userId uuid.UUID) error
+	GetApiTokenByHash(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error)
+	TouchApiToken(ctx context.Context, id uuid.UUID, lastUsedAt time.Time) error
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/repository"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// last_used_at обновляется не чаще раза в минуту, чтобы не писать в базу на каждый запрос
+const apiTokenTouchInterval = time.Minute
+
+type ApiTokenService struct {
+	apiTokenRepository ApiTokenRepository
+	apiTokenGenerator  ApiTokenGenerator
+	uuidGenerator      UUIDGenerator
+}
+
+func NewApiTokenService(apiTokenRepository ApiTokenRepository, apiTokenGenerator ApiTokenGenerator, uuidGenerator UUIDGenerator) *ApiTokenService {
+	return &ApiTokenService{
+		apiTokenRepository: apiTokenRepository,
+		apiTokenGenerator:  apiTokenGenerator,
+		uuidGenerator:      uuidGenerator,
+	}
+}
+
+func (a *ApiTokenService) CreateToken(ctx context.Context, userId uuid.UUID, apiTokenFromFront domain.ApiTokenFromFront) (domain.ApiTokenCreated, error) {
+	ctx, span := startSpan(ctx, "ApiTokenService.CreateToken")
+	defer span.End()
+	name := strings.TrimSpace(apiTokenFromFront.Name)
+	if name == "" || len(name) > domain.ApiTokenNameMaxLength {
+		return domain.ApiTokenCreated{}, ErrWrongApiTokenName
+	}
+	if len(apiTokenFromFront.Scopes) == 0 {
+		return domain.ApiTokenCreated{}, ErrWrongApiTokenScope
+	}
+	scopes := make([]domain.Scope, 0, len(apiTokenFromFront.Scopes))
+	seen := make(map[domain.Scope]bool)
+	for _, scope := range apiTokenFromFront.Scopes {
+		if !scope.IsValid() {
+			return domain.ApiTokenCreated{}, ErrWrongApiTokenScope
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	if apiTokenFromFront.ExpiresInDays < 1 || apiTokenFromFront.ExpiresInDays > domain.ApiTokenMaxDays {
+		return domain.ApiTokenCreated{}, ErrWrongApiTokenExpiration
+	}
+	token, tokenHash, err := a.apiTokenGenerator.Generate()
+	if err != nil {
+		return domain.ApiTokenCreated{}, err
+	}
+	id := a.uuidGenerator.NewId()
+	now := time.Now()
+	expiresAt := now.AddDate(0, 0, apiTokenFromFront.ExpiresInDays)
+	if err := a.apiTokenRepository.CreateApiToken(ctx, id, userId, name, tokenHash, scopes, expiresAt); err != nil && errors.Is(err, repository.ErrUniqueViolation) {
+		return domain.ApiTokenCreated{}, ErrApiTokenExists
+	} else if err != nil {
+		return domain.ApiTokenCreated{}, err
+	}
+	return domain.ApiTokenCreated{
+		Token: token,
+		ApiToken: domain.ApiToken{
+			Id:        id,
+			UserId:    userId,
+			Name:      name,
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+			CreatedAt: now,
+		},
+	}, nil
+}
+
+func (a *ApiTokenService) GetTokens(ctx context.Context, userId uuid.UUID) ([]domain.ApiToken, error) {
+	ctx, span := startSpan(ctx, "ApiTokenService.GetTokens")
+	defer span.End()
+	return a.apiTokenRepository.GetApiTokens(ctx, userId)
+}
+
+func (a *ApiTokenService) DeleteToken(ctx context.Context, userId, tokenId uuid.UUID) error {
+	ctx, span := startSpan(ctx, "ApiTokenService.DeleteToken")
+	defer span.End()
+	if err := a.apiTokenRepository.DeleteApiToken(ctx, tokenId, userId); err != nil && errors.Is(err, repository.ErrNoRow) {
+		return ErrApiTokenNotExists
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateToken проверяет персональный токен и возвращает владельца вместе с разрешенными scope
+func (a *ApiTokenService) ValidateToken(ctx context.Context, token string) (*domain.UserClaims, []domain.Scope, error) {
+	ctx, span := startSpan(ctx, "ApiTokenService.ValidateToken")
+	defer span.End()
+	if !strings.HasPrefix(token, domain.ApiTokenPrefix) {
+		return nil, nil, ErrInvalidApiToken
+	}
+	apiToken, claims, err := a.apiTokenRepository.GetApiTokenByHash(ctx, a.apiTokenGenerator.Hash(token))
+	if err != nil && errors.Is(err, repository.ErrNoRow) {
+		return nil, nil, ErrInvalidApiToken
+	} else if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if !apiToken.ExpiresAt.After(now) {
+		return nil, nil, ErrApiTokenExpired
+	}
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) >= apiTokenTouchInterval {
+		if err := a.apiTokenRepository.TouchApiToken(ctx, apiToken.Id, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &claims, apiToken.Scopes, nil
+}
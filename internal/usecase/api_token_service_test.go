@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Моки
+// Мок репозитория токенов
+type MockApiTokenRepository struct {
+	CreateApiTokenFn func(ctx context.Context, id, userId uuid.UUID, name, tokenHash string, scopes []domain.Scope, expiresAt time.Time) error
+	// переданные аргументы
+	createApiTokenFnIsCalled bool
+	createApiTokenName       string
+	createApiTokenHash       string
+	createApiTokenScopes     []domain.Scope
+
+	GetApiTokensFn func(ctx context.Context, userId uuid.UUID) ([]domain.ApiToken, error)
+
+	DeleteApiTokenFn func(ctx context.Context, id, userId uuid.UUID) error
+
+	GetApiTokenByHashFn func(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error)
+	// переданные аргументы
+	getApiTokenByHashHash string
+
+	TouchApiTokenFn func(ctx context.Context, id uuid.UUID, lastUsedAt time.Time) error
+	// переданные аргументы
+	touchApiTokenFnIsCalled bool
+}
+
+func (m *MockApiTokenRepository) CreateApiToken(ctx context.Context, id, userId uuid.UUID, name, tokenHash string, scopes []domain.Scope, expiresAt time.Time) error {
+	m.createApiTokenFnIsCalled = true
+	m.createApiTokenName = name
+	m.createApiTokenHash = tokenHash
+	m.createApiTokenScopes = scopes
+	if m.CreateApiTokenFn != nil {
+		return m.CreateApiTokenFn(ctx, id, userId, name, tokenHash, scopes, expiresAt)
+	}
+	return nil
+}
+
+func (m *MockApiTokenRepository) GetApiTokens(ctx context.Context, userId uuid.UUID) ([]domain.ApiToken, error) {
+	if m.GetApiTokensFn != nil {
+		return m.GetApiTokensFn(ctx, userId)
+	}
+	return nil, nil
+}
+
+func (m *MockApiTokenRepository) DeleteApiToken(ctx context.Context, id, userId uuid.UUID) error {
+	if m.DeleteApiTokenFn != nil {
+		return m.DeleteApiTokenFn(ctx, id, userId)
+	}
+	return nil
+}
+
+func (m *MockApiTokenRepository) GetApiTokenByHash(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error) {
+	m.getApiTokenByHashHash = tokenHash
+	if m.GetApiTokenByHashFn != nil {
+		return m.GetApiTokenByHashFn(ctx, tokenHash)
+	}
+	return domain.ApiToken{}, domain.UserClaims{}, nil
+}
+
+func (m *MockApiTokenRepository) TouchApiToken(ctx context.Context, id uuid.UUID, lastUsedAt time.Time) error {
+	m.touchApiTokenFnIsCalled = true
+	if m.TouchApiTokenFn != nil {
+		return m.TouchApiTokenFn(ctx, id, lastUsedAt)
+	}
+	return nil
+}
+
+// Мок генератора токенов
+type MockApiTokenGenerator struct {
+}
+
+func (m *MockApiTokenGenerator) Generate() (string, string, error) {
+	return "chp_secret", "hash:chp_secret", nil
+}
+
+func (m *MockApiTokenGenerator) Hash(token string) string {
+	return "hash:" + token
+}
+
+// Тест CreateToken - Успех (секрет отдается, а в репозиторий уходит только хэш)
+func TestCreateTokenSuccess(t *testing.T) {
+	// preparing
+	mockApiTokenRepository := &MockApiTokenRepository{}
+	service := NewApiTokenService(mockApiTokenRepository, &MockApiTokenGenerator{}, &MockUUIDGenerator{})
+	ctx, userId := context.Background(), uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	apiTokenFromFront := domain.ApiTokenFromFront{
+		Name:          " cron ",
+		Scopes:        []domain.Scope{domain.ScopeNotesWrite, domain.ScopeNotesWrite, domain.ScopeAlertsRead},
+		ExpiresInDays: 30,
+	}
+
+	// test
+	apiToken, err := service.CreateToken(ctx, userId, apiTokenFromFront)
+
+	// assert
+	if err != nil {
+		t.Errorf("error was not expected - %v", err)
+	}
+	if apiToken.Token != "chp_secret" {
+		t.Errorf("expected token was - %v", "chp_secret")
+	}
+	if mockApiTokenRepository.createApiTokenHash != "hash:chp_secret" {
+		t.Errorf("expected token hash was - %v", "hash:chp_secret")
+	}
+	if mockApiTokenRepository.createApiTokenName != "cron" {
+		t.Errorf("expected name was - %v", "cron")
+	}
+	if len(mockApiTokenRepository.createApiTokenScopes) != 2 {
+		t.Errorf("expected scopes without duplicates")
+	}
+}
+
+// Тест CreateToken - Провал (невалидные поля)
+func TestCreateTokenErrWrongFields(t *testing.T) {
+	// preparing
+	tests := []struct {
+		name              string
+		apiTokenFromFront domain.ApiTokenFromFront
+		expectedError     error
+	}{
+		{
+			name:              "empty name",
+			apiTokenFromFront: domain.ApiTokenFromFront{Name: "  ", Scopes: []domain.Scope{domain.ScopeNotesRead}, ExpiresInDays: 30},
+			expectedError:     ErrWrongApiTokenName,
+		},
+		{
+			name:              "no scopes",
+			apiTokenFromFront: domain.ApiTokenFromFront{Name: "cron", ExpiresInDays: 30},
+			expectedError:     ErrWrongApiTokenScope,
+		},
+		{
+			name:              "unknown scope",
+			apiTokenFromFront: domain.ApiTokenFromFront{Name: "cron", Scopes: []domain.Scope{"notes:delete"}, ExpiresInDays: 30},
+			expectedError:     ErrWrongApiTokenScope,
+		},
+		{
+			name:              "too long",
+			apiTokenFromFront: domain.ApiTokenFromFront{Name: "cron", Scopes: []domain.Scope{domain.ScopeNotesRead}, ExpiresInDays: 1000},
+			expectedError:     ErrWrongApiTokenExpiration,
+		},
+	}
+
+	// test + assert
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockApiTokenRepository := &MockApiTokenRepository{}
+			service := NewApiTokenService(mockApiTokenRepository, &MockApiTokenGenerator{}, &MockUUIDGenerator{})
+			_, err := service.CreateToken(context.Background(), uuid.UUID{}, test.apiTokenFromFront)
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf("expected error was - %v", test.expectedError)
+			}
+			if mockApiTokenRepository.createApiTokenFnIsCalled {
+				t.Errorf("create api token was called")
+			}
+		})
+	}
+}
+
+// Тест CreateToken - Провал (токен с таким именем уже есть)
+func TestCreateTokenErrApiTokenExists(t *testing.T) {
+	// preparing
+	mockApiTokenRepository := &MockApiTokenRepository{
+		CreateApiTokenFn: func(ctx context.Context, id, userId uuid.UUID, name, tokenHash string, scopes []domain.Scope, expiresAt time.Time) error {
+			return repository.ErrUniqueViolation
+		},
+	}
+	service := NewApiTokenService(mockApiTokenRepository, &MockApiTokenGenerator{}, &MockUUIDGenerator{})
+	apiTokenFromFront := domain.ApiTokenFromFront{Name: "cron", Scopes: []domain.Scope{domain.ScopeNotesRead}, ExpiresInDays: 30}
+
+	// test
+	_, err := service.CreateToken(context.Background(), uuid.UUID{}, apiTokenFromFront)
+
+	// assert
+	if !errors.Is(err, ErrApiTokenExists) {
+		t.Errorf("expected error was - %v", ErrApiTokenExists)
+	}
+}
+
+// Тест ValidateToken - Успех (last_used_at обновляется)
+func TestValidateTokenSuccess(t *testing.T) {
+	// preparing
+	userId := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	mockApiTokenRepository := &MockApiTokenRepository{
+		GetApiTokenByHashFn: func(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error) {
+			return domain.ApiToken{
+				UserId:    userId,
+				Scopes:    []domain.Scope{domain.ScopeNotesWrite},
+				ExpiresAt: time.Now().Add(time.Hour),
+			}, domain.UserClaims{
+				Id:       userId,
+				Username: "dexter",
+			}, nil
+		},
+	}
+	service := NewApiTokenService(mockApiTokenRepository, &MockApiTokenGenerator{}, nil)
+
+	// test
+	claims, scopes, err := service.ValidateToken(context.Background(), "chp_secret")
+
+	// assert
+	if err != nil {
+		t.Errorf("error was not expected - %v", err)
+	}
+	if mockApiTokenRepository.getApiTokenByHashHash != "hash:chp_secret" {
+		t.Errorf("token must be looked up by hash")
+	}
+	if claims.Id != userId || claims.Username != "dexter" {
+		t.Errorf("expected claims of user - %v", userId)
+	}
+	if len(scopes) != 1 || scopes[0] != domain.ScopeNotesWrite {
+		t.Errorf("expected scopes were - %v", []domain.Scope{domain.ScopeNotesWrite})
+	}
+	if !mockApiTokenRepository.touchApiTokenFnIsCalled {
+		t.Errorf("touch api token was not called")
+	}
+}
+
+// Тест ValidateToken - Провал (неизвестный, просроченный токен и JWT вместо токена)
+func TestValidateTokenErr(t *testing.T) {
+	// preparing
+	tests := []struct {
+		name          string
+		token         string
+		apiToken      domain.ApiToken
+		repoError     error
+		expectedError error
+	}{
+		{
+			name:          "not found",
+			token:         "chp_unknown",
+			repoError:     repository.ErrNoRow,
+			expectedError: ErrInvalidApiToken,
+		},
+		{
+			name:          "expired",
+			token:         "chp_secret",
+			apiToken:      domain.ApiToken{ExpiresAt: time.Now().Add(-time.Hour)},
+			expectedError: ErrApiTokenExpired,
+		},
+		{
+			name:          "no prefix",
+			token:         "eyJhbGciOiJIUzI1NiJ9",
+			expectedError: ErrInvalidApiToken,
+		},
+	}
+
+	// test + assert
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockApiTokenRepository := &MockApiTokenRepository{
+				GetApiTokenByHashFn: func(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error) {
+					return test.apiToken, domain.UserClaims{}, test.repoError
+				},
+			}
+			service := NewApiTokenService(mockApiTokenRepository, &MockApiTokenGenerator{}, nil)
+			claims, _, err := service.ValidateToken(context.Background(), test.token)
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf("expected error was - %v", test.expectedError)
+			}
+			if claims != nil {
+				t.Errorf("expected claims were nil")
+			}
+			if mockApiTokenRepository.touchApiTokenFnIsCalled {
+				t.Errorf("touch api token was called")
+			}
+		})
+	}
+}
+
+// Тест DeleteToken - Провал (токен не найден)
+func TestDeleteTokenErrApiTokenNotExists(t *testing.T) {
+	// preparing
+	mockApiTokenRepository := &MockApiTokenRepository{
+		DeleteApiTokenFn: func(ctx context.Context, id, userId uuid.UUID) error {
+			return repository.ErrNoRow
+		},
+	}
+	service := NewApiTokenService(mockApiTokenRepository, nil, nil)
+
+	// test
+	err := service.DeleteToken(context.Background(), uuid.UUID{}, uuid.UUID{})
+
+	// assert
+	if !errors.Is(err, ErrApiTokenNotExists) {
+		t.Errorf("expected error was - %v", ErrApiTokenNotExists)
+	}
+}
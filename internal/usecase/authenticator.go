@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Authenticator проверяет bearer токен одинаково для HTTP и gRPC
+type Authenticator struct {
+	jwt             JwtGenerator
+	apiTokenService *ApiTokenService
+	userService     *UserService
+}
+
+func NewAuthenticator(jwt JwtGenerator, apiTokenService *ApiTokenService, userService *UserService) *Authenticator {
+	return &Authenticator{
+		jwt:             jwt,
+		apiTokenService: apiTokenService,
+		userService:     userService,
+	}
+}
+
+// Authenticate возвращает ErrUnauthenticated с причиной, если токен не подходит; остальные ошибки - сбой проверки
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (domain.Identity, error) {
+	// персональный токен - права ограничены его scope
+	if strings.HasPrefix(token, domain.ApiTokenPrefix) {
+		claims, scopes, err := a.apiTokenService.ValidateToken(ctx, token)
+		if err != nil {
+			return domain.Identity{}, fmt.Errorf("%w: api token: %w", ErrUnauthenticated, err)
+		}
+		return domain.Identity{
+			UserId:   claims.Id,
+			Username: claims.Username,
+			ApiToken: true,
+			Scopes:   scopes,
+			Language: claims.Language,
+		}, nil
+	}
+	_, span := startSpan(ctx, "Jwt.ValidateToken")
+	claims, err := a.jwt.ValidateToken(token)
+	span.End()
+	if err != nil {
+		return domain.Identity{}, fmt.Errorf("%w: jwt: %w", ErrUnauthenticated, err)
+	}
+	// JWT живет до истечения срока, поэтому блокировку пользователя проверяем на каждый запрос
+	status, err := a.userService.CheckActive(ctx, claims.Id)
+	if err != nil && (errors.Is(err, ErrUserSuspended) || errors.Is(err, ErrUserNotExist)) {
+		return domain.Identity{}, fmt.Errorf("%w: jwt: %w", ErrUnauthenticated, err)
+	} else if err != nil {
+		return domain.Identity{}, err
+	}
+	return domain.Identity{
+		UserId:   claims.Id,
+		Username: claims.Username,
+		Language: status.Language,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Мок jwt - ValidateToken отдает заданные claims или ошибку
+type MockJwtValidator struct {
+	claims *domain.UserClaims
+	err    error
+}
+
+func (m *MockJwtValidator) GenerateToken(id uuid.UUID, username, email string, role domain.Role) (string, error) {
+	return "", nil
+}
+
+func (m *MockJwtValidator) ValidateToken(signedToken string) (*domain.UserClaims, error) {
+	return m.claims, m.err
+}
+
+// Тест Authenticate - JWT: блокировка и отсутствие пользователя - ErrUnauthenticated, сбой базы - нет
+func TestAuthenticateJwt(t *testing.T) {
+	userId := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	tests := []struct {
+		name     string
+		jwtErr   error
+		active   bool
+		repoErr  error
+		expected error
+	}{
+		{"активная сессия", nil, true, nil, nil},
+		{"невалидный jwt", errors.New("token is expired"), true, nil, ErrUnauthenticated},
+		{"заблокирован", nil, false, nil, ErrUserSuspended},
+		{"ошибка базы", nil, true, MockNeedErr, MockNeedErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// preparing
+			jwt := &MockJwtValidator{claims: &domain.UserClaims{Id: userId, Username: "dexter"}, err: tt.jwtErr}
+			userService := NewUserService(&MockUserRepositoryAdmin8{active: tt.active, language: "en", err: tt.repoErr}, nil, nil, nil, nil, nil, nil)
+			authenticator := NewAuthenticator(jwt, nil, userService)
+
+			// test
+			identity, err := authenticator.Authenticate(context.Background(), "jwt")
+
+			// assert
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("ожидалась ошибка - %v, получена - %v", tt.expected, err)
+			}
+			if errors.Is(tt.expected, ErrUserSuspended) && !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("блокировка должна быть ErrUnauthenticated, получена - %v", err)
+			}
+			if errors.Is(tt.expected, MockNeedErr) && errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("сбой базы не должен быть ErrUnauthenticated")
+			}
+			if tt.expected == nil && (identity.UserId != userId || identity.ApiToken || identity.Language != "en") {
+				t.Errorf("неожиданный identity - %+v", identity)
+			}
+		})
+	}
+}
+
+// Тест Authenticate - персональный токен ограничен своими scope, JWT разрешено все
+func TestAuthenticateApiToken(t *testing.T) {
+	// preparing
+	userId := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	mockApiTokenRepository := &MockApiTokenRepository{
+		GetApiTokenByHashFn: func(ctx context.Context, tokenHash string) (domain.ApiToken, domain.UserClaims, error) {
+			return domain.ApiToken{ExpiresAt: time.Now().Add(time.Hour), Scopes: []domain.Scope{domain.ScopeAlertsRead}},
+				domain.UserClaims{Id: userId, Username: "dexter"}, nil
+		},
+	}
+	apiTokenService := NewApiTokenService(mockApiTokenRepository, &MockApiTokenGenerator{}, &MockUUIDGenerator{})
+	authenticator := NewAuthenticator(nil, apiTokenService, nil)
+
+	// test
+	identity, err := authenticator.Authenticate(context.Background(), domain.ApiTokenPrefix+"secret")
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось, получена - %v", err)
+	}
+	if !identity.ApiToken || identity.UserId != userId {
+		t.Errorf("неожиданный identity - %+v", identity)
+	}
+	if !identity.Allows(domain.ScopeAlertsRead) || identity.Allows(domain.ScopeNotesWrite) {
+		t.Errorf("ожидался доступ только к %v, scopes - %v", domain.ScopeAlertsRead, identity.Scopes)
+	}
+	if !(domain.Identity{}).Allows(domain.ScopeNotesWrite) {
+		t.Errorf("JWT сессии должно быть разрешено все")
+	}
+}
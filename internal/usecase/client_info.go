@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+)
+
+type clientInfoKey struct{}
+
+// WithClientInfo кладет в ctx адрес и клиента запроса, транспорт вызывает ее перед изменением записи
+func WithClientInfo(ctx context.Context, info domain.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// clientInfo - данные клиента из ctx; без транспорта (CLI, тесты) - пустые
+func clientInfo(ctx context.Context) domain.ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(domain.ClientInfo)
+	return info
+}
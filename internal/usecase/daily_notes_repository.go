@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"chopper/internal/domain"
 	"context"
 	"time"
 
@@ -9,7 +10,13 @@ import (
 
 type DailyNotesRepository interface {
 	CreateNote(ctx context.Context, id, userId uuid.UUID, date time.Time, mood int16, sleepHours float64, load int16) error
-	ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16) error
-	ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, mood float64) error
-	ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, mood int16) error
+	GetNote(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error)
+	// GetTimeZone - часовой пояс пользователя (IANA), "" если не задан
+	GetTimeZone(ctx context.Context, userId uuid.UUID) (string, error)
+	// version - ожидаемая версия записи, 0 - без проверки. Возвращается изменение со старым значением и новой версией
+	ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16, version int) (domain.EntryChange, error)
+	ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, mood float64, version int) (domain.EntryChange, error)
+	ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, mood int16, version int) (domain.EntryChange, error)
+	// RestoreNote записывает все значения entry, если версия записи все еще entry.Version; возвращает новую версию
+	RestoreNote(ctx context.Context, userId uuid.UUID, entry domain.DailyEntry) (int, error)
 }
@@ -13,74 +13,329 @@ import (
 type DailyNotesService struct {
 	dailyNotesRepository DailyNotesRepository
 	uuidGenerator        UUIDGenerator
+	metrics              MetricsRecorder
+	transactor           Transactor
+	outbox               OutboxWriter
+	history              EntryHistoryRepository
+	now                  func() time.Time
 }
 
-func NewDailyNotesService(dailyNotesRepository DailyNotesRepository, uuidGenerator UUIDGenerator) *DailyNotesService {
+func NewDailyNotesService(dailyNotesRepository DailyNotesRepository, uuidGenerator UUIDGenerator, metrics MetricsRecorder, transactor Transactor, outbox OutboxWriter, history EntryHistoryRepository) *DailyNotesService {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	if transactor == nil {
+		transactor = noopTransactor{}
+	}
+	if outbox == nil {
+		outbox = noopOutbox{}
+	}
+	if history == nil {
+		history = noopEntryHistory{}
+	}
 	return &DailyNotesService{
 		dailyNotesRepository: dailyNotesRepository,
 		uuidGenerator:        uuidGenerator,
+		metrics:              metrics,
+		transactor:           transactor,
+		outbox:               outbox,
+		history:              history,
+		now:                  time.Now,
 	}
 }
 
 func (d *DailyNotesService) CreateNote(ctx context.Context, userId uuid.UUID, dailyNoteFromFront domain.DailyNoteFromFront) error {
+	ctx, span := startSpan(ctx, "DailyNotesService.CreateNote")
+	defer span.End()
 	id := d.uuidGenerator.NewId()
-	now := time.Now()
-	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	mood := dailyNoteFromFront.Mood
-	if mood < 0 || mood > 10 {
+	mood, sleepHours, load := dailyNoteFromFront.Mood, dailyNoteFromFront.SleepHours, dailyNoteFromFront.Load
+	if !domain.ValidMood(mood) {
 		return ErrWrongMoodValue
 	}
-	sleepHours := dailyNoteFromFront.SleepHours
-	if sleepHours > 9.9 || sleepHours < 0.0 {
+	if !domain.ValidSleepHours(sleepHours) {
 		return ErrWrongSleepHourValue
 	}
-	load := dailyNoteFromFront.Load
-	if load < 0 || load > 10 {
+	if !domain.ValidLoad(load) {
 		return ErrWrongLoadValue
 	}
-	if err := d.dailyNotesRepository.CreateNote(ctx, id, userId, date, mood, sleepHours, load); err != nil && errors.Is(err, repository.ErrUniqueViolation) {
+	date, err := d.today(ctx, userId)
+	if err != nil {
+		return err
+	}
+	event, err := newDomainEvent(domain.DomainEventEntryCreated, userId, domain.UserEventPayload{
+		Date:       date.Format(time.DateOnly),
+		Mood:       &mood,
+		SleepHours: &sleepHours,
+		Load:       &load,
+	})
+	if err != nil {
+		return err
+	}
+	created := domain.DailyEntry{Id: id, Mood: mood, SleepHours: sleepHours, Load: load, Version: domain.EntryFirstVersion}
+	changes := entryChanges(ctx, userId, domain.DailyEntry{}, created, domain.EntryChangeCreated)
+	err = d.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := d.dailyNotesRepository.CreateNote(ctx, id, userId, date, mood, sleepHours, load); err != nil {
+			return err
+		}
+		if err := d.history.AddEntryChanges(ctx, changes...); err != nil {
+			return err
+		}
+		return d.outbox.AddOutboxEvents(ctx, event)
+	})
+	if err != nil && errors.Is(err, repository.ErrUniqueViolation) {
 		return ErrNoteAlreadyExists
 	} else if err != nil {
 		return err
 	}
+	d.metrics.EntryCreated()
 	return nil
 }
 
-func (d *DailyNotesService) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16) (string, error) {
-	if mood < 0 || mood > 10 {
-		return "", ErrWrongMoodValue
+// today - текущая дата в часовом поясе пользователя, тот же день, что считает планировщик напоминаний;
+// без пояса в настройках - дата сервера
+func (d *DailyNotesService) today(ctx context.Context, userId uuid.UUID) (time.Time, error) {
+	timeZone, err := d.dailyNotesRepository.GetTimeZone(ctx, userId)
+	if err != nil {
+		return time.Time{}, err
+	}
+	location := time.Local
+	if timeZone != "" {
+		if userLocation, err := time.LoadLocation(timeZone); err == nil {
+			location = userLocation
+		}
+	}
+	now := d.now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location), nil
+}
+
+// GetNote - запись за date вместе с версией для ETag
+func (d *DailyNotesService) GetNote(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error) {
+	ctx, span := startSpan(ctx, "DailyNotesService.GetNote")
+	defer span.End()
+	entry, err := d.dailyNotesRepository.GetNote(ctx, userId, date)
+	if err != nil && errors.Is(err, repository.ErrDailyEntryNotFound) {
+		return domain.DailyEntry{}, ErrNoteNotExists
+	}
+	return entry, err
+}
+
+// GetNoteHistory - все изменения записи за date по возрастанию версии
+func (d *DailyNotesService) GetNoteHistory(ctx context.Context, userId uuid.UUID, date time.Time) ([]domain.EntryChange, error) {
+	ctx, span := startSpan(ctx, "DailyNotesService.GetNoteHistory")
+	defer span.End()
+	entry, err := d.GetNote(ctx, userId, date)
+	if err != nil {
+		return nil, err
+	}
+	return d.history.GetEntryHistory(ctx, entry.Id)
+}
+
+// ChangeMood меняет настроение, если запись не менялась с версии version (0 - без проверки), и возвращает новую версию
+func (d *DailyNotesService) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16, version int) (domain.MessageKey, int, error) {
+	ctx, span := startSpan(ctx, "DailyNotesService.ChangeMood")
+	defer span.End()
+	if !domain.ValidMood(mood) {
+		return "", 0, ErrWrongMoodValue
+	}
+	change := func(ctx context.Context) (domain.EntryChange, error) {
+		return d.dailyNotesRepository.ChangeMood(ctx, userId, date, mood, version)
+	}
+	newVersion, err := d.changeEntry(ctx, userId, change, domain.UserEventPayload{Date: date.Format(time.DateOnly), Mood: &mood})
+	if err != nil {
+		return "", 0, err
+	}
+	return domain.MessageMoodChanged, newVersion, nil
+}
+
+func (d *DailyNotesService) ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64, version int) (domain.MessageKey, int, error) {
+	ctx, span := startSpan(ctx, "DailyNotesService.ChangeSleepHours")
+	defer span.End()
+	if !domain.ValidSleepHours(sleepHours) {
+		return "", 0, ErrWrongSleepHourValue
+	}
+	change := func(ctx context.Context) (domain.EntryChange, error) {
+		return d.dailyNotesRepository.ChangeSleepHours(ctx, userId, date, sleepHours, version)
+	}
+	newVersion, err := d.changeEntry(ctx, userId, change, domain.UserEventPayload{Date: date.Format(time.DateOnly), SleepHours: &sleepHours})
+	if err != nil {
+		return "", 0, err
+	}
+	return domain.MessageSleepHoursChanged, newVersion, nil
+}
+
+func (d *DailyNotesService) ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, load int16, version int) (domain.MessageKey, int, error) {
+	ctx, span := startSpan(ctx, "DailyNotesService.ChangeLoad")
+	defer span.End()
+	if !domain.ValidLoad(load) {
+		return "", 0, ErrWrongLoadValue
+	}
+	change := func(ctx context.Context) (domain.EntryChange, error) {
+		return d.dailyNotesRepository.ChangeLoad(ctx, userId, date, load, version)
+	}
+	newVersion, err := d.changeEntry(ctx, userId, change, domain.UserEventPayload{Date: date.Format(time.DateOnly), Load: &load})
+	if err != nil {
+		return "", 0, err
+	}
+	return domain.MessageLoadChanged, newVersion, nil
+}
+
+// RevertNote возвращает запись к значениям версии toVersion новой версией, история при этом не теряется.
+// version - ожидаемая текущая версия, 0 - без проверки. Если значения уже совпадают, запись не меняется
+func (d *DailyNotesService) RevertNote(ctx context.Context, userId uuid.UUID, date time.Time, toVersion, version int) (domain.MessageKey, int, error) {
+	ctx, span := startSpan(ctx, "DailyNotesService.RevertNote")
+	defer span.End()
+	newVersion := 0
+	err := d.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := d.dailyNotesRepository.GetNote(ctx, userId, date)
+		if err != nil {
+			return err
+		}
+		if version != 0 && current.Version != version {
+			return repository.ErrDailyEntryVersionMismatch
+		}
+		history, err := d.history.GetEntryHistory(ctx, current.Id)
+		if err != nil {
+			return err
+		}
+		target, ok := entryAtVersion(current, history, toVersion)
+		if !ok {
+			return ErrNoteVersionNotExists
+		}
+		newVersion = current.Version
+		changes := entryChanges(ctx, userId, current, target, domain.EntryChangeReverted)
+		if len(changes) == 0 {
+			return nil
+		}
+		// значения target, проверка - по текущей версии
+		restore := target
+		restore.Version = current.Version
+		if newVersion, err = d.dailyNotesRepository.RestoreNote(ctx, userId, restore); err != nil {
+			return err
+		}
+		payload := domain.UserEventPayload{Date: date.Format(time.DateOnly)}
+		for i := range changes {
+			changes[i].Version = newVersion
+			switch changes[i].Field {
+			case domain.EntryFieldMood:
+				payload.Mood = &target.Mood
+			case domain.EntryFieldSleepHours:
+				payload.SleepHours = &target.SleepHours
+			case domain.EntryFieldLoad:
+				payload.Load = &target.Load
+			}
+		}
+		if err := d.history.AddEntryChanges(ctx, changes...); err != nil {
+			return err
+		}
+		event, err := newDomainEvent(domain.DomainEventEntryChanged, userId, payload)
+		if err != nil {
+			return err
+		}
+		return d.outbox.AddOutboxEvents(ctx, event)
+	})
+	if err := noteError(err); err != nil {
+		return "", 0, err
+	}
+	return domain.MessageNoteReverted, newVersion, nil
+}
+
+// changeEntry применяет change, пишет его в историю и entry.changed в outbox одной транзакцией
+func (d *DailyNotesService) changeEntry(ctx context.Context, userId uuid.UUID, change func(ctx context.Context) (domain.EntryChange, error), payload domain.UserEventPayload) (int, error) {
+	event, err := newDomainEvent(domain.DomainEventEntryChanged, userId, payload)
+	if err != nil {
+		return 0, err
 	}
-	if err := d.dailyNotesRepository.ChangeMood(ctx, userId, date, mood); err != nil {
-		if errors.Is(err, repository.ErrDailyEntryNotFound) {
-			return "", ErrNoteNotExists
+	var newVersion int
+	err = d.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		entryChange, err := change(ctx)
+		if err != nil {
+			return err
+		}
+		newVersion = entryChange.Version
+		entryChange.UserId = userId
+		entryChange.Reason = domain.EntryChangeEdited
+		info := clientInfo(ctx)
+		entryChange.ClientIP, entryChange.UserAgent = info.IP, info.UserAgent
+		if err := d.history.AddEntryChanges(ctx, entryChange); err != nil {
+			return err
 		}
-		return "", err
+		return d.outbox.AddOutboxEvents(ctx, event)
+	})
+	if err := noteError(err); err != nil {
+		return 0, err
 	}
-	return "mood успешно изменен", nil
+	return newVersion, nil
 }
 
-func (d *DailyNotesService) ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64) (string, error) {
-	if sleepHours > 9.9 || sleepHours < 0.0 {
-		return "", ErrWrongSleepHourValue
+// noteError переводит ошибки репозитория записей в ошибки usecase
+func noteError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrDailyEntryNotFound):
+		return ErrNoteNotExists
+	case errors.Is(err, repository.ErrDailyEntryVersionMismatch):
+		return ErrNoteVersionMismatch
 	}
-	if err := d.dailyNotesRepository.ChangeSleepHours(ctx, userId, date, sleepHours); err != nil {
-		if errors.Is(err, repository.ErrDailyEntryNotFound) {
-			return "", ErrNoteNotExists
+	return err
+}
+
+// entryChanges - изменения полей от from к to; у from без версии (создание) старых значений нет
+func entryChanges(ctx context.Context, userId uuid.UUID, from, to domain.DailyEntry, reason domain.EntryChangeReason) []domain.EntryChange {
+	info := clientInfo(ctx)
+	changes := []domain.EntryChange{}
+	add := func(field domain.EntryField, oldValue, newValue float64) {
+		if from.Version != 0 && oldValue == newValue {
+			return
+		}
+		change := domain.EntryChange{
+			EntryId:   to.Id,
+			UserId:    userId,
+			Version:   to.Version,
+			Field:     field,
+			NewValue:  newValue,
+			Reason:    reason,
+			ClientIP:  info.IP,
+			UserAgent: info.UserAgent,
+		}
+		if from.Version != 0 {
+			change.OldValue = &oldValue
 		}
-		return "", err
+		changes = append(changes, change)
 	}
-	return "sleep hours успешно изменен", nil
+	add(domain.EntryFieldMood, float64(from.Mood), float64(to.Mood))
+	add(domain.EntryFieldSleepHours, from.SleepHours, to.SleepHours)
+	add(domain.EntryFieldLoad, float64(from.Load), float64(to.Load))
+	return changes
 }
 
-func (d *DailyNotesService) ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, load int16) (string, error) {
-	if load < 0 || load > 10 {
-		return "", ErrWrongLoadValue
+// entryAtVersion восстанавливает значения записи на версии version, откатывая изменения после нее
+// от последнего к первому. Если история после version неполная (записи старше истории), ok == false
+func entryAtVersion(current domain.DailyEntry, history []domain.EntryChange, version int) (domain.DailyEntry, bool) {
+	if version < domain.EntryFirstVersion || version > current.Version {
+		return domain.DailyEntry{}, false
 	}
-	if err := d.dailyNotesRepository.ChangeLoad(ctx, userId, date, load); err != nil {
-		if errors.Is(err, repository.ErrDailyEntryNotFound) {
-			return "", ErrNoteNotExists
+	versions := map[int]struct{}{}
+	entry := current
+	for i := len(history) - 1; i >= 0; i-- {
+		change := history[i]
+		if change.Version <= version {
+			continue
 		}
-		return "", err
+		if change.OldValue == nil {
+			return domain.DailyEntry{}, false
+		}
+		versions[change.Version] = struct{}{}
+		switch change.Field {
+		case domain.EntryFieldMood:
+			entry.Mood = int16(*change.OldValue)
+		case domain.EntryFieldSleepHours:
+			entry.SleepHours = *change.OldValue
+		case domain.EntryFieldLoad:
+			entry.Load = int16(*change.OldValue)
+		}
+	}
+	if len(versions) != current.Version-version {
+		return domain.DailyEntry{}, false
 	}
-	return "load успешно изменен", nil
+	entry.Version = version
+	return entry, true
 }
@@ -4,8 +4,10 @@ import (
 	"chopper/internal/domain"
 	"chopper/internal/repository"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +33,7 @@ type MockDailyNotesRepository struct {
 	changeMoodUserId     uuid.UUID
 	changeMoodDate       time.Time
 	changeMoodMood       int16
+	changeMoodVersion    int
 
 	ChangeSleepHoursFn func(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64) error
 	//переданные аргументы
@@ -38,6 +41,7 @@ type MockDailyNotesRepository struct {
 	changeSleepHoursUserId     uuid.UUID
 	changeSleepHoursDate       time.Time
 	changeSleepHoursSleepHours float64
+	changeSleepHoursVersion    int
 
 	ChangeLoadFn func(ctx context.Context, userId uuid.UUID, date time.Time, load int16) error
 	// переданные аргументы
@@ -45,6 +49,16 @@ type MockDailyNotesRepository struct {
 	changeLoadUserId     uuid.UUID
 	changeLoadDate       time.Time
 	changeLoadLoad       int16
+	changeLoadVersion    int
+
+	GetNoteFn func(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error)
+
+	// часовой пояс пользователя, "" - не задан
+	timeZone string
+
+	RestoreNoteFn func(ctx context.Context, userId uuid.UUID, entry domain.DailyEntry) (int, error)
+	// переданные аргументы
+	restoreNoteEntry domain.DailyEntry
 }
 
 func (m *MockDailyNotesRepository) CreateNote(ctx context.Context, id, userId uuid.UUID, date time.Time, mood int16, sleepHours float64, load int16) error {
@@ -61,39 +75,86 @@ func (m *MockDailyNotesRepository) CreateNote(ctx context.Context, id, userId uu
 	return nil
 }
 
-func (m *MockDailyNotesRepository) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16) error {
+func (m *MockDailyNotesRepository) GetTimeZone(ctx context.Context, userId uuid.UUID) (string, error) {
+	return m.timeZone, nil
+}
+
+// ChangeMood - при успехе версия растет на 1, как в базе
+func (m *MockDailyNotesRepository) ChangeMood(ctx context.Context, userId uuid.UUID, date time.Time, mood int16, version int) (domain.EntryChange, error) {
 	m.changeMoodFnIsCalled = true
 	m.changeMoodUserId = userId
 	m.changeMoodDate = date
 	m.changeMoodMood = mood
+	m.changeMoodVersion = version
 	if m.ChangeMoodFn != nil {
-		return m.ChangeMoodFn(ctx, userId, date, mood)
+		if err := m.ChangeMoodFn(ctx, userId, date, mood); err != nil {
+			return domain.EntryChange{}, err
+		}
 	}
-	return nil
+	return domain.EntryChange{Version: version + 1}, nil
 }
 
-func (m *MockDailyNotesRepository) ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64) error {
+// ChangeSleepHours - при успехе версия растет на 1, как в базе
+func (m *MockDailyNotesRepository) ChangeSleepHours(ctx context.Context, userId uuid.UUID, date time.Time, sleepHours float64, version int) (domain.EntryChange, error) {
 	m.changeSleepHoursFnIsCalled = true
 	m.changeSleepHoursUserId = userId
 	m.changeSleepHoursDate = date
 	m.changeSleepHoursSleepHours = sleepHours
+	m.changeSleepHoursVersion = version
 	if m.ChangeSleepHoursFn != nil {
-		return m.ChangeSleepHoursFn(ctx, userId, date, sleepHours)
+		if err := m.ChangeSleepHoursFn(ctx, userId, date, sleepHours); err != nil {
+			return domain.EntryChange{}, err
+		}
 	}
-	return nil
+	return domain.EntryChange{Version: version + 1}, nil
 }
 
-func (m *MockDailyNotesRepository) ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, load int16) error {
+// ChangeLoad - при успехе версия растет на 1, как в базе
+func (m *MockDailyNotesRepository) ChangeLoad(ctx context.Context, userId uuid.UUID, date time.Time, load int16, version int) (domain.EntryChange, error) {
 	m.changeLoadFnIsCalled = true
 	m.changeLoadUserId = userId
 	m.changeLoadDate = date
 	m.changeLoadLoad = load
+	m.changeLoadVersion = version
 	if m.ChangeLoadFn != nil {
-		return m.ChangeLoadFn(ctx, userId, date, load)
+		if err := m.ChangeLoadFn(ctx, userId, date, load); err != nil {
+			return domain.EntryChange{}, err
+		}
 	}
+	return domain.EntryChange{Version: version + 1}, nil
+}
+
+func (m *MockDailyNotesRepository) GetNote(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error) {
+	if m.GetNoteFn != nil {
+		return m.GetNoteFn(ctx, userId, date)
+	}
+	return domain.DailyEntry{}, nil
+}
+
+// RestoreNote - при успехе версия растет на 1, как в базе
+func (m *MockDailyNotesRepository) RestoreNote(ctx context.Context, userId uuid.UUID, entry domain.DailyEntry) (int, error) {
+	m.restoreNoteEntry = entry
+	if m.RestoreNoteFn != nil {
+		return m.RestoreNoteFn(ctx, userId, entry)
+	}
+	return entry.Version + 1, nil
+}
+
+// Мок истории изменений - отдает history и запоминает добавленные изменения
+type MockEntryHistoryRepository struct {
+	history []domain.EntryChange
+	added   []domain.EntryChange
+}
+
+func (m *MockEntryHistoryRepository) AddEntryChanges(ctx context.Context, changes ...domain.EntryChange) error {
+	m.added = append(m.added, changes...)
 	return nil
 }
 
+func (m *MockEntryHistoryRepository) GetEntryHistory(ctx context.Context, entryId uuid.UUID) ([]domain.EntryChange, error) {
+	return m.history, nil
+}
+
 // Мок генератора uuid
 type MockUUIDGenerator struct {
 	NewIdFn  func() uuid.UUID
@@ -124,7 +185,7 @@ func TestCreateNoteSuccess(t *testing.T) {
 			return uuid.MustParse("11111111-1111-1111-1111-111111111111")
 		},
 	}
-	dailyNotesSevice := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator)
+	dailyNotesSevice := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator, nil, nil, nil, nil)
 	ctx := context.Background()
 	userId := uuid.MustParse("22222222-2222-2222-2222-222222222222")
 	dailyNoteFromFront := domain.DailyNoteFromFront{
@@ -175,6 +236,31 @@ func TestCreateNoteSuccess(t *testing.T) {
 	}
 }
 
+// Тест CreateNote - дата записи по часовому поясу пользователя (UTC+10), а не сервера (UTC)
+func TestCreateNoteUserTimeZone(t *testing.T) {
+	// preparing
+	mockDailyNotesRepository := &MockDailyNotesRepository{timeZone: "Australia/Brisbane"}
+	mockOutbox := &MockOutbox{}
+	dailyNotesSevice := NewDailyNotesService(mockDailyNotesRepository, &MockUUIDGenerator{}, nil, nil, mockOutbox, nil)
+	dailyNotesSevice.now = func() time.Time { return time.Date(2026, 10, 19, 20, 30, 0, 0, time.UTC) }
+	userId := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	expectedDate := "2026-10-20"
+
+	// test
+	err := dailyNotesSevice.CreateNote(context.Background(), userId, domain.DailyNoteFromFront{Mood: 5, SleepHours: 8, Load: 5})
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if date := mockDailyNotesRepository.createNoteDate.Format(time.DateOnly); date != expectedDate {
+		t.Errorf("ожидалась дата %v, получена - %v", expectedDate, date)
+	}
+	if len(mockOutbox.events) != 1 || !strings.Contains(string(mockOutbox.events[0].Payload), expectedDate) {
+		t.Errorf("ожидалось событие с датой %v, получено - %v", expectedDate, mockOutbox.events)
+	}
+}
+
 // Тест CreateNote - Провал (Невалидный mood)
 func TestCreateNoteFailureInvalidMood(t *testing.T) {
 	// preparing
@@ -188,7 +274,7 @@ func TestCreateNoteFailureInvalidMood(t *testing.T) {
 			return uuid.MustParse("11111111-1111-1111-1111-111111111111")
 		},
 	}
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator, nil, nil, nil, nil)
 	ctx := context.Background()
 	userId := uuid.MustParse("22222222-2222-2222-2222-222222222222")
 	dailyNoteFromFrontNegativeMood := domain.DailyNoteFromFront{
@@ -255,7 +341,7 @@ func TestCreateNoteInvalidSleepHours(t *testing.T) {
 		SleepHours: 11.1,
 		Load:       5,
 	}
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator, nil, nil, nil, nil)
 	tests := []struct {
 		name               string
 		ctx                context.Context
@@ -309,7 +395,7 @@ func TestCreateNoteInvalidLoad(t *testing.T) {
 		SleepHours: 5.5,
 		Load:       11,
 	}
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator, nil, nil, nil, nil)
 	tests := []struct {
 		name               string
 		ctx                context.Context
@@ -358,7 +444,7 @@ func TestCreateNoteErrNoteAlreadyExists(t *testing.T) {
 		SleepHours: 5.5,
 		Load:       5,
 	}
-	dailyNoteService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator)
+	dailyNoteService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator, nil, nil, nil, nil)
 	expectedUUID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	expectedDate := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.Now().Location())
 	expectedMood, expectedSleepHours, expectedLoad := int16(5), 5.5, int16(5)
@@ -420,7 +506,7 @@ func TestCreateNoteErr(t *testing.T) {
 		SleepHours: 5.5,
 		Load:       5,
 	}
-	dailyNoteService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator)
+	dailyNoteService := NewDailyNotesService(mockDailyNotesRepository, mockIdGenerator, nil, nil, nil, nil)
 	expectedUUID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	expectedDate := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.Now().Location())
 	expectedMood, expectedSleepHours, expectedLoad := int16(5), 5.5, int16(5)
@@ -475,8 +561,8 @@ func TestChangeMoodSuccess(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	mood := int16(5)
-	dailyNoteService := NewDailyNotesService(mockDailyNotesRepository, nil)
-	expectedResponse := "mood успешно изменен"
+	dailyNoteService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
+	expectedResponse := domain.MessageMoodChanged
 	/*
 		changeMoodFnIsCalled bool
 		changeMoodUserId     uuid.UUID
@@ -485,7 +571,7 @@ func TestChangeMoodSuccess(t *testing.T) {
 	*/
 
 	// test
-	response, err := dailyNoteService.ChangeMood(ctx, userId, date, mood)
+	response, _, err := dailyNoteService.ChangeMood(ctx, userId, date, mood, 0)
 
 	// assert
 	if err != nil {
@@ -516,7 +602,7 @@ func TestChangeMoodErrWrongMoodValue(t *testing.T) {
 			return nil
 		},
 	}
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	ctx := context.Background()
 	userId := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	now := time.Now()
@@ -539,7 +625,7 @@ func TestChangeMoodErrWrongMoodValue(t *testing.T) {
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, err := dailyNotesService.ChangeMood(test.ctx, test.userId, test.date, test.mood)
+			result, _, err := dailyNotesService.ChangeMood(test.ctx, test.userId, test.date, test.mood, 0)
 			if !errors.Is(err, test.expectedError) {
 				t.Errorf("expected error - %v", expectedError)
 			}
@@ -566,11 +652,11 @@ func TestChangeMoodErrNoteNotExists(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	mood := int16(5)
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	expectedError := ErrNoteNotExists
 
 	// test
-	result, err := dailyNotesService.ChangeMood(ctx, userId, date, mood)
+	result, _, err := dailyNotesService.ChangeMood(ctx, userId, date, mood, 0)
 
 	// assert
 	if !errors.Is(err, expectedError) {
@@ -608,11 +694,11 @@ func TestChangeMoodErr(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	mood := int16(5)
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	expectedError := needError
 
 	// test
-	result, err := dailyNotesService.ChangeMood(ctx, userId, date, mood)
+	result, _, err := dailyNotesService.ChangeMood(ctx, userId, date, mood, 0)
 
 	// assert
 	if !errors.Is(err, expectedError) {
@@ -648,11 +734,11 @@ func TestChangeSleepHoursSuccess(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	sleepHours := 5.5
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
-	expectedResponse := "sleep hours успешно изменен"
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
+	expectedResponse := domain.MessageSleepHoursChanged
 
 	// test
-	response, err := dailyNotesService.ChangeSleepHours(ctx, userId, date, sleepHours)
+	response, _, err := dailyNotesService.ChangeSleepHours(ctx, userId, date, sleepHours, 0)
 
 	// assert
 	if err != nil {
@@ -700,12 +786,12 @@ func TestChangeSleepHoursErrWrongSleepHourValue(t *testing.T) {
 		{name: "negative sleep hours", ctx: ctx, userId: userId, date: date, sleepHours: sleepHoursNegative, expectedError: expectedError},
 		{name: "over sleep hours", ctx: ctx, userId: userId, date: date, sleepHours: sleepHoursOver, expectedError: expectedError},
 	}
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, err := dailyNotesService.ChangeSleepHours(test.ctx, test.userId, test.date, test.sleepHours)
+			result, _, err := dailyNotesService.ChangeSleepHours(test.ctx, test.userId, test.date, test.sleepHours, 0)
 			if !errors.Is(err, test.expectedError) {
 				t.Errorf("expected error - %v", test.expectedError)
 			}
@@ -731,11 +817,11 @@ func TestChangeSleepHoursErrNoteNotExists(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	sleepHours := 5.5
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	expectedError := ErrNoteNotExists
 
 	// test
-	response, err := dailyNotesService.ChangeSleepHours(ctx, userId, date, sleepHours)
+	response, _, err := dailyNotesService.ChangeSleepHours(ctx, userId, date, sleepHours, 0)
 
 	// assert
 	if !errors.Is(err, expectedError) {
@@ -771,11 +857,11 @@ func TestChangeSleepHoursErr(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	sleepHours := 5.5
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	expectedError := needError
 
 	// test
-	response, err := dailyNotesService.ChangeSleepHours(ctx, userId, date, sleepHours)
+	response, _, err := dailyNotesService.ChangeSleepHours(ctx, userId, date, sleepHours, 0)
 
 	// assert
 	if !errors.Is(err, expectedError) {
@@ -810,11 +896,11 @@ func TestChangeLoadSuccess(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	load := int16(5)
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
-	expectedResponse := "load успешно изменен"
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
+	expectedResponse := domain.MessageLoadChanged
 
 	// test
-	response, err := dailyNotesService.ChangeLoad(ctx, userId, date, load)
+	response, _, err := dailyNotesService.ChangeLoad(ctx, userId, date, load, 0)
 
 	// assert
 	if err != nil {
@@ -851,7 +937,7 @@ func TestChangeLoadErrWrongLoadValue(t *testing.T) {
 	loadNegative := int16(-1)
 	loadOver := int16(11)
 	expectedError := ErrWrongLoadValue
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	tests := []struct {
 		name          string
 		ctx           context.Context
@@ -867,7 +953,7 @@ func TestChangeLoadErrWrongLoadValue(t *testing.T) {
 	// test + assert
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			response, err := dailyNotesService.ChangeLoad(test.ctx, test.userId, test.date, test.load)
+			response, _, err := dailyNotesService.ChangeLoad(test.ctx, test.userId, test.date, test.load, 0)
 			if !errors.Is(err, test.expectedError) {
 				t.Errorf("expected error - %v", expectedError)
 			}
@@ -894,10 +980,10 @@ func TestChangeLoadErrNoteNotExists(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	load := int16(5)
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 
 	// test
-	response, err := dailyNotesService.ChangeLoad(ctx, userId, date, load)
+	response, _, err := dailyNotesService.ChangeLoad(ctx, userId, date, load, 0)
 
 	// assert
 	if !errors.Is(err, expectedError) {
@@ -933,11 +1019,11 @@ func TestChangeLoadErr(t *testing.T) {
 	now := time.Now()
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	load := int16(5)
-	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil)
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, nil, nil, nil, nil, nil)
 	expectedError := needError
 
 	// test
-	response, err := dailyNotesService.ChangeLoad(ctx, userId, date, load)
+	response, _, err := dailyNotesService.ChangeLoad(ctx, userId, date, load, 0)
 
 	// assert
 	if !errors.Is(err, expectedError) {
@@ -959,3 +1045,218 @@ func TestChangeLoadErr(t *testing.T) {
 		t.Errorf("expected load - %v", load)
 	}
 }
+
+// Мок транзакции - fn выполняется с пометкой в ctx, результат запоминается
+type MockTransactor struct {
+	committed  int
+	rolledBack int
+}
+
+type mockTxKey struct{}
+
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(context.WithValue(ctx, mockTxKey{}, true)); err != nil {
+		m.rolledBack++
+		return err
+	}
+	m.committed++
+	return nil
+}
+
+// Мок outbox - запоминает события и то, были ли они записаны внутри транзакции
+type MockOutbox struct {
+	events []domain.DomainEvent
+	inTx   bool
+}
+
+func (m *MockOutbox) AddOutboxEvents(ctx context.Context, events ...domain.DomainEvent) error {
+	m.inTx, _ = ctx.Value(mockTxKey{}).(bool)
+	m.events = append(m.events, events...)
+	return nil
+}
+
+// Тест - событие пишется в outbox в той же транзакции, что и изменение, и только если изменение удалось
+func TestChangeMoodWritesOutboxEvent(t *testing.T) {
+	// preparing
+	notExists := true
+	mockDailyNotesRepository := &MockDailyNotesRepository{
+		ChangeMoodFn: func(ctx context.Context, userId uuid.UUID, date time.Time, mood int16) error {
+			if notExists {
+				return repository.ErrDailyEntryNotFound
+			}
+			return nil
+		},
+	}
+	mockTransactor := &MockTransactor{}
+	mockOutbox := &MockOutbox{}
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, &MockUUIDGenerator{}, nil, mockTransactor, mockOutbox, nil)
+	date := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// test
+	_, _, errNotExists := dailyNotesService.ChangeMood(context.Background(), uuid.New(), date, 3, 0)
+	notExists = false
+	_, _, err := dailyNotesService.ChangeMood(context.Background(), uuid.New(), date, 3, 0)
+
+	// assert
+	if !errors.Is(errNotExists, ErrNoteNotExists) || err != nil {
+		t.Fatalf("неожиданные ошибки - %v, %v", errNotExists, err)
+	}
+	if mockTransactor.rolledBack != 1 || mockTransactor.committed != 1 {
+		t.Errorf("ожидались один откат и один коммит, получено - %v и %v", mockTransactor.rolledBack, mockTransactor.committed)
+	}
+	if len(mockOutbox.events) != 1 || mockOutbox.events[0].Type != domain.DomainEventEntryChanged {
+		t.Fatalf("ожидалось одно событие %v, получены - %v", domain.DomainEventEntryChanged, mockOutbox.events)
+	}
+	if !mockOutbox.inTx {
+		t.Errorf("событие должно писаться внутри транзакции")
+	}
+	var payload domain.UserEventPayload
+	if err := json.Unmarshal(mockOutbox.events[0].Payload, &payload); err != nil {
+		t.Fatalf("payload не разобран - %v", err)
+	}
+	if payload.Date != "2025-01-03" || payload.Mood == nil || *payload.Mood != 3 || payload.Load != nil {
+		t.Errorf("неожиданный payload - %+v", payload)
+	}
+}
+
+// Тест - ожидаемая версия доходит до репозитория, устаревшая версия превращается в ErrNoteVersionMismatch
+func TestChangeLoadVersion(t *testing.T) {
+	// preparing
+	stale := false
+	mockDailyNotesRepository := &MockDailyNotesRepository{
+		ChangeLoadFn: func(ctx context.Context, userId uuid.UUID, date time.Time, load int16) error {
+			if stale {
+				return repository.ErrDailyEntryVersionMismatch
+			}
+			return nil
+		},
+	}
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, &MockUUIDGenerator{}, nil, nil, nil, nil)
+	date := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// test
+	_, newVersion, err := dailyNotesService.ChangeLoad(context.Background(), uuid.New(), date, 4, 3)
+	stale = true
+	_, _, errStale := dailyNotesService.ChangeLoad(context.Background(), uuid.New(), date, 4, 3)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if mockDailyNotesRepository.changeLoadVersion != 3 {
+		t.Errorf("ожидалась версия в репозитории - 3, получена - %v", mockDailyNotesRepository.changeLoadVersion)
+	}
+	if newVersion != 4 {
+		t.Errorf("ожидалась новая версия - 4, получена - %v", newVersion)
+	}
+	if !errors.Is(errStale, ErrNoteVersionMismatch) {
+		t.Errorf("ожидалась ошибка - %v, получена - %v", ErrNoteVersionMismatch, errStale)
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+// testEntryHistory - запись создана (версия 1), затем изменены mood (2) и load (3)
+var testEntryHistory = []domain.EntryChange{
+	{Version: 1, Field: domain.EntryFieldMood, NewValue: 5},
+	{Version: 1, Field: domain.EntryFieldSleepHours, NewValue: 7},
+	{Version: 1, Field: domain.EntryFieldLoad, NewValue: 3},
+	{Version: 2, Field: domain.EntryFieldMood, OldValue: ptr(5), NewValue: 8},
+	{Version: 3, Field: domain.EntryFieldLoad, OldValue: ptr(3), NewValue: 6},
+}
+
+// Тест - откат пишет значения старой версии новой версией и записывает в историю только изменившиеся поля
+func TestRevertNote(t *testing.T) {
+	// preparing
+	entryId := uuid.New()
+	mockDailyNotesRepository := &MockDailyNotesRepository{
+		GetNoteFn: func(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error) {
+			return domain.DailyEntry{Id: entryId, Date: date, Mood: 8, SleepHours: 7, Load: 6, Version: 3}, nil
+		},
+	}
+	mockHistory := &MockEntryHistoryRepository{history: testEntryHistory}
+	mockOutbox := &MockOutbox{}
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, &MockUUIDGenerator{}, nil, &MockTransactor{}, mockOutbox, mockHistory)
+	ctx := WithClientInfo(context.Background(), domain.ClientInfo{IP: "10.0.0.1", UserAgent: "test"})
+	date := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// test
+	message, newVersion, err := dailyNotesService.RevertNote(ctx, uuid.New(), date, 1, 3)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if message != domain.MessageNoteReverted || newVersion != 4 {
+		t.Errorf("ожидались %v и версия 4, получено - %v и %v", domain.MessageNoteReverted, message, newVersion)
+	}
+	restored := mockDailyNotesRepository.restoreNoteEntry
+	if restored.Mood != 5 || restored.SleepHours != 7 || restored.Load != 3 || restored.Version != 3 {
+		t.Errorf("ожидались значения версии 1 с проверкой по версии 3, получено - %+v", restored)
+	}
+	if len(mockHistory.added) != 2 {
+		t.Fatalf("ожидалось изменений в истории - 2, получено - %v", len(mockHistory.added))
+	}
+	for _, change := range mockHistory.added {
+		if change.Version != 4 || change.Reason != domain.EntryChangeReverted || change.EntryId != entryId || change.ClientIP != "10.0.0.1" {
+			t.Errorf("неожиданное изменение в истории - %+v", change)
+		}
+	}
+	if len(mockOutbox.events) != 1 || mockOutbox.events[0].Type != domain.DomainEventEntryChanged {
+		t.Errorf("ожидалось одно событие %v, получены - %v", domain.DomainEventEntryChanged, mockOutbox.events)
+	}
+}
+
+// Тест - откат на версию вне истории и с устаревшей версией
+func TestRevertNoteErrors(t *testing.T) {
+	// preparing
+	mockDailyNotesRepository := &MockDailyNotesRepository{
+		GetNoteFn: func(ctx context.Context, userId uuid.UUID, date time.Time) (domain.DailyEntry, error) {
+			return domain.DailyEntry{Mood: 8, SleepHours: 7, Load: 6, Version: 3}, nil
+		},
+	}
+	mockHistory := &MockEntryHistoryRepository{history: testEntryHistory}
+	dailyNotesService := NewDailyNotesService(mockDailyNotesRepository, &MockUUIDGenerator{}, nil, nil, nil, mockHistory)
+	date := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// test
+	_, _, errNotExists := dailyNotesService.RevertNote(context.Background(), uuid.New(), date, 5, 3)
+	_, _, errStale := dailyNotesService.RevertNote(context.Background(), uuid.New(), date, 1, 2)
+
+	// assert
+	if !errors.Is(errNotExists, ErrNoteVersionNotExists) {
+		t.Errorf("ожидалась ошибка - %v, получена - %v", ErrNoteVersionNotExists, errNotExists)
+	}
+	if !errors.Is(errStale, ErrNoteVersionMismatch) {
+		t.Errorf("ожидалась ошибка - %v, получена - %v", ErrNoteVersionMismatch, errStale)
+	}
+	if len(mockHistory.added) != 0 {
+		t.Errorf("история не должна меняться, добавлено - %v", mockHistory.added)
+	}
+}
+
+func TestEntryAtVersion(t *testing.T) {
+	current := domain.DailyEntry{Mood: 8, SleepHours: 7, Load: 6, Version: 3}
+	tests := []struct {
+		name     string
+		history  []domain.EntryChange
+		version  int
+		expected domain.DailyEntry
+		ok       bool
+	}{
+		{"текущая версия", testEntryHistory, 3, current, true},
+		{"версия 2", testEntryHistory, 2, domain.DailyEntry{Mood: 8, SleepHours: 7, Load: 3, Version: 2}, true},
+		{"версия 1", testEntryHistory, 1, domain.DailyEntry{Mood: 5, SleepHours: 7, Load: 3, Version: 1}, true},
+		{"версия больше текущей", testEntryHistory, 4, domain.DailyEntry{}, false},
+		{"нулевая версия", testEntryHistory, 0, domain.DailyEntry{}, false},
+		{"история неполная", testEntryHistory[4:], 1, domain.DailyEntry{}, false},
+	}
+	for _, tt := range tests {
+		entry, ok := entryAtVersion(current, tt.history, tt.version)
+		if ok != tt.ok || entry != tt.expected {
+			t.Errorf("%v: ожидалось - %+v, %v, получено - %+v, %v", tt.name, tt.expected, tt.ok, entry, ok)
+		}
+	}
+}
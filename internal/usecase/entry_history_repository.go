@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// EntryHistoryRepository - история изменений записей; AddEntryChanges вызывается внутри Transactor.WithinTx вместе с изменением
+type EntryHistoryRepository interface {
+	AddEntryChanges(ctx context.Context, changes ...domain.EntryChange) error
+	// GetEntryHistory - изменения записи по возрастанию версии
+	GetEntryHistory(ctx context.Context, entryId uuid.UUID) ([]domain.EntryChange, error)
+}
+
+// noopEntryHistory используется, когда история не нужна (например, в тестах)
+type noopEntryHistory struct {
+}
+
+func (noopEntryHistory) AddEntryChanges(ctx context.Context, changes ...domain.EntryChange) error {
+	return nil
+}
+
+func (noopEntryHistory) GetEntryHistory(ctx context.Context, entryId uuid.UUID) ([]domain.EntryChange, error) {
+	return []domain.EntryChange{}, nil
+}
@@ -6,6 +6,10 @@ import "errors"
 var ErrUserExists = errors.New("user already exists")
 var ErrUserNotExist = errors.New("user not exist")
 var ErrWrongPassword = errors.New("wrong password")
+var ErrUserSuspended = errors.New("user is suspended")
+var ErrWrongLanguage = errors.New("unsupported language")
+var ErrWrongTimeZone = errors.New("unknown time zone")
+var ErrWrongReminderTime = errors.New("wrong reminder time")
 
 // notes
 var ErrWrongMoodValue = errors.New("wrong mood value")
@@ -13,3 +17,25 @@ var ErrWrongSleepHourValue = errors.New("wrong sleep hours value")
 var ErrWrongLoadValue = errors.New("wrong load value")
 var ErrNoteAlreadyExists = errors.New("note already exusts")
 var ErrNoteNotExists = errors.New("note not exists")
+var ErrNoteVersionMismatch = errors.New("note version mismatch")
+var ErrNoteVersionNotExists = errors.New("note version not exists")
+
+// api tokens
+var ErrWrongApiTokenName = errors.New("wrong api token name")
+var ErrWrongApiTokenScope = errors.New("wrong api token scope")
+var ErrWrongApiTokenExpiration = errors.New("wrong api token expiration")
+var ErrApiTokenExists = errors.New("api token already exists")
+var ErrApiTokenNotExists = errors.New("api token not exists")
+var ErrInvalidApiToken = errors.New("invalid api token")
+var ErrApiTokenExpired = errors.New("api token is expired")
+
+// auth
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// events
+var ErrTooManyStreams = errors.New("too many event streams")
+
+// health
+var ErrShuttingDown = errors.New("server is shutting down")
+var ErrDatabaseUnavailable = errors.New("database unavailable")
+var ErrSchemaVersionMismatch = errors.New("schema version mismatch")
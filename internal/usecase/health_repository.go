@@ -0,0 +1,8 @@
+package usecase
+
+import "context"
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	GetSchemaVersion(ctx context.Context) (uint, bool, error)
+}
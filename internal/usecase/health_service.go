@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type HealthService struct {
+	healthRepository HealthRepository
+	schemaVersion    uint          // версия миграций, с которой собран бинарник
+	timeout          time.Duration // сколько ждать базу в проверке
+	shuttingDown     atomic.Bool
+}
+
+func NewHealthService(healthRepository HealthRepository, schemaVersion uint, timeout time.Duration) *HealthService {
+	return &HealthService{
+		healthRepository: healthRepository,
+		schemaVersion:    schemaVersion,
+		timeout:          timeout,
+	}
+}
+
+// BeginShutdown переводит readiness в провал, чтобы балансировщик снял трафик до остановки сервера
+func (h *HealthService) BeginShutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Ready проверяет, что сервис может принимать трафик: не останавливается, база отвечает и схема нужной версии
+func (h *HealthService) Ready(ctx context.Context) error {
+	if h.shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	if err := h.healthRepository.Ping(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseUnavailable, err)
+	}
+	version, dirty, err := h.healthRepository.GetSchemaVersion(ctx)
+	if err != nil && errors.Is(err, repository.ErrNoRow) {
+		return fmt.Errorf("%w: no migrations applied", ErrSchemaVersionMismatch)
+	} else if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseUnavailable, err)
+	}
+	if dirty {
+		return fmt.Errorf("%w: version %d is dirty", ErrSchemaVersionMismatch, version)
+	}
+	if version != h.schemaVersion {
+		return fmt.Errorf("%w: expected %d, got %d", ErrSchemaVersionMismatch, h.schemaVersion, version)
+	}
+	return nil
+}
+
+// SchemaVersion - текущая версия схемы в базе рядом с версией, с которой собран бинарник
+func (h *HealthService) SchemaVersion(ctx context.Context) (domain.SchemaVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	version, dirty, err := h.healthRepository.GetSchemaVersion(ctx)
+	if err != nil && !errors.Is(err, repository.ErrNoRow) {
+		return domain.SchemaVersion{}, fmt.Errorf("%w: %v", ErrDatabaseUnavailable, err)
+	}
+	return domain.SchemaVersion{
+		Current:  version,
+		Expected: h.schemaVersion,
+		Dirty:    dirty,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"chopper/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Моки
+// Мок репозитория health проверок
+type MockHealthRepository struct {
+	PingFn             func(ctx context.Context) error
+	GetSchemaVersionFn func(ctx context.Context) (uint, bool, error)
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	if m.PingFn != nil {
+		return m.PingFn(ctx)
+	}
+	return nil
+}
+
+func (m *MockHealthRepository) GetSchemaVersion(ctx context.Context) (uint, bool, error) {
+	if m.GetSchemaVersionFn != nil {
+		return m.GetSchemaVersionFn(ctx)
+	}
+	return 5, false, nil
+}
+
+// Тест Ready - Успех
+func TestReadySuccess(t *testing.T) {
+	// preparing
+	service := NewHealthService(&MockHealthRepository{}, 5, time.Second)
+
+	// test
+	err := service.Ready(context.Background())
+
+	// assert
+	if err != nil {
+		t.Errorf("error was not expected - %v", err)
+	}
+}
+
+// Тест Ready - Провал (база недоступна, схема другой версии или грязная, нет миграций)
+func TestReadyErr(t *testing.T) {
+	// preparing
+	tests := []struct {
+		name          string
+		repository    *MockHealthRepository
+		expectedError error
+	}{
+		{
+			name: "ping failed",
+			repository: &MockHealthRepository{PingFn: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			}},
+			expectedError: ErrDatabaseUnavailable,
+		},
+		{
+			name: "old schema",
+			repository: &MockHealthRepository{GetSchemaVersionFn: func(ctx context.Context) (uint, bool, error) {
+				return 4, false, nil
+			}},
+			expectedError: ErrSchemaVersionMismatch,
+		},
+		{
+			name: "dirty schema",
+			repository: &MockHealthRepository{GetSchemaVersionFn: func(ctx context.Context) (uint, bool, error) {
+				return 5, true, nil
+			}},
+			expectedError: ErrSchemaVersionMismatch,
+		},
+		{
+			name: "no migrations",
+			repository: &MockHealthRepository{GetSchemaVersionFn: func(ctx context.Context) (uint, bool, error) {
+				return 0, false, repository.ErrNoRow
+			}},
+			expectedError: ErrSchemaVersionMismatch,
+		},
+	}
+
+	// test + assert
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := NewHealthService(test.repository, 5, time.Second)
+			err := service.Ready(context.Background())
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf("expected error was - %v, got - %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+// Тест Ready - после BeginShutdown проверка проваливается, даже если база в порядке
+func TestReadyErrShuttingDown(t *testing.T) {
+	// preparing
+	pingIsCalled := false
+	service := NewHealthService(&MockHealthRepository{PingFn: func(ctx context.Context) error {
+		pingIsCalled = true
+		return nil
+	}}, 5, time.Second)
+
+	// test
+	service.BeginShutdown()
+	err := service.Ready(context.Background())
+
+	// assert
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("expected error was - %v", ErrShuttingDown)
+	}
+	if pingIsCalled {
+		t.Errorf("database must not be checked during shutdown")
+	}
+}
+
+// Тест SchemaVersion - версия базы рядом с версией бинарника, без миграций текущая версия 0
+func TestSchemaVersion(t *testing.T) {
+	// preparing
+	noMigrations := &MockHealthRepository{GetSchemaVersionFn: func(ctx context.Context) (uint, bool, error) {
+		return 0, false, repository.ErrNoRow
+	}}
+
+	// test
+	version, err := NewHealthService(&MockHealthRepository{}, 6, time.Second).SchemaVersion(context.Background())
+	empty, emptyErr := NewHealthService(noMigrations, 6, time.Second).SchemaVersion(context.Background())
+
+	// assert
+	if err != nil || version.Current != 5 || version.Expected != 6 || version.Dirty {
+		t.Errorf("expected current 5 and expected 6, got - %+v, %v", version, err)
+	}
+	if emptyErr != nil || empty.Current != 0 || empty.Expected != 6 {
+		t.Errorf("expected current 0 without migrations, got - %+v, %v", empty, emptyErr)
+	}
+}
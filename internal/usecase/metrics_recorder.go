@@ -0,0 +1,20 @@
+package usecase
+
+// MetricsRecorder - хуки бизнес метрик, реализуются пакетом metrics
+type MetricsRecorder interface {
+	LoginSucceeded()
+	LoginFailed()
+	EntryCreated()
+	AlertTriggered(rule string)
+	ReminderDelivered(result string)
+}
+
+// noopMetrics используется, когда метрики не переданы (например, в тестах)
+type noopMetrics struct {
+}
+
+func (noopMetrics) LoginSucceeded()                 {}
+func (noopMetrics) LoginFailed()                    {}
+func (noopMetrics) EntryCreated()                   {}
+func (noopMetrics) AlertTriggered(rule string)      {}
+func (noopMetrics) ReminderDelivered(result string) {}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// outboxRetryMax - предел паузы перед повторной доставкой события
+const outboxRetryMax = time.Minute * 5
+
+// OutboxHandler - подписчик релея. Доставка at-least-once: после сбоя событие приходит повторно
+// всем его подписчикам, поэтому обработчик должен переносить дубли
+type OutboxHandler func(ctx context.Context, event domain.DomainEvent) error
+
+type outboxSubscriber struct {
+	name   string
+	handle OutboxHandler
+	types  map[domain.DomainEventType]struct{}
+}
+
+// OutboxRelay рассылает события из outbox подписчикам этого процесса. Проход выполняется
+// планировщиком под блокировкой, поэтому при нескольких репликах события рассылает одна из них
+type OutboxRelay struct {
+	outboxRepository OutboxRepository
+	config           domain.OutboxConfig
+	subscribers      []outboxSubscriber
+}
+
+func NewOutboxRelay(outboxRepository OutboxRepository, config domain.OutboxConfig) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepository: outboxRepository,
+		config:           config,
+	}
+}
+
+// Subscribe регистрирует обработчик событий types, вызывается до запуска планировщика
+func (o *OutboxRelay) Subscribe(name string, handle OutboxHandler, types ...domain.DomainEventType) {
+	subscriber := outboxSubscriber{name: name, handle: handle, types: map[domain.DomainEventType]struct{}{}}
+	for _, eventType := range types {
+		subscriber.types[eventType] = struct{}{}
+	}
+	o.subscribers = append(o.subscribers, subscriber)
+}
+
+// RelayPending - один проход, не больше BatchSize событий. Отмена ctx останавливает проход
+// между событиями: начатая рассылка доводится до конца и записывается
+func (o *OutboxRelay) RelayPending(ctx context.Context) error {
+	events, err := o.outboxRepository.GetPendingOutboxEvents(ctx, o.config.MaxAttempts, o.config.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := o.publish(context.WithoutCancel(ctx), event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cleanup удаляет разосланные события старше Retention
+func (o *OutboxRelay) Cleanup(ctx context.Context) error {
+	deleted, err := o.outboxRepository.DeleteOutboxEventsPublishedBefore(ctx, time.Now().Add(-o.config.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).WithField("deleted", deleted).Info("published outbox events cleaned up")
+	}
+	return nil
+}
+
+// publish возвращает только ошибку записи результата: неудачная рассылка повторится позже
+func (o *OutboxRelay) publish(ctx context.Context, event domain.DomainEvent) error {
+	ctx, span := startSpan(ctx, "OutboxRelay.publish")
+	defer span.End()
+	var errs []error
+	for _, subscriber := range o.subscribers {
+		if _, ok := subscriber.types[event.Type]; !ok {
+			continue
+		}
+		if err := subscriber.handle(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", subscriber.name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		attempts := event.Attempts + 1
+		log := logger.FromContext(ctx).WithFields(logrus.Fields{
+			"event_id": event.Id,
+			"type":     event.Type,
+			"attempt":  attempts,
+		}).WithError(err)
+		if attempts >= o.config.MaxAttempts {
+			log.Error("outbox event delivery failed, giving up")
+		} else {
+			log.Warn("outbox event delivery failed")
+		}
+		return o.outboxRepository.MarkOutboxEventFailed(ctx, event.Id, err.Error(), time.Now().Add(outboxRetryDelay(attempts)))
+	}
+	return o.outboxRepository.MarkOutboxEventPublished(ctx, event.Id)
+}
+
+// outboxRetryDelay - экспоненциальная пауза: 1s, 2s, 4s ... до outboxRetryMax
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := time.Second
+	for i := 1; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxRetryMax)
+}
+
+// newDomainEvent - событие для записи в outbox, Id и время проставляет база
+func newDomainEvent(eventType domain.DomainEventType, userId uuid.UUID, payload any) (domain.DomainEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return domain.DomainEvent{}, err
+	}
+	return domain.DomainEvent{Type: eventType, UserId: userId, Payload: data}, nil
+}
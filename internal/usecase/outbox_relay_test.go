@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Моки
+// Мок репозитория outbox - отдает pending и запоминает результат рассылки
+type MockOutboxRepository struct {
+	MockOutbox
+	pending   []domain.DomainEvent
+	published []int64
+	failed    map[int64]string
+	retryAt   map[int64]time.Time
+}
+
+func (m *MockOutboxRepository) GetPendingOutboxEvents(ctx context.Context, maxAttempts, limit int) ([]domain.DomainEvent, error) {
+	return m.pending, nil
+}
+
+func (m *MockOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	m.published = append(m.published, id)
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, retryAt time.Time) error {
+	m.failed[id] = lastError
+	m.retryAt[id] = retryAt
+	return nil
+}
+
+func (m *MockOutboxRepository) DeleteOutboxEventsPublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+var testOutboxConfig = domain.OutboxConfig{BatchSize: 10, MaxAttempts: 5, Retention: time.Hour}
+
+// Тест - события доходят только до подписчиков своего типа, сбой подписчика откладывает событие на повтор
+func TestRelayPending(t *testing.T) {
+	// preparing
+	userId := uuid.New()
+	mockOutboxRepository := &MockOutboxRepository{
+		pending: []domain.DomainEvent{
+			{Id: 1, Type: domain.DomainEventEntryCreated, UserId: userId},
+			{Id: 2, Type: domain.DomainEventUserRegistered, UserId: userId},
+			{Id: 3, Type: domain.DomainEventEntryChanged, UserId: userId, Attempts: 2},
+		},
+		failed:  map[int64]string{},
+		retryAt: map[int64]time.Time{},
+	}
+	received := []int64{}
+	relay := NewOutboxRelay(mockOutboxRepository, testOutboxConfig)
+	relay.Subscribe("entries", func(ctx context.Context, event domain.DomainEvent) error {
+		received = append(received, event.Id)
+		if event.Id == 3 {
+			return errors.New("база недоступна")
+		}
+		return nil
+	}, domain.DomainEventEntryCreated, domain.DomainEventEntryChanged)
+	start := time.Now()
+
+	// test
+	err := relay.RelayPending(context.Background())
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if len(received) != 2 || received[0] != 1 || received[1] != 3 {
+		t.Errorf("ожидалась доставка событий 1 и 3, получено - %v", received)
+	}
+	if len(mockOutboxRepository.published) != 2 || mockOutboxRepository.published[0] != 1 || mockOutboxRepository.published[1] != 2 {
+		t.Errorf("ожидались разосланными события 1 и 2, получено - %v", mockOutboxRepository.published)
+	}
+	if mockOutboxRepository.failed[3] != "entries: база недоступна" {
+		t.Errorf("ожидалась записанная ошибка подписчика, получено - %q", mockOutboxRepository.failed[3])
+	}
+	// третья попытка - пауза 4s
+	if delay := mockOutboxRepository.retryAt[3].Sub(start); delay < time.Second*4 || delay > time.Second*5 {
+		t.Errorf("ожидалась пауза перед повтором около 4s, получено - %v", delay)
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, time.Second * 2},
+		{4, time.Second * 8},
+		{20, outboxRetryMax},
+	}
+	for _, tt := range tests {
+		if delay := outboxRetryDelay(tt.attempts); delay != tt.expected {
+			t.Errorf("попытка %v: ожидалась пауза - %v, получена - %v", tt.attempts, tt.expected, delay)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+)
+
+// OutboxWriter - запись доменных событий; вызывается внутри Transactor.WithinTx вместе с изменением
+type OutboxWriter interface {
+	AddOutboxEvents(ctx context.Context, events ...domain.DomainEvent) error
+}
+
+type OutboxRepository interface {
+	OutboxWriter
+	// GetPendingOutboxEvents - неразосланные события по порядку, у которых подошло время следующей попытки
+	GetPendingOutboxEvents(ctx context.Context, maxAttempts, limit int) ([]domain.DomainEvent, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, retryAt time.Time) error
+	DeleteOutboxEventsPublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// noopOutbox используется, когда события не нужны (например, в тестах)
+type noopOutbox struct {
+}
+
+func (noopOutbox) AddOutboxEvents(ctx context.Context, events ...domain.DomainEvent) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReminderRepository interface {
+	// GetDueReminders - пользователи, у которых по их часовому поясу наступило время напоминания,
+	// а записи за сегодня нет и напоминание еще не доставлено
+	GetDueReminders(ctx context.Context, maxAttempts, limit int) ([]domain.Reminder, error)
+	// RecordReminderDelivery - пустой deliveryErr означает успешную доставку, неудачная повторяется не раньше retryAt
+	RecordReminderDelivery(ctx context.Context, userId uuid.UUID, date time.Time, deliveryErr string, retryAt time.Time) error
+}
+
+// ReminderNotifier доставляет напоминание пользователю: в лог, вебхук и т.п.
+type ReminderNotifier interface {
+	Notify(ctx context.Context, reminder domain.Reminder) error
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/logger"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// результат доставки напоминания - label в метриках
+const (
+	reminderDelivered = "delivered"
+	reminderFailed    = "failed"
+)
+
+// ReminderService напоминает заполнить день тем, кто к своему времени напоминания еще этого не сделал
+type ReminderService struct {
+	reminderRepository ReminderRepository
+	notifier           ReminderNotifier
+	config             domain.RemindersConfig
+	metrics            MetricsRecorder
+}
+
+func NewReminderService(reminderRepository ReminderRepository, notifier ReminderNotifier, config domain.RemindersConfig, metrics MetricsRecorder) *ReminderService {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	return &ReminderService{
+		reminderRepository: reminderRepository,
+		notifier:           notifier,
+		config:             config,
+		metrics:            metrics,
+	}
+}
+
+// SendDue - один проход планировщика, не больше BatchSize напоминаний; остальные уйдут следующим проходом.
+// Отмена ctx останавливает проход между напоминаниями: начатая доставка доводится до конца и записывается
+func (r *ReminderService) SendDue(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "ReminderService.SendDue")
+	defer span.End()
+	reminders, err := r.reminderRepository.GetDueReminders(ctx, r.config.MaxAttempts, r.config.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range reminders {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.send(context.WithoutCancel(ctx), reminder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send возвращает только ошибку записи результата: неудачная доставка повторится после паузы reminderRetryDelay
+func (r *ReminderService) send(ctx context.Context, reminder domain.Reminder) error {
+	log := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"user_id": reminder.UserId,
+		"date":    reminder.Date.Format("2006-01-02"),
+		"attempt": reminder.Attempt,
+	})
+	deliveryErr := ""
+	if err := r.notifier.Notify(ctx, reminder); err != nil {
+		log.WithError(err).Warn("reminder delivery failed")
+		r.metrics.ReminderDelivered(reminderFailed)
+		deliveryErr = err.Error()
+	} else {
+		log.Info("reminder delivered")
+		r.metrics.ReminderDelivered(reminderDelivered)
+	}
+	retryAt := time.Now().Add(reminderRetryDelay(r.config.RetryDelay, reminder.Attempt))
+	return r.reminderRepository.RecordReminderDelivery(ctx, reminder.UserId, reminder.Date, deliveryErr, retryAt)
+}
+
+// reminderRetryDelay - пауза после неудачной попытки attempt: delay, 2*delay, 4*delay ...
+func reminderRetryDelay(delay time.Duration, attempt int) time.Duration {
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+	}
+	return delay
+}
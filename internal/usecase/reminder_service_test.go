@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Моки
+// Мок репозитория напоминаний - отдает due и запоминает записанные результаты
+type MockReminderRepository struct {
+	due      []domain.Reminder
+	recorded map[uuid.UUID]string
+	retryAt  map[uuid.UUID]time.Time
+}
+
+func (m *MockReminderRepository) GetDueReminders(ctx context.Context, maxAttempts, limit int) ([]domain.Reminder, error) {
+	if len(m.due) > limit {
+		return m.due[:limit], nil
+	}
+	return m.due, nil
+}
+
+func (m *MockReminderRepository) RecordReminderDelivery(ctx context.Context, userId uuid.UUID, date time.Time, deliveryErr string, retryAt time.Time) error {
+	m.recorded[userId] = deliveryErr
+	if m.retryAt != nil {
+		m.retryAt[userId] = retryAt
+	}
+	return nil
+}
+
+// Мок нотификатора - NotifyFn вызывается на каждое напоминание
+type MockReminderNotifier struct {
+	NotifyFn func(ctx context.Context, reminder domain.Reminder) error
+}
+
+func (m *MockReminderNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	return m.NotifyFn(ctx, reminder)
+}
+
+var testRemindersConfig = domain.RemindersConfig{BatchSize: 10, MaxAttempts: 3, RetryDelay: time.Minute * 5}
+
+// Тест - доставленные и неудачные напоминания записываются, ошибка доставки не прерывает проход
+func TestSendDueRecordsDeliveries(t *testing.T) {
+	// preparing
+	failed := uuid.New()
+	delivered := uuid.New()
+	mockReminderRepository := &MockReminderRepository{
+		due:      []domain.Reminder{{UserId: failed, Attempt: 1}, {UserId: delivered, Attempt: 1}},
+		recorded: map[uuid.UUID]string{},
+	}
+	mockNotifier := &MockReminderNotifier{NotifyFn: func(ctx context.Context, reminder domain.Reminder) error {
+		if reminder.UserId == failed {
+			return errors.New("webhook недоступен")
+		}
+		return nil
+	}}
+	service := NewReminderService(mockReminderRepository, mockNotifier, testRemindersConfig, nil)
+
+	// test
+	err := service.SendDue(context.Background())
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if len(mockReminderRepository.recorded) != 2 {
+		t.Fatalf("ожидалось записей - 2, получено - %v", len(mockReminderRepository.recorded))
+	}
+	if mockReminderRepository.recorded[failed] != "webhook недоступен" {
+		t.Errorf("ожидалась записанная ошибка доставки, получено - %q", mockReminderRepository.recorded[failed])
+	}
+	if mockReminderRepository.recorded[delivered] != "" {
+		t.Errorf("ожидалась успешная доставка, получено - %q", mockReminderRepository.recorded[delivered])
+	}
+}
+
+// Тест - отмена ctx останавливает проход между напоминаниями, начатая доставка записывается
+func TestSendDueStopsOnCancel(t *testing.T) {
+	// preparing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockReminderRepository := &MockReminderRepository{
+		due:      []domain.Reminder{{UserId: uuid.New()}, {UserId: uuid.New()}},
+		recorded: map[uuid.UUID]string{},
+	}
+	mockNotifier := &MockReminderNotifier{NotifyFn: func(notifyCtx context.Context, reminder domain.Reminder) error {
+		cancel()
+		return notifyCtx.Err()
+	}}
+	service := NewReminderService(mockReminderRepository, mockNotifier, testRemindersConfig, nil)
+
+	// test
+	err := service.SendDue(ctx)
+
+	// assert
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ожидалась ошибка - %v, получена - %v", context.Canceled, err)
+	}
+	if len(mockReminderRepository.recorded) != 1 {
+		t.Fatalf("ожидалось записей - 1, получено - %v", len(mockReminderRepository.recorded))
+	}
+	for _, deliveryErr := range mockReminderRepository.recorded {
+		if deliveryErr != "" {
+			t.Errorf("начатая доставка не должна отменяться, получено - %q", deliveryErr)
+		}
+	}
+}
+
+// Тест - неудачная доставка повторяется не раньше паузы, которая удваивается с каждой попыткой
+func TestSendDueRetryBackoff(t *testing.T) {
+	// preparing
+	first, third := uuid.New(), uuid.New()
+	mockReminderRepository := &MockReminderRepository{
+		due:      []domain.Reminder{{UserId: first, Attempt: 1}, {UserId: third, Attempt: 3}},
+		recorded: map[uuid.UUID]string{},
+		retryAt:  map[uuid.UUID]time.Time{},
+	}
+	mockNotifier := &MockReminderNotifier{NotifyFn: func(ctx context.Context, reminder domain.Reminder) error {
+		return errors.New("webhook недоступен")
+	}}
+	service := NewReminderService(mockReminderRepository, mockNotifier, testRemindersConfig, nil)
+	start := time.Now()
+
+	// test
+	err := service.SendDue(context.Background())
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	tests := []struct {
+		userId uuid.UUID
+		delay  time.Duration
+	}{
+		{first, time.Minute * 5},
+		{third, time.Minute * 20},
+	}
+	for _, tt := range tests {
+		delay := mockReminderRepository.retryAt[tt.userId].Sub(start)
+		if delay < tt.delay || delay > tt.delay+time.Second {
+			t.Errorf("ожидалась пауза %v, получена - %v", tt.delay, delay)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "chopper/internal/usecase"
+
+// startSpan открывает дочерний спан метода сервиса; запросы репозитория становятся его детьми через ctx
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import "context"
+
+// Transactor - единица работы: репозитории, вызванные с ctx из fn, пишут в одну транзакцию.
+// Ошибка fn откатывает транзакцию, вложенный WithinTx присоединяется к внешней
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// noopTransactor используется, когда транзакции не нужны (например, в тестах)
+type noopTransactor struct {
+}
+
+func (noopTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
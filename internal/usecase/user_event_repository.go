@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserEventRepository interface {
+	CreateUserEvent(ctx context.Context, userId uuid.UUID, eventType domain.UserEventType, payload domain.UserEventPayload) (domain.UserEvent, error)
+	GetUserEventsAfter(ctx context.Context, userId uuid.UUID, afterId int64, limit int) ([]domain.UserEvent, error)
+	GetLastUserEvent(ctx context.Context, userId uuid.UUID, eventType domain.UserEventType) (domain.UserEvent, error)
+	DeleteUserEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// UserEventListener доставляет события, записанные любой репликой; блокируется до ошибки или отмены ctx
+type UserEventListener interface {
+	Listen(ctx context.Context, handle func(domain.UserEvent)) error
+}
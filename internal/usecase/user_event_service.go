@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// userEventReplayPage - размер страницы при догоне по Last-Event-ID
+	userEventReplayPage = 500
+	// userEventBuffer - сколько событий может ждать медленный клиент, дальше поток закрывается
+	userEventBuffer = 64
+	// userEventRetryMax - предел паузы между переподключениями LISTEN
+	userEventRetryMax = time.Second * 30
+	// userEventCleanupInterval - как часто удаляются события старше retention
+	userEventCleanupInterval = time.Hour
+)
+
+// UserEventSubscription - поток событий одного клиента: сначала Replay, потом Events.
+// Events закрывается, если клиент не успевает читать или LISTEN переподключился - клиент
+// переподключается с Last-Event-ID и догоняет пропущенное из базы
+type UserEventSubscription struct {
+	userId uuid.UUID
+	Replay []domain.UserEvent
+	events chan domain.UserEvent
+}
+
+func (s *UserEventSubscription) Events() <-chan domain.UserEvent {
+	return s.events
+}
+
+// UserEventService пишет события в базу и раздает их подписчикам этой реплики.
+// Между репликами события ходят через LISTEN/NOTIFY, поэтому подписчики получают их только из listener
+type UserEventService struct {
+	userEventRepository UserEventRepository
+	listener            UserEventListener
+	alertService        *AlertService
+	transactor          Transactor
+	outbox              OutboxWriter
+	config              domain.EventsConfig
+	log                 *logrus.Logger
+
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*UserEventSubscription]struct{}
+	closed      bool
+}
+
+func NewUserEventService(userEventRepository UserEventRepository, listener UserEventListener, alertService *AlertService, transactor Transactor, outbox OutboxWriter, config domain.EventsConfig, log *logrus.Logger) *UserEventService {
+	if transactor == nil {
+		transactor = noopTransactor{}
+	}
+	if outbox == nil {
+		outbox = noopOutbox{}
+	}
+	return &UserEventService{
+		userEventRepository: userEventRepository,
+		listener:            listener,
+		alertService:        alertService,
+		transactor:          transactor,
+		outbox:              outbox,
+		config:              config,
+		log:                 log,
+		subscribers:         map[uuid.UUID]map[*UserEventSubscription]struct{}{},
+	}
+}
+
+// HeartbeatInterval - период комментариев в SSE потоке
+func (e *UserEventService) HeartbeatInterval() time.Duration {
+	return e.config.HeartbeatInterval
+}
+
+// HandleEntryEvent - подписчик outbox на entry.created и entry.changed: публикует изменение записи
+// и, если из-за него сменился результат анализа, в той же транзакции пишет в outbox alert.raised.
+// Ошибка возвращает событие релею на повтор
+func (e *UserEventService) HandleEntryEvent(ctx context.Context, event domain.DomainEvent) error {
+	ctx, span := startSpan(ctx, "UserEventService.HandleEntryEvent")
+	defer span.End()
+	var payload domain.UserEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("parse entry event payload: %w", err)
+	}
+	key, err := e.alertService.Evaluate(ctx, event.UserId)
+	if err != nil {
+		return err
+	}
+	changed, err := e.alertChanged(ctx, event.UserId, key)
+	if err != nil {
+		return err
+	}
+	alertEvent, err := newDomainEvent(domain.DomainEventAlertRaised, event.UserId, domain.UserEventPayload{Key: key})
+	if err != nil {
+		return err
+	}
+	err = e.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := e.userEventRepository.CreateUserEvent(ctx, event.UserId, domain.UserEventType(event.Type), payload); err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		return e.outbox.AddOutboxEvents(ctx, alertEvent)
+	})
+	if err != nil {
+		return err
+	}
+	if changed {
+		e.alertService.AlertRaised(ctx, event.UserId, key)
+	}
+	return nil
+}
+
+// HandleAlertEvent - подписчик outbox на alert.raised: публикует алерт в поток пользователя.
+// Повтор того же результата (повторная доставка или два изменения в одном проходе релея) пропускается
+func (e *UserEventService) HandleAlertEvent(ctx context.Context, event domain.DomainEvent) error {
+	ctx, span := startSpan(ctx, "UserEventService.HandleAlertEvent")
+	defer span.End()
+	var payload domain.UserEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("parse alert event payload: %w", err)
+	}
+	changed, err := e.alertChanged(ctx, event.UserId, payload.Key)
+	if err != nil || !changed {
+		return err
+	}
+	_, err = e.userEventRepository.CreateUserEvent(ctx, event.UserId, domain.UserEventAlert, payload)
+	return err
+}
+
+// alertChanged - результат анализа отличается от последнего опубликованного алерта, включая возврат к alert.ok;
+// без опубликованных алертов alert.ok изменением не считается
+func (e *UserEventService) alertChanged(ctx context.Context, userId uuid.UUID, key domain.MessageKey) (bool, error) {
+	last, err := e.userEventRepository.GetLastUserEvent(ctx, userId, domain.UserEventAlert)
+	if errors.Is(err, repository.ErrNoRow) {
+		return key != domain.MessageAlertOk, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return last.Payload.Key != key, nil
+}
+
+// Subscribe регистрирует поток; lastEventId > 0 - клиент переподключился и догоняет события после него
+func (e *UserEventService) Subscribe(ctx context.Context, userId uuid.UUID, lastEventId int64) (*UserEventSubscription, error) {
+	ctx, span := startSpan(ctx, "UserEventService.Subscribe")
+	defer span.End()
+	subscription := &UserEventSubscription{
+		userId: userId,
+		events: make(chan domain.UserEvent, userEventBuffer),
+	}
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil, ErrShuttingDown
+	}
+	if len(e.subscribers[userId]) >= e.config.MaxStreams {
+		e.mu.Unlock()
+		return nil, ErrTooManyStreams
+	}
+	if e.subscribers[userId] == nil {
+		e.subscribers[userId] = map[*UserEventSubscription]struct{}{}
+	}
+	e.subscribers[userId][subscription] = struct{}{}
+	e.mu.Unlock()
+
+	// подписка раньше чтения базы: событие между ними придет дважды, а не потеряется; дубли отсекает клиент потока по Id
+	for afterId := lastEventId; afterId > 0; {
+		page, err := e.userEventRepository.GetUserEventsAfter(ctx, userId, afterId, userEventReplayPage)
+		if err != nil {
+			e.Unsubscribe(subscription)
+			return nil, err
+		}
+		subscription.Replay = append(subscription.Replay, page...)
+		if len(page) < userEventReplayPage {
+			break
+		}
+		afterId = page[len(page)-1].Id
+	}
+	return subscription, nil
+}
+
+func (e *UserEventService) Unsubscribe(subscription *UserEventSubscription) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.remove(subscription)
+}
+
+// remove вызывается под mu
+func (e *UserEventService) remove(subscription *UserEventSubscription) {
+	subscriptions := e.subscribers[subscription.userId]
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(e.subscribers, subscription.userId)
+	}
+	close(subscription.events)
+}
+
+// dispatch не блокируется на медленном клиенте - его поток закрывается
+func (e *UserEventService) dispatch(event domain.UserEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for subscription := range e.subscribers[event.UserId] {
+		select {
+		case subscription.events <- event:
+		default:
+			e.remove(subscription)
+		}
+	}
+}
+
+// CloseStreams завершает все потоки и не дает открыть новые: без этого Shutdown сервера
+// ждал бы SSE соединения до таймаута. Клиенты переподключаются к другой реплике
+func (e *UserEventService) CloseStreams() {
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+	e.closeAll()
+}
+
+// closeAll - события за время переподключения LISTEN потеряны, клиенты догонят их по Last-Event-ID
+func (e *UserEventService) closeAll() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, subscriptions := range e.subscribers {
+		for subscription := range subscriptions {
+			e.remove(subscription)
+		}
+	}
+}
+
+// Run слушает события всех реплик и чистит старые до отмены ctx
+func (e *UserEventService) Run(ctx context.Context) {
+	go e.cleanup(ctx)
+	retry := time.Second
+	for {
+		started := time.Now()
+		err := e.listener.Listen(ctx, e.dispatch)
+		e.closeAll()
+		if ctx.Err() != nil {
+			return
+		}
+		// соединение жило долго - это новый сбой, а не серия неудачных попыток
+		if time.Since(started) > userEventRetryMax {
+			retry = time.Second
+		}
+		e.log.WithError(err).WithField("retry_in", retry.String()).Error("user events listener failed")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, userEventRetryMax)
+	}
+}
+
+func (e *UserEventService) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(userEventCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := e.userEventRepository.DeleteUserEventsBefore(ctx, time.Now().Add(-e.config.Retention))
+			if err != nil {
+				e.log.WithError(err).Error("user events cleanup failed")
+				continue
+			}
+			e.log.WithField("deleted", deleted).Debug("user events cleanup")
+		}
+	}
+}
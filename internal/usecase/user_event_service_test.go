@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Моки
+// Мок репозитория событий - события в памяти, Id по порядку
+type MockUserEventRepository struct {
+	events []domain.UserEvent
+}
+
+func (m *MockUserEventRepository) CreateUserEvent(ctx context.Context, userId uuid.UUID, eventType domain.UserEventType, payload domain.UserEventPayload) (domain.UserEvent, error) {
+	event := domain.UserEvent{Id: int64(len(m.events) + 1), UserId: userId, Type: eventType, Payload: payload, CreatedAt: time.Now()}
+	m.events = append(m.events, event)
+	return event, nil
+}
+
+func (m *MockUserEventRepository) GetUserEventsAfter(ctx context.Context, userId uuid.UUID, afterId int64, limit int) ([]domain.UserEvent, error) {
+	events := []domain.UserEvent{}
+	for _, event := range m.events {
+		if event.UserId == userId && event.Id > afterId && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *MockUserEventRepository) GetLastUserEvent(ctx context.Context, userId uuid.UUID, eventType domain.UserEventType) (domain.UserEvent, error) {
+	for i := len(m.events) - 1; i >= 0; i-- {
+		if m.events[i].UserId == userId && m.events[i].Type == eventType {
+			return m.events[i], nil
+		}
+	}
+	return domain.UserEvent{}, repository.ErrNoRow
+}
+
+func (m *MockUserEventRepository) DeleteUserEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// Мок метрик - запоминает правила сработавших алертов
+type MockMetrics struct {
+	noopMetrics
+	alerts []string
+}
+
+func (m *MockMetrics) AlertTriggered(rule string) {
+	m.alerts = append(m.alerts, rule)
+}
+
+var testEventsConfig = domain.EventsConfig{HeartbeatInterval: time.Second, Retention: time.Hour, MaxStreams: 2}
+
+// badDays - три дня подряд с плохим настроением и высокой нагрузкой
+func badDays() []domain.Day {
+	days := []domain.Day{}
+	for i := 3; i >= 1; i-- {
+		days = append(days, domain.Day{Date: time.Date(2025, 1, i, 0, 0, 0, 0, time.Now().Location()), Mood: 4, SleepHours: 9, Load: 6})
+	}
+	return days
+}
+
+// Тест HandleEntryEvent - alert.raised пишется в outbox в транзакции изменения только при смене результата анализа,
+// HandleAlertEvent публикует его в поток
+func TestHandleEntryEventPublishesAlertOnChange(t *testing.T) {
+	// preparing
+	days := []domain.Day{}
+	mockAlertRepository := &MockAlertRepository{
+		GetLastSevenDaysFn: func(ctx context.Context, userId uuid.UUID) ([]domain.Day, error) {
+			return days, nil
+		},
+	}
+	mockUserEventRepository := &MockUserEventRepository{}
+	mockTransactor, mockOutbox, mockMetrics := &MockTransactor{}, &MockOutbox{}, &MockMetrics{}
+	alertService := NewAlertServcie(mockAlertRepository, testAlertRules, mockMetrics)
+	service := NewUserEventService(mockUserEventRepository, nil, alertService, mockTransactor, mockOutbox, testEventsConfig, logrus.New())
+	ctx, userId := context.Background(), uuid.New()
+	mood := int16(4)
+	event, err := newDomainEvent(domain.DomainEventEntryChanged, userId, domain.UserEventPayload{Date: "2025-01-03", Mood: &mood})
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	// handle - как релей: изменение записи, затем новые события outbox подписчику alert.raised
+	errs := []error{}
+	handle := func() {
+		published := len(mockOutbox.events)
+		errs = append(errs, service.HandleEntryEvent(ctx, event))
+		for _, raised := range mockOutbox.events[published:] {
+			errs = append(errs, service.HandleAlertEvent(ctx, raised))
+		}
+	}
+
+	// test
+	handle()
+	days = badDays()
+	handle()
+	handle()
+	_, readErr := alertService.GetLastSevenDays(ctx, userId)
+	days = nil
+	handle()
+	errs = append(errs, readErr)
+
+	// assert
+	if err := errors.Join(errs...); err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if len(mockOutbox.events) != 2 || mockOutbox.events[0].Type != domain.DomainEventAlertRaised || !mockOutbox.inTx {
+		t.Errorf("ожидалось два alert.raised внутри транзакции, получено - %v", mockOutbox.events)
+	}
+	if len(mockMetrics.alerts) != 1 || mockMetrics.alerts[0] != alertRuleMoodLoad {
+		t.Errorf("ожидался один сработавший алерт %v, получено - %v", alertRuleMoodLoad, mockMetrics.alerts)
+	}
+	if mockTransactor.committed != 4 {
+		t.Errorf("ожидалось 4 транзакции, получено - %v", mockTransactor.committed)
+	}
+	types := []domain.UserEventType{}
+	keys := []domain.MessageKey{}
+	for _, event := range mockUserEventRepository.events {
+		types = append(types, event.Type)
+		if event.Type == domain.UserEventAlert {
+			keys = append(keys, event.Payload.Key)
+		}
+	}
+	expected := []domain.UserEventType{
+		domain.UserEventEntryChanged,
+		domain.UserEventEntryChanged, domain.UserEventAlert,
+		domain.UserEventEntryChanged,
+		domain.UserEventEntryChanged, domain.UserEventAlert,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("ожидались события %v, получены - %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("ожидались события %v, получены - %v", expected, types)
+		}
+	}
+	if keys[0] != domain.MessageAlertMoodLoad || keys[1] != domain.MessageAlertOk {
+		t.Errorf("ожидались алерты %v и %v, получены - %v", domain.MessageAlertMoodLoad, domain.MessageAlertOk, keys)
+	}
+	if mockAlertRepository.getLastSevenDaysFnIsCalled != true {
+		t.Errorf("ожидался анализ после изменения записи")
+	}
+}
+
+// Тест HandleAlertEvent - повторная доставка того же алерта не публикуется дважды
+func TestHandleAlertEventSkipsRepeat(t *testing.T) {
+	// preparing
+	mockUserEventRepository := &MockUserEventRepository{}
+	service := NewUserEventService(mockUserEventRepository, nil, nil, nil, nil, testEventsConfig, logrus.New())
+	ctx, userId := context.Background(), uuid.New()
+	event, err := newDomainEvent(domain.DomainEventAlertRaised, userId, domain.UserEventPayload{Key: domain.MessageAlertMoodLoad})
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+
+	// test
+	errFirst := service.HandleAlertEvent(ctx, event)
+	errSecond := service.HandleAlertEvent(ctx, event)
+
+	// assert
+	if errFirst != nil || errSecond != nil {
+		t.Fatalf("ошибки не ожидалось - %v, %v", errFirst, errSecond)
+	}
+	if len(mockUserEventRepository.events) != 1 || mockUserEventRepository.events[0].Type != domain.UserEventAlert {
+		t.Errorf("ожидался один алерт, получено - %v", mockUserEventRepository.events)
+	}
+}
+
+// Тест Subscribe - догон по Last-Event-ID, лимит потоков и доставка только своему пользователю
+func TestSubscribe(t *testing.T) {
+	// preparing
+	mockUserEventRepository := &MockUserEventRepository{}
+	service := NewUserEventService(mockUserEventRepository, nil, nil, nil, nil, testEventsConfig, logrus.New())
+	ctx, userId, otherId := context.Background(), uuid.New(), uuid.New()
+	for range 3 {
+		mockUserEventRepository.CreateUserEvent(ctx, userId, domain.UserEventEntryCreated, domain.UserEventPayload{})
+	}
+
+	// test
+	subscription, err := service.Subscribe(ctx, userId, 1)
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось, получена - %v", err)
+	}
+	_, errSecond := service.Subscribe(ctx, userId, 0)
+	_, errThird := service.Subscribe(ctx, userId, 0)
+	service.dispatch(domain.UserEvent{Id: 4, UserId: otherId})
+	service.dispatch(domain.UserEvent{Id: 5, UserId: userId})
+
+	// assert
+	if len(subscription.Replay) != 2 || subscription.Replay[0].Id != 2 {
+		t.Errorf("ожидался догон событий 2 и 3, получено - %v", subscription.Replay)
+	}
+	if errSecond != nil || !errors.Is(errThird, ErrTooManyStreams) {
+		t.Errorf("ожидался лимит на третьем потоке, получено - %v, %v", errSecond, errThird)
+	}
+	select {
+	case event := <-subscription.Events():
+		if event.Id != 5 {
+			t.Errorf("ожидалось событие 5, получено - %v", event.Id)
+		}
+	default:
+		t.Errorf("ожидалось событие 5")
+	}
+}
+
+// Тест - медленный клиент отключается, после CloseStreams новые потоки не открываются
+func TestUserEventStreamsClosed(t *testing.T) {
+	// preparing
+	service := NewUserEventService(&MockUserEventRepository{}, nil, nil, nil, nil, testEventsConfig, logrus.New())
+	ctx, userId := context.Background(), uuid.New()
+	slow, _ := service.Subscribe(ctx, userId, 0)
+
+	// test
+	for i := range userEventBuffer + 1 {
+		service.dispatch(domain.UserEvent{Id: int64(i + 1), UserId: userId})
+	}
+	service.CloseStreams()
+	_, err := service.Subscribe(ctx, userId, 0)
+
+	// assert
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received != userEventBuffer {
+		t.Errorf("ожидалось %v событий до закрытия, получено - %v", userEventBuffer, received)
+	}
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("ожидалась ошибка - %v, получена - %v", ErrShuttingDown, err)
+	}
+}
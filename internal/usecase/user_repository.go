@@ -3,6 +3,7 @@ package usecase
 import (
 	"chopper/internal/domain"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,4 +12,9 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, uuid uuid.UUID, username, email, hashPassword string, role domain.Role) error
 	CheckUser(ctx context.Context, username string) (domain.User, error)
 	GetIdUsernameRole(ctx context.Context, id uuid.UUID, username string) (domain.UserWhoAmI, error)
+	SetPasswordHash(ctx context.Context, username, hashPassword string) error
+	SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error
+	GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error)
+	SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error
+	SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error
 }
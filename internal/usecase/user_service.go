@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"chopper/internal/logger"
 	"chopper/internal/repository"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,19 +17,46 @@ type UserService struct {
 	jwtService     JwtGenerator
 	passwordHasher PasswordHasher
 	uuidGenerator  UUIDGenerator
+	metrics        MetricsRecorder
+	transactor     Transactor
+	outbox         OutboxWriter
 }
 
-func NewUserService(userRepository UserRepository, jwtService JwtGenerator, passwordHasher PasswordHasher, uuidGenerator UUIDGenerator) *UserService {
+func NewUserService(userRepository UserRepository, jwtService JwtGenerator, passwordHasher PasswordHasher, uuidGenerator UUIDGenerator, metrics MetricsRecorder, transactor Transactor, outbox OutboxWriter) *UserService {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	if transactor == nil {
+		transactor = noopTransactor{}
+	}
+	if outbox == nil {
+		outbox = noopOutbox{}
+	}
 	return &UserService{
 		userRepository: userRepository,
 		jwtService:     jwtService,
 		passwordHasher: passwordHasher,
 		uuidGenerator:  uuidGenerator,
+		metrics:        metrics,
+		transactor:     transactor,
+		outbox:         outbox,
 	}
 }
 
 func (u *UserService) CreateUser(ctx context.Context, userRegisterFromFront domain.UserRegisterFromFront) error {
-	defaultRole := domain.RoleUser
+	ctx, span := startSpan(ctx, "UserService.CreateUser")
+	defer span.End()
+	return u.createUser(ctx, userRegisterFromFront, domain.RoleUser)
+}
+
+// CreateAdmin - только для CLI (первый администратор), через API админа создать нельзя
+func (u *UserService) CreateAdmin(ctx context.Context, userRegisterFromFront domain.UserRegisterFromFront) error {
+	ctx, span := startSpan(ctx, "UserService.CreateAdmin")
+	defer span.End()
+	return u.createUser(ctx, userRegisterFromFront, domain.RoleAdmin)
+}
+
+func (u *UserService) createUser(ctx context.Context, userRegisterFromFront domain.UserRegisterFromFront, role domain.Role) error {
 	username := userRegisterFromFront.Username
 	email := userRegisterFromFront.Email
 	passwordHash, err := u.passwordHasher.GenerateFromPassword(userRegisterFromFront.Password)
@@ -34,7 +64,17 @@ func (u *UserService) CreateUser(ctx context.Context, userRegisterFromFront doma
 		return err
 	}
 	uuid := u.uuidGenerator.NewId()
-	if err := u.userRepository.CreateUser(ctx, uuid, username, email, string(passwordHash), defaultRole); err != nil && errors.Is(err, repository.ErrUniqueViolation) {
+	event, err := newDomainEvent(domain.DomainEventUserRegistered, uuid, domain.UserRegisteredPayload{Username: username, Role: role})
+	if err != nil {
+		return err
+	}
+	err = u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.userRepository.CreateUser(ctx, uuid, username, email, string(passwordHash), role); err != nil {
+			return err
+		}
+		return u.outbox.AddOutboxEvents(ctx, event)
+	})
+	if err != nil && errors.Is(err, repository.ErrUniqueViolation) {
 		return ErrUserExists
 	} else if err != nil {
 		return err
@@ -43,24 +83,38 @@ func (u *UserService) CreateUser(ctx context.Context, userRegisterFromFront doma
 }
 
 func (u *UserService) CheckUserInDatabase(ctx context.Context, userLoginFromFront domain.UserLoginFromFront) (string, error) {
+	ctx, span := startSpan(ctx, "UserService.CheckUserInDatabase")
+	defer span.End()
 	username := userLoginFromFront.Username
 	user, err := u.userRepository.CheckUser(ctx, username)
 	if err != nil && errors.Is(err, repository.ErrNoRow) {
+		logger.FromContext(ctx).WithField("username", username).Info("login failed: user not exist")
+		u.metrics.LoginFailed()
 		return "", ErrUserNotExist
 	} else if err != nil {
 		return "", err
 	}
 	if err := u.passwordHasher.CompareHashAndPassword(user.HashPassword, userLoginFromFront.Password); err != nil {
+		logger.FromContext(ctx).WithField("user_id", user.Id).Info("login failed: wrong password")
+		u.metrics.LoginFailed()
 		return "", ErrWrongPassword
 	}
+	if user.SuspendedAt != nil {
+		logger.FromContext(ctx).WithField("user_id", user.Id).Info("login failed: user is suspended")
+		u.metrics.LoginFailed()
+		return "", ErrUserSuspended
+	}
 	token, err := u.jwtService.GenerateToken(user.Id, user.Username, user.Email, user.Role)
 	if err != nil {
 		return "", err
 	}
+	u.metrics.LoginSucceeded()
 	return token, nil
 }
 
 func (u *UserService) GetIdUsernameRole(ctx context.Context, id uuid.UUID, username string) (domain.UserWhoAmI, error) {
+	ctx, span := startSpan(ctx, "UserService.GetIdUsernameRole")
+	defer span.End()
 	user, err := u.userRepository.GetIdUsernameRole(ctx, id, username)
 	if err != nil && errors.Is(err, repository.ErrNoRow) {
 		return user, ErrUserNotExist
@@ -69,3 +123,84 @@ func (u *UserService) GetIdUsernameRole(ctx context.Context, id uuid.UUID, usern
 	}
 	return user, nil
 }
+
+func (u *UserService) SetPassword(ctx context.Context, username, password string) error {
+	ctx, span := startSpan(ctx, "UserService.SetPassword")
+	defer span.End()
+	passwordHash, err := u.passwordHasher.GenerateFromPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepository.SetPasswordHash(ctx, username, passwordHash); err != nil && errors.Is(err, repository.ErrNoRow) {
+		return ErrUserNotExist
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SuspendUser блокирует вход, JWT сессии и персональные токены пользователя
+func (u *UserService) SuspendUser(ctx context.Context, username string) error {
+	ctx, span := startSpan(ctx, "UserService.SuspendUser")
+	defer span.End()
+	if err := u.userRepository.SuspendUser(ctx, username, time.Now()); err != nil && errors.Is(err, repository.ErrNoRow) {
+		return ErrUserNotExist
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CheckActive вызывается на каждый запрос с JWT, чтобы блокировка действовала сразу, а не после истечения токена.
+// Статус заодно несет языковую настройку, чтобы не ходить за ней в базу отдельно
+func (u *UserService) CheckActive(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	ctx, span := startSpan(ctx, "UserService.CheckActive")
+	defer span.End()
+	status, err := u.userRepository.GetUserStatus(ctx, id)
+	if err != nil && errors.Is(err, repository.ErrNoRow) {
+		return domain.UserStatus{}, ErrUserNotExist
+	} else if err != nil {
+		return domain.UserStatus{}, err
+	}
+	if !status.Active {
+		return domain.UserStatus{}, ErrUserSuspended
+	}
+	return status, nil
+}
+
+// SetLanguage сохраняет язык ответов пользователя; пустая строка сбрасывает настройку к Accept-Language
+func (u *UserService) SetLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	ctx, span := startSpan(ctx, "UserService.SetLanguage")
+	defer span.End()
+	if language != "" && !i18n.Supported(language) {
+		return ErrWrongLanguage
+	}
+	if err := u.userRepository.SetUserLanguage(ctx, id, language); err != nil && errors.Is(err, repository.ErrNoRow) {
+		return ErrUserNotExist
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetReminder включает ежедневное напоминание в reminderTime по часовому поясу timeZone; пустой reminderTime выключает его
+func (u *UserService) SetReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	ctx, span := startSpan(ctx, "UserService.SetReminder")
+	defer span.End()
+	if reminderTime != "" {
+		if !domain.ValidTimeZone(timeZone) {
+			return ErrWrongTimeZone
+		}
+		if !domain.ValidReminderTime(reminderTime) {
+			return ErrWrongReminderTime
+		}
+	} else if timeZone != "" && !domain.ValidTimeZone(timeZone) {
+		return ErrWrongTimeZone
+	}
+	if err := u.userRepository.SetUserReminder(ctx, id, timeZone, reminderTime); err != nil && errors.Is(err, repository.ErrNoRow) {
+		return ErrUserNotExist
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
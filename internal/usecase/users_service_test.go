@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -41,6 +42,26 @@ func (m *MockUserRepositorySuccess) GetIdUsernameRole(ctx context.Context, id uu
 	return domain.UserWhoAmI{}, nil
 }
 
+func (m *MockUserRepositorySuccess) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositorySuccess) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 // мок хэша
 type MockPasswordHasherSuccess struct {
 	generateWasCalled    bool
@@ -81,7 +102,7 @@ func TestCreateUserSuccess(t *testing.T) {
 	mockUserRepository := &MockUserRepositorySuccess{}
 	mockPasswordHasher := &MockPasswordHasherSuccess{}
 	mockIdGenerator := &MockIdGeneratorSuccess{}
-	service := NewUserService(mockUserRepository, nil, mockPasswordHasher, mockIdGenerator)
+	service := NewUserService(mockUserRepository, nil, mockPasswordHasher, mockIdGenerator, nil, nil, nil)
 
 	//test
 	err := service.CreateUser(ctx, userRegisterFromFront)
@@ -145,7 +166,7 @@ func TestCreateUserFailureLongPassword(t *testing.T) {
 	mockUserRepository := &MockUserRepositorySuccess{}
 	mockPasswordHasher := &MockPasswordHasherFailureLongPassword{}
 	mockIdGenerator := &MockIdGeneratorSuccess{}
-	service := NewUserService(mockUserRepository, nil, mockPasswordHasher, mockIdGenerator)
+	service := NewUserService(mockUserRepository, nil, mockPasswordHasher, mockIdGenerator, nil, nil, nil)
 
 	// test
 	err := service.CreateUser(ctx, userRegisterFromFront)
@@ -181,6 +202,26 @@ func (m *MockUserRepositoryFailure) GetIdUsernameRole(ctx context.Context, id uu
 	return domain.UserWhoAmI{}, nil
 }
 
+func (m *MockUserRepositoryFailure) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailure) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailure) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositoryFailure) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailure) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 func TestCreateserFailureRepositoryError(t *testing.T) {
 	// preparing
 	ctx, cancel := context.WithCancel(context.Background())
@@ -193,7 +234,7 @@ func TestCreateserFailureRepositoryError(t *testing.T) {
 	mockUserRepositoryFailure := &MockUserRepositoryFailure{}
 	mockPasswordHasherSuccess := &MockPasswordHasherSuccess{}
 	mockIdGeneratorSeuccess := &MockIdGeneratorSuccess{}
-	service := NewUserService(mockUserRepositoryFailure, nil, mockPasswordHasherSuccess, mockIdGeneratorSeuccess)
+	service := NewUserService(mockUserRepositoryFailure, nil, mockPasswordHasherSuccess, mockIdGeneratorSeuccess, nil, nil, nil)
 	expectedError := MockErrNeedError
 
 	// test
@@ -235,6 +276,26 @@ func (m *MockUserRepositorySuccess2) GetIdUsernameRole(ctx context.Context, id u
 	return domain.UserWhoAmI{}, nil
 }
 
+func (m *MockUserRepositorySuccess2) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess2) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess2) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositorySuccess2) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess2) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 // Мок хэша
 type MockHashPasswordSuccess2 struct {
 	wasCalled    bool
@@ -286,7 +347,7 @@ func TestCheckUserInDatabaseSuccess(t *testing.T) {
 	mockUserRepository := &MockUserRepositorySuccess2{}
 	mockJwtService := &MockJwtServiceSuccess2{}
 	mockHashPassword := &MockHashPasswordSuccess2{}
-	service := NewUserService(mockUserRepository, mockJwtService, mockHashPassword, nil)
+	service := NewUserService(mockUserRepository, mockJwtService, mockHashPassword, nil, nil, nil, nil)
 	expectedId := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	expectedUsername := "dexter"
 	expectedEmail := "dexter@email.com"
@@ -346,6 +407,26 @@ func (m *MockUserRepositoryFailureDatabaseError2) GetIdUsernameRole(ctx context.
 	return domain.UserWhoAmI{}, nil
 }
 
+func (m *MockUserRepositoryFailureDatabaseError2) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureDatabaseError2) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureDatabaseError2) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositoryFailureDatabaseError2) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureDatabaseError2) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 // Мок хэша
 type MockPasswordHashFailureDatabaseError2 struct {
 	wasCalled bool
@@ -386,7 +467,7 @@ func TestCheckUserInDatabaseFailureDatabaseError(t *testing.T) {
 	mockJwtService := &MockJwtServiceFailureDatabaseError2{}
 	mockPasswordHash := &MockPasswordHashFailureDatabaseError2{}
 	expectedError := MockErrUserNotExists
-	service := NewUserService(mockUserRepository, mockJwtService, mockPasswordHash, nil)
+	service := NewUserService(mockUserRepository, mockJwtService, mockPasswordHash, nil, nil, nil, nil)
 
 	// test
 	token, err := service.CheckUserInDatabase(ctx, userLoginFromFront)
@@ -430,6 +511,26 @@ func (m *MockUserRepositoryFailureWrongPassword3) GetIdUsernameRole(ctx context.
 	return domain.UserWhoAmI{}, nil
 }
 
+func (m *MockUserRepositoryFailureWrongPassword3) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureWrongPassword3) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureWrongPassword3) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositoryFailureWrongPassword3) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureWrongPassword3) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 // Мок хэша
 type MockPasswordHashFailureWrongPassword3 struct {
 	wasCalled bool
@@ -471,7 +572,7 @@ func TestCheckUserInDatabaseFailureWrongPassword(t *testing.T) {
 	mockUserRepository := &MockUserRepositoryFailureWrongPassword3{}
 	mockJwtService := &MockJwtServiceFailureWrongPassword3{}
 	mockPasswordHash := &MockPasswordHashFailureWrongPassword3{}
-	service := NewUserService(mockUserRepository, mockJwtService, mockPasswordHash, nil)
+	service := NewUserService(mockUserRepository, mockJwtService, mockPasswordHash, nil, nil, nil, nil)
 	expectedError := ErrWrongPassword
 
 	// test
@@ -531,6 +632,26 @@ func (m *MockUserRepositoryFailureTokenGeneration4) GetIdUsernameRole(ctx contex
 	return domain.UserWhoAmI{}, nil
 }
 
+func (m *MockUserRepositoryFailureTokenGeneration4) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureTokenGeneration4) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureTokenGeneration4) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositoryFailureTokenGeneration4) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureTokenGeneration4) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 // Мок jwt
 type MockJwtServiceFailureTokenGeneration4 struct {
 	wasCalled bool
@@ -579,7 +700,7 @@ func TestTestCheckUserInDatabaseFailureTokenError(t *testing.T) {
 	mockJwtService := &MockJwtServiceFailureTokenGeneration4{}
 	mockPasswordHash := &MockPasswordHashFailureTokenGeneration4{}
 	expectedError := MockErrWhileToken
-	service := NewUserService(mockUserRepository, mockJwtService, mockPasswordHash, nil)
+	service := NewUserService(mockUserRepository, mockJwtService, mockPasswordHash, nil, nil, nil, nil)
 
 	// test
 	token, err := service.CheckUserInDatabase(ctx, userLoginFromFront)
@@ -648,13 +769,33 @@ func (m *MockUserRepositorySuccess3) GetIdUsernameRole(ctx context.Context, id u
 	}, nil
 }
 
+func (m *MockUserRepositorySuccess3) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess3) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess3) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositorySuccess3) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositorySuccess3) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 func TestGetIdUsernameRoleSuccess(t *testing.T) {
 	// preparing
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	id, username := uuid.MustParse("11111111-1111-1111-1111-111111111111"), "dexter"
 	mockUserRepository := &MockUserRepositorySuccess3{}
-	service := NewUserService(mockUserRepository, nil, nil, nil)
+	service := NewUserService(mockUserRepository, nil, nil, nil, nil, nil, nil)
 
 	// test
 	user, err := service.GetIdUsernameRole(ctx, id, username)
@@ -709,13 +850,33 @@ func (m *MockUserRepositoryFailureErrNoRows5) GetIdUsernameRole(ctx context.Cont
 	return domain.UserWhoAmI{}, MockErrNoRows
 }
 
+func (m *MockUserRepositoryFailureErrNoRows5) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureErrNoRows5) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureErrNoRows5) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositoryFailureErrNoRows5) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailureErrNoRows5) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 func TestGetIdUsernameRoleFailureErrNoRows(t *testing.T) {
 	// preparing
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	mockUserRepository := &MockUserRepositoryFailureErrNoRows5{}
 	id, username := uuid.MustParse("11111111-1111-1111-1111-111111111111"), "dexter"
-	service := NewUserService(mockUserRepository, nil, nil, nil)
+	service := NewUserService(mockUserRepository, nil, nil, nil, nil, nil, nil)
 	expectedError := ErrUserNotExist
 
 	// test
@@ -762,13 +923,33 @@ func (m *MockUserRepositoryFailure6) GetIdUsernameRole(ctx context.Context, id u
 	return domain.UserWhoAmI{}, MockNeedErr
 }
 
+func (m *MockUserRepositoryFailure6) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailure6) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailure6) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: true}, nil
+}
+
+func (m *MockUserRepositoryFailure6) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	return nil
+}
+
+func (m *MockUserRepositoryFailure6) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	return nil
+}
+
 func TestGetIdUsernameRoleFailureError(t *testing.T) {
 	// preparing
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	id, username := uuid.MustParse("11111111-1111-1111-1111-111111111111"), "dexter"
 	mockUserRepository := &MockUserRepositoryFailure6{}
-	service := NewUserService(mockUserRepository, nil, nil, nil)
+	service := NewUserService(mockUserRepository, nil, nil, nil, nil, nil, nil)
 	expectedError := MockNeedErr
 
 	// test
@@ -788,3 +969,284 @@ func TestGetIdUsernameRoleFailureError(t *testing.T) {
 		t.Errorf("ожидался username - %v", username)
 	}
 }
+
+// Тест CreateAdmin - успех (роль администратора)
+func TestCreateAdminSuccess(t *testing.T) {
+	// preparing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	userRegisterFromFront := domain.UserRegisterFromFront{
+		Username: "dexter",
+		Email:    "dexter@email.com",
+		Password: "bay harbour butcher",
+	}
+	mockUserRepository := &MockUserRepositorySuccess{}
+	service := NewUserService(mockUserRepository, nil, &MockPasswordHasherSuccess{}, &MockIdGeneratorSuccess{}, nil, nil, nil)
+
+	// test
+	err := service.CreateAdmin(ctx, userRegisterFromFront)
+
+	// assert
+	if err != nil {
+		t.Errorf("ошибки не ожидалось")
+	}
+	if mockUserRepository.recievedRole != domain.RoleAdmin {
+		t.Errorf("ожидалась роль - %v", domain.RoleAdmin)
+	}
+}
+
+// Тест CheckUserInDatabase - провал (пользователь заблокирован)
+// Мок репозитория
+type MockUserRepositorySuspended7 struct {
+	MockUserRepositorySuccess2
+}
+
+func (m *MockUserRepositorySuspended7) CheckUser(ctx context.Context, username string) (domain.User, error) {
+	user, err := m.MockUserRepositorySuccess2.CheckUser(ctx, username)
+	suspendedAt := time.Now()
+	user.SuspendedAt = &suspendedAt
+	return user, err
+}
+
+func TestCheckUserInDatabaseFailureSuspended(t *testing.T) {
+	// preparing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	userLoginFromFront := domain.UserLoginFromFront{
+		Username: "dexter",
+		Password: "morgan",
+	}
+	mockJwtService := &MockJwtServiceSuccess2{}
+	service := NewUserService(&MockUserRepositorySuspended7{}, mockJwtService, &MockHashPasswordSuccess2{}, nil, nil, nil, nil)
+
+	// test
+	token, err := service.CheckUserInDatabase(ctx, userLoginFromFront)
+
+	// assert
+	if !errors.Is(err, ErrUserSuspended) {
+		t.Errorf("ожидалась ошибка - %v", ErrUserSuspended)
+	}
+	if token != "" {
+		t.Errorf("токен не ожидался")
+	}
+	if mockJwtService.wasCalled {
+		t.Errorf("jwt не должен выдаваться заблокированному пользователю")
+	}
+}
+
+// Тест SetPassword, SuspendUser, CheckActive, SetLanguage
+// Мок репозитория
+type MockUserRepositoryAdmin8 struct {
+	MockUserRepositorySuccess
+	hashPassword string
+	suspended    string
+	language     string
+	reminder     string
+	active       bool
+	err          error
+}
+
+func (m *MockUserRepositoryAdmin8) SetPasswordHash(ctx context.Context, username, hashPassword string) error {
+	m.hashPassword = hashPassword
+	return m.err
+}
+
+func (m *MockUserRepositoryAdmin8) SuspendUser(ctx context.Context, username string, suspendedAt time.Time) error {
+	m.suspended = username
+	return m.err
+}
+
+func (m *MockUserRepositoryAdmin8) GetUserStatus(ctx context.Context, id uuid.UUID) (domain.UserStatus, error) {
+	return domain.UserStatus{Active: m.active, Language: m.language}, m.err
+}
+
+func (m *MockUserRepositoryAdmin8) SetUserLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	m.language = language
+	return m.err
+}
+
+func (m *MockUserRepositoryAdmin8) SetUserReminder(ctx context.Context, id uuid.UUID, timeZone, reminderTime string) error {
+	m.reminder = timeZone + " " + reminderTime
+	return m.err
+}
+
+func TestSetPasswordSuccess(t *testing.T) {
+	// preparing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockUserRepository := &MockUserRepositoryAdmin8{}
+	service := NewUserService(mockUserRepository, nil, &MockPasswordHasherSuccess{}, nil, nil, nil, nil)
+
+	// test
+	err := service.SetPassword(ctx, "dexter", "deb")
+
+	// assert
+	if err != nil {
+		t.Errorf("ошибки не ожидалось")
+	}
+	if mockUserRepository.hashPassword != "deb"+"morgan" {
+		t.Errorf("ожидался хэш - %v", "deb"+"morgan")
+	}
+}
+
+func TestSuspendUserFailureNotExist(t *testing.T) {
+	// preparing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockUserRepository := &MockUserRepositoryAdmin8{err: repository.ErrNoRow}
+	service := NewUserService(mockUserRepository, nil, nil, nil, nil, nil, nil)
+
+	// test
+	err := service.SuspendUser(ctx, "doakes")
+
+	// assert
+	if !errors.Is(err, ErrUserNotExist) {
+		t.Errorf("ожидалась ошибка - %v", ErrUserNotExist)
+	}
+	if mockUserRepository.suspended != "doakes" {
+		t.Errorf("ожидался username - %v", "doakes")
+	}
+}
+
+func TestCheckActive(t *testing.T) {
+	tests := []struct {
+		name     string
+		active   bool
+		err      error
+		expected error
+	}{
+		{"активный", true, nil, nil},
+		{"заблокирован", false, nil, ErrUserSuspended},
+		{"удален из базы", false, repository.ErrNoRow, ErrUserNotExist},
+		{"ошибка базы", false, MockNeedErr, MockNeedErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// preparing
+			service := NewUserService(&MockUserRepositoryAdmin8{active: tt.active, err: tt.err}, nil, nil, nil, nil, nil, nil)
+
+			// test
+			_, err := service.CheckActive(context.Background(), uuid.New())
+
+			// assert
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("ожидалась ошибка - %v, получена - %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckActiveReturnsLanguage(t *testing.T) {
+	// preparing
+	service := NewUserService(&MockUserRepositoryAdmin8{active: true, language: "en"}, nil, nil, nil, nil, nil, nil)
+
+	// test
+	status, err := service.CheckActive(context.Background(), uuid.New())
+
+	// assert
+	if err != nil {
+		t.Errorf("ошибки не ожидалось")
+	}
+	if status.Language != "en" {
+		t.Errorf("ожидался язык - %v, получен - %v", "en", status.Language)
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		err      error
+		expected error
+	}{
+		{"язык из каталога", "en", nil, nil},
+		{"сброс настройки", "", nil, nil},
+		{"неизвестный язык", "xx", nil, ErrWrongLanguage},
+		{"удален из базы", "ru", repository.ErrNoRow, ErrUserNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// preparing
+			mockUserRepository := &MockUserRepositoryAdmin8{language: "-", err: tt.err}
+			service := NewUserService(mockUserRepository, nil, nil, nil, nil, nil, nil)
+
+			// test
+			err := service.SetLanguage(context.Background(), uuid.New(), tt.language)
+
+			// assert
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("ожидалась ошибка - %v, получена - %v", tt.expected, err)
+			}
+			if tt.expected == nil && mockUserRepository.language != tt.language {
+				t.Errorf("ожидался сохраненный язык - %v, получен - %v", tt.language, mockUserRepository.language)
+			}
+		})
+	}
+}
+
+func TestSetReminder(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeZone     string
+		reminderTime string
+		err          error
+		expected     error
+	}{
+		{"включение", "Europe/Moscow", "21:30", nil, nil},
+		{"выключение", "Europe/Moscow", "", nil, nil},
+		{"выключение без пояса", "", "", nil, nil},
+		{"время без пояса", "", "21:30", nil, ErrWrongTimeZone},
+		{"пояс сервера", "Local", "21:30", nil, ErrWrongTimeZone},
+		{"неизвестный пояс", "Mars/Olympus", "21:30", nil, ErrWrongTimeZone},
+		{"неверное время", "Europe/Moscow", "25:00", nil, ErrWrongReminderTime},
+		{"удален из базы", "UTC", "08:00", repository.ErrNoRow, ErrUserNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// preparing
+			mockUserRepository := &MockUserRepositoryAdmin8{reminder: "-", err: tt.err}
+			service := NewUserService(mockUserRepository, nil, nil, nil, nil, nil, nil)
+
+			// test
+			err := service.SetReminder(context.Background(), uuid.New(), tt.timeZone, tt.reminderTime)
+
+			// assert
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("ожидалась ошибка - %v, получена - %v", tt.expected, err)
+			}
+			if expected := tt.timeZone + " " + tt.reminderTime; tt.expected == nil && mockUserRepository.reminder != expected {
+				t.Errorf("ожидалась сохраненная настройка - %q, получена - %q", expected, mockUserRepository.reminder)
+			}
+		})
+	}
+}
+
+// Тест CreateUser - user.registered пишется в outbox в транзакции регистрации, без почты
+func TestCreateUserWritesOutboxEvent(t *testing.T) {
+	// preparing
+	userRegisterFromFront := domain.UserRegisterFromFront{
+		Username: "dexter",
+		Email:    "dexter@email.com",
+		Password: "bay harbour butcher",
+	}
+	mockTransactor := &MockTransactor{}
+	mockOutbox := &MockOutbox{}
+	service := NewUserService(&MockUserRepositorySuccess{}, nil, &MockPasswordHasherSuccess{}, &MockIdGeneratorSuccess{}, nil, mockTransactor, mockOutbox)
+
+	// test
+	err := service.CreateUser(context.Background(), userRegisterFromFront)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if len(mockOutbox.events) != 1 || mockOutbox.events[0].Type != domain.DomainEventUserRegistered || !mockOutbox.inTx {
+		t.Fatalf("ожидалось событие %v в транзакции, получены - %v", domain.DomainEventUserRegistered, mockOutbox.events)
+	}
+	if mockTransactor.committed != 1 {
+		t.Errorf("ожидался коммит транзакции")
+	}
+	if expected := `{"username":"dexter","role":"USER"}`; string(mockOutbox.events[0].Payload) != expected {
+		t.Errorf("ожидался payload - %v, получен - %s", expected, mockOutbox.events[0].Payload)
+	}
+}
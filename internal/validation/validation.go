@@ -0,0 +1,266 @@
+// Package validation проверяет DTO из запросов по тегам binding и политику паролей.
+// Validator подключается в gin как binding.Validator и используется CLI для тех же правил
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	_ "embed"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords []byte
+
+// FieldError - ошибка одного поля, Field - имя из json тега.
+// Тексты - ключи i18n, переводятся на языке запроса; у пароля нарушений может быть несколько
+type FieldError struct {
+	Field    string
+	Problems []i18n.Message
+}
+
+func (f FieldError) Message(lang string) string {
+	messages := make([]string, 0, len(f.Problems))
+	for _, problem := range f.Problems {
+		messages = append(messages, problem.In(lang))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Error собирает ошибки всех полей сразу
+type Error struct {
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	problems := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		problems = append(problems, field.Field+" "+field.Message(i18n.Fallback))
+	}
+	return "validation failed: " + strings.Join(problems, "; ")
+}
+
+type Validator struct {
+	validate  *validator.Validate
+	policy    domain.PasswordPolicy
+	blocklist map[string]struct{}
+}
+
+func New(policy domain.PasswordPolicy) (*Validator, error) {
+	v := &Validator{
+		validate:  validator.New(validator.WithRequiredStructEnabled()),
+		policy:    policy,
+		blocklist: map[string]struct{}{},
+	}
+	if err := v.loadBlocklist(commonPasswords); err != nil {
+		return nil, err
+	}
+	if policy.BlocklistFile != "" {
+		data, err := os.ReadFile(policy.BlocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("read password blocklist: %w", err)
+		}
+		if err := v.loadBlocklist(data); err != nil {
+			return nil, err
+		}
+	}
+	// теги те же, что у gin, имена полей - из json, у параметров query - из form
+	v.validate.SetTagName("binding")
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	for tag, fn := range map[string]validator.Func{
+		"username":       func(fl validator.FieldLevel) bool { return domain.ValidUsername(fl.Field().String()) },
+		"email_length":   func(fl validator.FieldLevel) bool { return len(fl.Field().String()) <= domain.EmailMaxLength },
+		"password":       func(fl validator.FieldLevel) bool { return len(v.PasswordProblems(fl.Field().String())) == 0 },
+		"mood":           func(fl validator.FieldLevel) bool { return domain.ValidMood(int16(fl.Field().Int())) },
+		"sleep_hours":    func(fl validator.FieldLevel) bool { return domain.ValidSleepHours(fl.Field().Float()) },
+		"load":           func(fl validator.FieldLevel) bool { return domain.ValidLoad(int16(fl.Field().Int())) },
+		"scope":          func(fl validator.FieldLevel) bool { return domain.Scope(fl.Field().String()).IsValid() },
+		"api_token_name": func(fl validator.FieldLevel) bool { return validApiTokenName(fl.Field().String()) },
+		"api_token_days": func(fl validator.FieldLevel) bool { return validApiTokenDays(fl.Field().Int()) },
+		"language":       func(fl validator.FieldLevel) bool { return i18n.Supported(fl.Field().String()) },
+		"time_zone":      func(fl validator.FieldLevel) bool { return domain.ValidTimeZone(fl.Field().String()) },
+		"reminder_time":  func(fl validator.FieldLevel) bool { return domain.ValidReminderTime(fl.Field().String()) },
+	} {
+		if err := v.validate.RegisterValidation(tag, fn); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (v *Validator) loadBlocklist(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if password := strings.TrimSpace(scanner.Text()); password != "" {
+			v.blocklist[strings.ToLower(password)] = struct{}{}
+		}
+	}
+	return scanner.Err()
+}
+
+// ValidateStruct реализует binding.StructValidator; не структуры (например, map) не проверяются
+func (v *Validator) ValidateStruct(obj any) error {
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	if err := v.validate.Struct(value.Interface()); err != nil {
+		return v.translate(err)
+	}
+	return nil
+}
+
+// Engine реализует binding.StructValidator
+func (v *Validator) Engine() any {
+	return v.validate
+}
+
+// ValidatePassword - только политика паролей, для смены пароля без остальных полей
+func (v *Validator) ValidatePassword(password string) error {
+	if problems := v.PasswordProblems(password); len(problems) > 0 {
+		return &Error{Fields: []FieldError{{Field: "password", Problems: problems}}}
+	}
+	return nil
+}
+
+// PasswordProblems перечисляет все нарушенные требования политики
+func (v *Validator) PasswordProblems(password string) []i18n.Message {
+	problems := []i18n.Message{}
+	if utf8.RuneCountInString(password) < v.policy.MinLength {
+		problems = append(problems, i18n.Msg("validation.password_min_length", "n", v.policy.MinLength))
+	}
+	if len(password) > v.policy.MaxLength {
+		problems = append(problems, i18n.Msg("validation.password_max_length", "n", v.policy.MaxLength))
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if v.policy.RequireUpper && !upper {
+		problems = append(problems, i18n.Msg("validation.password_upper"))
+	}
+	if v.policy.RequireLower && !lower {
+		problems = append(problems, i18n.Msg("validation.password_lower"))
+	}
+	if v.policy.RequireDigit && !digit {
+		problems = append(problems, i18n.Msg("validation.password_digit"))
+	}
+	if v.policy.RequireSymbol && !symbol {
+		problems = append(problems, i18n.Msg("validation.password_symbol"))
+	}
+	if _, ok := v.blocklist[strings.ToLower(password)]; ok {
+		problems = append(problems, i18n.Msg("validation.password_common"))
+	}
+	return problems
+}
+
+func (v *Validator) translate(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	result := &Error{}
+	for _, fieldError := range validationErrors {
+		// namespace без имени корневой структуры: scopes[1], а не ApiTokenFromFront.scopes[1]
+		field := fieldError.Namespace()
+		if _, rest, found := strings.Cut(field, "."); found {
+			field = rest
+		}
+		result.Fields = append(result.Fields, FieldError{Field: field, Problems: v.problems(fieldError)})
+	}
+	return result
+}
+
+func (v *Validator) problems(fieldError validator.FieldError) []i18n.Message {
+	switch fieldError.Tag() {
+	case "required", "required_with":
+		return []i18n.Message{i18n.Msg("validation.required")}
+	case "email":
+		return []i18n.Message{i18n.Msg("validation.email")}
+	case "email_length":
+		return []i18n.Message{i18n.Msg("validation.max_length", "n", domain.EmailMaxLength)}
+	case "min", "max":
+		key := "validation." + fieldError.Tag()
+		switch fieldError.Kind() {
+		case reflect.String:
+			key += "_length"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			key += "_items"
+		}
+		return []i18n.Message{i18n.Msg(key, "n", fieldError.Param())}
+	case "username":
+		return []i18n.Message{i18n.Msg("validation.username", "min", domain.UsernameMinLength, "max", domain.UsernameMaxLength)}
+	case "password":
+		password, _ := fieldError.Value().(string)
+		return v.PasswordProblems(password)
+	case "mood":
+		return []i18n.Message{RangeMessage(domain.MoodMin, domain.MoodMax)}
+	case "sleep_hours":
+		return []i18n.Message{RangeMessage(domain.SleepHoursMin, domain.SleepHoursMax)}
+	case "load":
+		return []i18n.Message{RangeMessage(domain.LoadMin, domain.LoadMax)}
+	case "scope":
+		return []i18n.Message{i18n.Msg("validation.scope", "scopes", ScopesList())}
+	case "api_token_name":
+		return []i18n.Message{i18n.Msg("validation.api_token_name", "max", domain.ApiTokenNameMaxLength)}
+	case "api_token_days":
+		return []i18n.Message{RangeMessage(1, domain.ApiTokenMaxDays)}
+	case "language":
+		return []i18n.Message{i18n.Msg("validation.language", "languages", strings.Join(i18n.Languages(), ", "))}
+	case "time_zone":
+		return []i18n.Message{i18n.Msg("validation.time_zone")}
+	case "reminder_time":
+		return []i18n.Message{i18n.Msg("validation.reminder_time")}
+	}
+	return []i18n.Message{i18n.Msg("validation.invalid")}
+}
+
+// RangeMessage - общий текст для диапазонов из domain, им же пользуются ошибки сервисов
+func RangeMessage(min, max any) i18n.Message {
+	return i18n.Msg("validation.range", "min", min, "max", max)
+}
+
+func ScopesList() string {
+	return strings.Join([]string{string(domain.ScopeNotesRead), string(domain.ScopeNotesWrite), string(domain.ScopeAlertsRead), string(domain.ScopeUsersRead)}, ", ")
+}
+
+func validApiTokenName(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && len(name) <= domain.ApiTokenNameMaxLength
+}
+
+func validApiTokenDays(days int64) bool {
+	return days >= 1 && days <= domain.ApiTokenMaxDays
+}
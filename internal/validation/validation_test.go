@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"chopper/internal/domain"
+	"chopper/internal/i18n"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestValidator(t *testing.T, policy domain.PasswordPolicy) *Validator {
+	t.Helper()
+	if policy.MinLength == 0 {
+		policy.MinLength = 8
+	}
+	if policy.MaxLength == 0 {
+		policy.MaxLength = 72
+	}
+	v, err := New(policy)
+	if err != nil {
+		t.Fatalf("New - %v", err)
+	}
+	return v
+}
+
+func fieldMessages(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var validationErr *Error
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ожидалась *Error, получена - %v", err)
+	}
+	fields := map[string]string{}
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = field.Message(i18n.Fallback)
+	}
+	return fields
+}
+
+// Тест регистрации - ошибки всех полей возвращаются вместе
+func TestValidateStructRegisterAllFields(t *testing.T) {
+	// preparing
+	v := newTestValidator(t, domain.PasswordPolicy{})
+	user := &domain.UserRegisterFromFront{Username: "1dexter", Email: "not-an-email", Password: "qwerty"}
+
+	// test
+	fields := fieldMessages(t, v.ValidateStruct(user))
+
+	// assert
+	for _, name := range []string{"username", "email", "password"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("ожидалась ошибка поля %v, получены - %v", name, fields)
+		}
+	}
+	if !strings.Contains(fields["password"], "at least 8") || !strings.Contains(fields["password"], "too common") {
+		t.Errorf("ожидались все нарушения политики, получено - %v", fields["password"])
+	}
+}
+
+// Тест регистрации - длина email ограничена domain.EmailMaxLength
+func TestValidateStructRegisterEmailLength(t *testing.T) {
+	// preparing
+	v := newTestValidator(t, domain.PasswordPolicy{})
+	email := strings.Repeat("d", domain.EmailMaxLength) + "@miami.com"
+	user := domain.UserRegisterFromFront{Username: "dexter", Email: email, Password: "Bay-Harbor-1"}
+
+	// test
+	fields := fieldMessages(t, v.ValidateStruct(user))
+
+	// assert
+	if !strings.Contains(fields["email"], fmt.Sprintf("at most %v", domain.EmailMaxLength)) {
+		t.Errorf("ожидалось ограничение длины email, получено - %v", fields)
+	}
+}
+
+// Тест регистрации - корректный запрос проходит
+func TestValidateStructRegisterValid(t *testing.T) {
+	// preparing
+	v := newTestValidator(t, domain.PasswordPolicy{RequireUpper: true, RequireDigit: true})
+	user := domain.UserRegisterFromFront{Username: "dexter.morgan", Email: "dexter@miami.com", Password: "Bay-Harbor-1"}
+
+	// test
+	err := v.ValidateStruct(user)
+
+	// assert
+	if err != nil {
+		t.Errorf("ошибка не ожидалась, получена - %v", err)
+	}
+}
+
+// Тест политики паролей - классы символов, длина в байтах и блоклист из файла без учета регистра
+func TestPasswordPolicy(t *testing.T) {
+	// preparing
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte("Miami-Metro-2006\n\n"), 0o600); err != nil {
+		t.Fatalf("write test file - %v", err)
+	}
+	v := newTestValidator(t, domain.PasswordPolicy{
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		BlocklistFile: blocklist,
+	})
+	tests := []struct {
+		password string
+		problem  string
+	}{
+		{"lowercase-only-1", "uppercase"},
+		{"UPPERCASE-ONLY-1", "lowercase"},
+		{"No-Digits-Here", "digit"},
+		{"NoSymbols123", "symbol"},
+		{"miami-metro-2006", "too common"},
+		{"Dexter-Morgan-1", ""},
+		{"Aa1!" + strings.Repeat("я", 40), "at most 72 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			// test
+			err := v.ValidatePassword(tt.password)
+
+			// assert
+			if tt.problem == "" {
+				if err != nil {
+					t.Errorf("ошибка не ожидалась, получена - %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("ожидалась ошибка с %q, получена - %v", tt.problem, err)
+			}
+		})
+	}
+}
+
+// Тест - блоклист из несуществующего файла не загружается
+func TestNewBlocklistFileMissing(t *testing.T) {
+	// test
+	_, err := New(domain.PasswordPolicy{MinLength: 8, MaxLength: 72, BlocklistFile: filepath.Join(t.TempDir(), "missing.txt")})
+
+	// assert
+	if err == nil {
+		t.Errorf("ожидалась ошибка")
+	}
+}
+
+// Тест записей и токенов - диапазоны из domain и имена полей из json, включая элементы списков
+func TestValidateStructRanges(t *testing.T) {
+	// preparing
+	v := newTestValidator(t, domain.PasswordPolicy{})
+	tests := []struct {
+		name   string
+		obj    any
+		fields []string
+	}{
+		{"запись в диапазоне", domain.DailyNoteFromFront{Mood: 0, SleepHours: 9.9, Load: 10}, nil},
+		{"запись вне диапазона", domain.DailyNoteFromFront{Mood: 11, SleepHours: -1, Load: -1}, []string{"mood", "sleep_hours", "load"}},
+		{"изменение без даты", domain.ChangeMoodFromFront{Mood: 5}, []string{"date"}},
+		{"изменение", domain.ChangeLoadFromFront{Date: time.Now(), Load: 3}, nil},
+		{"токен", domain.ApiTokenFromFront{Name: "grafana", Scopes: []domain.Scope{domain.ScopeNotesRead}, ExpiresInDays: 30}, nil},
+		{"токен с ошибками", domain.ApiTokenFromFront{Name: "  ", Scopes: []domain.Scope{domain.ScopeNotesRead, "notes:delete"}, ExpiresInDays: 366}, []string{"name", "scopes[1]", "expires_in_days"}},
+		{"токен без scopes", domain.ApiTokenFromFront{Name: "grafana", ExpiresInDays: 1}, []string{"scopes"}},
+		{"язык из каталога", domain.UserLanguageFromFront{Language: "en"}, nil},
+		{"сброс языка", domain.UserLanguageFromFront{}, nil},
+		{"неизвестный язык", domain.UserLanguageFromFront{Language: "xx"}, []string{"language"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			err := v.ValidateStruct(&tt.obj)
+
+			// assert
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Errorf("ошибка не ожидалась, получена - %v", err)
+				}
+				return
+			}
+			fields := fieldMessages(t, err)
+			if len(fields) != len(tt.fields) {
+				t.Errorf("ожидались ошибки полей %v, получены - %v", tt.fields, fields)
+			}
+			for _, name := range tt.fields {
+				if _, ok := fields[name]; !ok {
+					t.Errorf("ожидалась ошибка поля %v, получены - %v", name, fields)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS RateLimitBuckets;
\ No newline at end of file
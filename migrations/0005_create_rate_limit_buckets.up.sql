@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS RateLimitBuckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ratelimitbuckets_updated_at_idx ON RateLimitBuckets (updated_at);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS userevents_notify ON UserEvents;
+DROP FUNCTION IF EXISTS notify_user_event();
+DROP TABLE IF EXISTS UserEvents;
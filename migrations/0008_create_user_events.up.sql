@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS UserEvents (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES Users(id)
+);
+CREATE INDEX IF NOT EXISTS userevents_user_id_id_idx ON UserEvents (user_id, id);
+CREATE INDEX IF NOT EXISTS userevents_created_at_idx ON UserEvents (created_at);
+
+-- событие уходит во все реплики только после коммита вставки
+CREATE OR REPLACE FUNCTION notify_user_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('chopper_user_events', json_build_object(
+        'id', NEW.id,
+        'user_id', NEW.user_id,
+        'type', NEW.type,
+        'payload', NEW.payload,
+        'created_at', NEW.created_at
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER userevents_notify AFTER INSERT ON UserEvents
+    FOR EACH ROW EXECUTE FUNCTION notify_user_event();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ReminderDeliveries;
+ALTER TABLE Users DROP COLUMN IF EXISTS reminder_time;
+ALTER TABLE Users DROP COLUMN IF EXISTS time_zone;
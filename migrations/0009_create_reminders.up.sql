@@ -0,0 +1,14 @@
+ALTER TABLE Users ADD COLUMN IF NOT EXISTS time_zone TEXT;
+ALTER TABLE Users ADD COLUMN IF NOT EXISTS reminder_time TIME;
+
+-- одна строка на пользователя и его локальную дату: повторная отправка за день не нужна
+CREATE TABLE IF NOT EXISTS ReminderDeliveries (
+    user_id UUID NOT NULL,
+    date DATE NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    sent_at TIMESTAMPTZ,
+    last_error TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, date),
+    FOREIGN KEY (user_id) REFERENCES Users(id)
+);
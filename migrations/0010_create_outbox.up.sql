@@ -0,0 +1,14 @@
+-- доменные события пишутся в той же транзакции, что и изменение, и рассылаются релеем
+CREATE TABLE IF NOT EXISTS Outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    user_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    published_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON Outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_published_at_idx ON Outbox (published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,13 @@
+-- ответы на запросы с Idempotency-Key; status IS NULL - первый запрос еще выполняется
+CREATE TABLE IF NOT EXISTS IdempotencyKeys (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    status INT,
+    headers JSONB,
+    body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+CREATE INDEX IF NOT EXISTS idempotencykeys_expires_at_idx ON IdempotencyKeys (expires_at);
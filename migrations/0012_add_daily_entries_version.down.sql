@@ -0,0 +1 @@
+ALTER TABLE DailyEntries DROP COLUMN IF EXISTS version;
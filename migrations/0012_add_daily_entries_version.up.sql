@@ -0,0 +1,2 @@
+-- версия записи растет на каждое изменение, из нее строится ETag
+ALTER TABLE DailyEntries ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
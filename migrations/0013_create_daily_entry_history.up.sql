@@ -0,0 +1,16 @@
+-- каждое изменение поля записи: создание, правка или откат. Несколько строк с одной version - одно изменение
+CREATE TABLE IF NOT EXISTS DailyEntryHistory (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    version INT NOT NULL,
+    field TEXT NOT NULL,
+    old_value DOUBLE PRECISION,
+    new_value DOUBLE PRECISION NOT NULL,
+    reason TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    client_ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (entry_id) REFERENCES DailyEntries(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS dailyentryhistory_entry_id_idx ON DailyEntryHistory (entry_id, version);
@@ -0,0 +1 @@
+ALTER TABLE ReminderDeliveries DROP COLUMN IF EXISTS retry_at;
@@ -0,0 +1,2 @@
+-- неудачная доставка повторяется не раньше retry_at, а не на каждом проходе планировщика
+ALTER TABLE ReminderDeliveries ADD COLUMN IF NOT EXISTS retry_at TIMESTAMPTZ;
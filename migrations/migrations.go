@@ -0,0 +1,42 @@
+// Package migrations встраивает SQL миграции в бинарник, чтобы он не зависел от раскладки файлов на диске
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Source - драйвер golang-migrate поверх встроенных файлов
+func Source() (source.Driver, error) {
+	return iofs.New(files, ".")
+}
+
+// Latest - последняя версия среди встроенных миграций, с ней бинарник и собран
+func Latest() (uint, error) {
+	driver, err := Source()
+	if err != nil {
+		return 0, err
+	}
+	defer driver.Close()
+	version, err := driver.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := driver.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// Тест - у каждой встроенной миграции есть пара up/down, а Latest совпадает с последним файлом
+func TestEmbeddedMigrations(t *testing.T) {
+	// preparing
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		t.Fatalf("glob - %v", err)
+	}
+
+	// test
+	latest, err := Latest()
+
+	// assert
+	if err != nil {
+		t.Fatalf("ошибки не ожидалось - %v", err)
+	}
+	if len(names) == 0 || len(names)%2 != 0 {
+		t.Fatalf("ожидались пары up/down, получено - %v", names)
+	}
+	for _, name := range names {
+		pair := strings.Replace(name, ".up.sql", ".down.sql", 1)
+		if strings.HasSuffix(name, ".down.sql") {
+			pair = strings.Replace(name, ".down.sql", ".up.sql", 1)
+		}
+		if _, err := fs.Stat(files, pair); err != nil {
+			t.Errorf("нет пары для %v", name)
+		}
+	}
+	last := names[len(names)-1]
+	if !strings.HasPrefix(last, fmt.Sprintf("%04d_", latest)) {
+		t.Errorf("ожидалась версия из %v, получена - %v", last, latest)
+	}
+}